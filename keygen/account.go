@@ -0,0 +1,18 @@
+package keygen
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// GetAccount returns the account's settings, including the public keys
+// needed to verify signed license files and machine proofs at runtime
+// instead of hard-coding them.
+func (c *Client) GetAccount(ctx context.Context) (Account, error) {
+	var resp accountResponse
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/accounts/%s", c.accountID), nil, &resp); err != nil {
+		return Account{}, err
+	}
+	return resp.toAccount(), nil
+}
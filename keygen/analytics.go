@@ -0,0 +1,86 @@
+package keygen
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// GetAnalyticsCounts returns the account-wide license, machine and user
+// totals.
+func (c *Client) GetAnalyticsCounts(ctx context.Context) (AnalyticsCounts, error) {
+	var resp analyticsCountsResponse
+	path := fmt.Sprintf("/accounts/%s/analytics/actions/count", c.accountID)
+	if err := c.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return AnalyticsCounts{}, err
+	}
+	return resp.Meta, nil
+}
+
+// CountEvents returns daily counts (keyed by YYYY-MM-DD) of the given events
+// over Keygen's default window (the last 2 weeks), e.g.
+// "license.validation.succeeded" to chart validation volume.
+func (c *Client) CountEvents(ctx context.Context, events ...string) (map[string]int, error) {
+	q := url.Values{}
+	for _, e := range events {
+		q.Add("metrics[]", e)
+	}
+	var resp metricsCountResponse
+	path := fmt.Sprintf("/accounts/%s/metrics/actions/count?%s", c.accountID, q.Encode())
+	if err := c.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Meta, nil
+}
+
+// TopLicensesByVolume returns the licenses making the most API requests
+// between start and end (zero values use Keygen's default window).
+func (c *Client) TopLicensesByVolume(ctx context.Context, start, end time.Time) ([]TopEntry, error) {
+	return c.topByVolume(ctx, "top-licenses-by-volume", start, end)
+}
+
+// TopURLsByVolume returns the most requested API URLs between start and end.
+func (c *Client) TopURLsByVolume(ctx context.Context, start, end time.Time) ([]TopEntry, error) {
+	return c.topByVolume(ctx, "top-urls-by-volume", start, end)
+}
+
+// TopIPsByVolume returns the IP addresses making the most API requests
+// between start and end.
+func (c *Client) TopIPsByVolume(ctx context.Context, start, end time.Time) ([]TopEntry, error) {
+	return c.topByVolume(ctx, "top-ips-by-volume", start, end)
+}
+
+func (c *Client) topByVolume(ctx context.Context, report string, start, end time.Time) ([]TopEntry, error) {
+	q := url.Values{}
+	if !start.IsZero() {
+		q.Set("date[start]", start.UTC().Format(time.DateOnly))
+	}
+	if !end.IsZero() {
+		q.Set("date[end]", end.UTC().Format(time.DateOnly))
+	}
+	path := fmt.Sprintf("/accounts/%s/analytics/%s", c.accountID, report)
+	if len(q) > 0 {
+		path += "?" + q.Encode()
+	}
+
+	var resp topVolumeResponse
+	if err := c.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return nil, err
+	}
+	out := make([]TopEntry, 0, len(resp.Data))
+	for _, d := range resp.Data {
+		e := TopEntry{Method: d.Method, Count: d.Count}
+		switch {
+		case d.LicenseID != "":
+			e.Key = d.LicenseID
+		case d.URL != "":
+			e.Key = d.URL
+		default:
+			e.Key = d.IP
+		}
+		out = append(out, e)
+	}
+	return out, nil
+}
@@ -0,0 +1,41 @@
+package keygen
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAnalytics(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/accounts/acct/metrics/actions/count":
+			if got := r.URL.Query()["metrics[]"]; len(got) != 1 || got[0] != "license.validation.succeeded" {
+				t.Errorf("metrics = %v", got)
+			}
+			_, _ = w.Write([]byte(`{"meta":{"2024-03-01":12,"2024-03-02":7}}`))
+		case "/accounts/acct/analytics/top-urls-by-volume":
+			if got := r.URL.Query().Get("date[start]"); got != "2024-03-01" {
+				t.Errorf("date[start] = %q", got)
+			}
+			_, _ = w.Write([]byte(`{"data":[{"method":"POST","url":"/v1/accounts/acct/licenses/actions/validate-key","count":42}]}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	c := New("acct", "token", WithBaseURL(srv.URL))
+	counts, err := c.CountEvents(ctx, "license.validation.succeeded")
+	if err != nil || counts["2024-03-01"] != 12 {
+		t.Fatalf("CountEvents = %v, %v", counts, err)
+	}
+	top, err := c.TopURLsByVolume(ctx, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), time.Time{})
+	if err != nil || len(top) != 1 || top[0].Method != "POST" || top[0].Count != 42 {
+		t.Fatalf("TopURLsByVolume = %+v, %v", top, err)
+	}
+}
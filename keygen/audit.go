@@ -0,0 +1,177 @@
+package keygen
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AuditEntry records one mutating API call.
+type AuditEntry struct {
+	Time       time.Time `json:"time"`
+	Operation  string    `json:"operation"`            // e.g. "licenses.create", "machines.delete", "licenses.suspend"
+	Method     string    `json:"method"`               // HTTP method
+	Path       string    `json:"path"`                 // path relative to the account
+	ResourceID string    `json:"resourceId,omitempty"` // target ID, or the created resource's ID
+	Reason     string    `json:"reason,omitempty"`     // see ContextWithAuditReason
+	Outcome    string    `json:"outcome"`              // "success" or "failure"
+	Status     int       `json:"status,omitempty"`     // HTTP status, 0 if no response
+	Error      string    `json:"error,omitempty"`      // status or transport failure; never the response body
+	ErrorCodes []string  `json:"errorCodes,omitempty"` // JSON:API error codes, e.g. "MACHINE_LIMIT_EXCEEDED"
+	RequestID  string    `json:"requestId,omitempty"`  // Keygen X-Request-Id
+}
+
+// WithAuditLog appends one JSON line per mutating call to w. Writes are
+// serialized, so w may be a plain append-only file.
+func WithAuditLog(w io.Writer) Option {
+	var mu sync.Mutex
+	enc := json.NewEncoder(w)
+	return func(c *Client) {
+		c.audit = func(_ context.Context, e AuditEntry) {
+			mu.Lock()
+			defer mu.Unlock()
+			_ = enc.Encode(e)
+		}
+	}
+}
+
+// WithAuditLogger emits one structured record per mutating call to l.
+func WithAuditLogger(l *slog.Logger) Option {
+	return func(c *Client) {
+		c.audit = func(ctx context.Context, e AuditEntry) {
+			level := slog.LevelInfo
+			if e.Outcome != "success" {
+				level = slog.LevelWarn
+			}
+			l.LogAttrs(ctx, level, "keygen audit",
+				slog.String("operation", e.Operation),
+				slog.String("method", e.Method),
+				slog.String("path", e.Path),
+				slog.String("resource_id", e.ResourceID),
+				slog.String("reason", e.Reason),
+				slog.String("outcome", e.Outcome),
+				slog.Int("status", e.Status),
+				slog.String("error", e.Error),
+				slog.Any("error_codes", e.ErrorCodes),
+				slog.String("request_id", e.RequestID),
+			)
+		}
+	}
+}
+
+type auditReasonKey struct{}
+
+// ContextWithAuditReason attaches a caller-supplied reason (ticket, actor,
+// webhook event...) to the mutating calls made with ctx.
+func ContextWithAuditReason(ctx context.Context, reason string) context.Context {
+	return context.WithValue(ctx, auditReasonKey{}, reason)
+}
+
+// auditResponse carries what do() learned from the response.
+type auditResponse struct {
+	status    int
+	requestID string
+	body      []byte
+	cached    bool // served by WithCache without a request
+}
+
+// recordAudit emits an AuditEntry for a mutating call.
+func (c *Client) recordAudit(ctx context.Context, method, path string, r auditResponse, err error) {
+	rel := strings.TrimPrefix(path, "/accounts/"+c.accountID)
+	rel, _, _ = strings.Cut(rel, "?")
+	op, id := auditOperation(method, rel)
+	if id == "" && err == nil {
+		id = createdID(r.body)
+	}
+	reason, _ := ctx.Value(auditReasonKey{}).(string)
+
+	e := AuditEntry{
+		Time:       time.Now().UTC(),
+		Operation:  op,
+		Method:     method,
+		Path:       rel,
+		ResourceID: id,
+		Reason:     reason,
+		Outcome:    "success",
+		Status:     r.status,
+		RequestID:  r.requestID,
+	}
+	if err != nil {
+		e.Outcome = "failure"
+		// Like errorAttrs: response bodies can carry license keys and
+		// customer emails, and audit logs are kept forever.
+		var herr *HTTPError
+		var uerr *url.Error
+		switch {
+		case errors.As(err, &herr):
+			e.Status = herr.StatusCode
+			e.Error = fmt.Sprintf("HTTP %d", herr.StatusCode)
+			e.ErrorCodes = herr.Codes()
+		case errors.As(err, &uerr):
+			e.Error = uerr.Op + ": " + uerr.Err.Error()
+		default:
+			e.Error = err.Error()
+		}
+	}
+	c.audit(ctx, e)
+}
+
+// isMutation reports whether a call changes state. Validations and searches
+// are POSTs but read-only.
+func isMutation(method, path string) bool {
+	if method == http.MethodGet || method == http.MethodHead {
+		return false
+	}
+	rel, _, _ := strings.Cut(path, "?")
+	return !strings.Contains(rel, "/actions/validate") && !strings.HasSuffix(rel, "/search")
+}
+
+// auditOperation derives a stable operation name and target ID from a
+// relative path like /licenses/{id}/actions/suspend.
+func auditOperation(method, rel string) (op, id string) {
+	seg := strings.Split(strings.Trim(rel, "/"), "/")
+	resource := seg[0]
+	switch {
+	case len(seg) >= 4 && seg[2] == "actions":
+		return resource + "." + seg[3], seg[1]
+	case len(seg) >= 3 && seg[1] == "actions":
+		return resource + "." + seg[2], ""
+	case len(seg) == 1:
+		return resource + "." + verbFor(method), ""
+	default:
+		return strings.Join(append([]string{resource}, seg[2:]...), ".") + "." + verbFor(method), seg[1]
+	}
+}
+
+func verbFor(method string) string {
+	switch method {
+	case http.MethodPost:
+		return "create"
+	case http.MethodPatch, http.MethodPut:
+		return "update"
+	case http.MethodDelete:
+		return "delete"
+	}
+	return strings.ToLower(method)
+}
+
+// createdID extracts data.id from a JSON:API response body.
+func createdID(body []byte) string {
+	var doc struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if len(body) == 0 || json.Unmarshal(body, &doc) != nil {
+		return ""
+	}
+	return doc.Data.ID
+}
@@ -0,0 +1,83 @@
+package keygen
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWithAuditLog_RecordsMutations(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-1")
+		switch r.Method {
+		case http.MethodPost:
+			_, _ = w.Write([]byte(`{"data":{"id":"lic-1","attributes":{"key":"KEY"}}}`))
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			_, _ = w.Write([]byte(`{"data":[]}`))
+		}
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	c := New("acct", "token", WithBaseURL(srv.URL), WithAuditLog(&buf))
+	ctx := ContextWithAuditReason(context.Background(), "stripe evt_123")
+
+	if _, err := c.CreateLicense(ctx, "pol", LicenseMetadata{SubscriptionID: "sub"}); err != nil {
+		t.Fatalf("CreateLicense: %v", err)
+	}
+	if _, err := c.GetLicenseBySubscriptionID(ctx, "sub"); err != nil {
+		t.Fatalf("GetLicenseBySubscriptionID: %v", err)
+	}
+	if err := c.DeleteLicense(ctx, "lic-1"); err != nil {
+		t.Fatalf("DeleteLicense: %v", err)
+	}
+
+	dec := json.NewDecoder(&buf)
+	var got []AuditEntry
+	for dec.More() {
+		var e AuditEntry
+		if err := dec.Decode(&e); err != nil {
+			t.Fatalf("decode audit line: %v", err)
+		}
+		got = append(got, e)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 audit entries (reads excluded), got %+v", got)
+	}
+	if got[0].Operation != "licenses.create" || got[0].ResourceID != "lic-1" || got[0].Reason != "stripe evt_123" || got[0].RequestID != "req-1" {
+		t.Fatalf("unexpected create entry: %+v", got[0])
+	}
+	if got[1].Operation != "licenses.delete" || got[1].ResourceID != "lic-1" || got[1].Outcome != "success" {
+		t.Fatalf("unexpected delete entry: %+v", got[1])
+	}
+}
+
+func TestWithAuditLog_OmitsResponseBodies(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"errors":[{"title":"Unprocessable","detail":"key SECRET-KEY for a@b.io","code":"MACHINE_LIMIT_EXCEEDED"}]}`))
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	c := New("acct", "token", WithBaseURL(srv.URL), WithAuditLog(&buf))
+	if err := c.DeleteLicense(context.Background(), "lic-1"); err == nil {
+		t.Fatal("DeleteLicense: expected an error")
+	}
+	if strings.Contains(buf.String(), "SECRET-KEY") || strings.Contains(buf.String(), "a@b.io") {
+		t.Fatalf("audit log leaks the response body: %s", buf.String())
+	}
+	var e AuditEntry
+	if err := json.Unmarshal(buf.Bytes(), &e); err != nil {
+		t.Fatalf("decode audit line: %v", err)
+	}
+	if e.Outcome != "failure" || e.Status != 422 || e.Error != "HTTP 422" || len(e.ErrorCodes) != 1 || e.ErrorCodes[0] != "MACHINE_LIMIT_EXCEEDED" {
+		t.Fatalf("unexpected entry: %+v", e)
+	}
+}
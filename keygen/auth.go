@@ -0,0 +1,32 @@
+package keygen
+
+import "fmt"
+
+// NewWithToken creates a Client authenticating with a token of the given
+// kind, e.g. a license token handed to an edge device (see
+// CreateLicenseToken). Calls outside the token's permissions fail with
+// ErrUnauthorized. A declared license or user token also skips the WhoAmI
+// lookup behind the least-privilege options.
+func NewWithToken(accountID string, kind TokenKind, token string, opts ...Option) *Client {
+	c := New(accountID, token, opts...)
+	switch kind {
+	case TokenKindAdmin, TokenKindProduct, TokenKindEnvironment, TokenKindLicense, TokenKindUser:
+		c.tokenKind = kind
+	default:
+		if c.initErr == nil {
+			c.initErr = fmt.Errorf("keygen: unknown token kind %q", kind)
+		}
+	}
+	return c
+}
+
+// NewWithLicenseKey creates a Client authenticating with a license key
+// ("Authorization: License <key>"), so a device can validate, activate and
+// heartbeat its machines with nothing but its own key. The license's policy
+// must allow license key authentication.
+func NewWithLicenseKey(accountID, licenseKey string, opts ...Option) *Client {
+	c := New(accountID, licenseKey, opts...)
+	c.authScheme = "License"
+	c.tokenKind = TokenKindLicense
+	return c
+}
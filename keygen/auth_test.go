@@ -0,0 +1,46 @@
+package keygen
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuthModes(t *testing.T) {
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/accounts/acct/me" {
+			t.Errorf("unexpected WhoAmI lookup for a declared device credential")
+		}
+		auth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	for _, tc := range []struct {
+		c    *Client
+		want string
+	}{
+		{New("acct", "admin-tok", WithBaseURL(srv.URL)), "Bearer admin-tok"},
+		{NewWithToken("acct", TokenKindLicense, "activ-tok", WithBaseURL(srv.URL)), "Bearer activ-tok"},
+		{NewWithLicenseKey("acct", "KEY-1", WithBaseURL(srv.URL)), "License KEY-1"},
+	} {
+		if err := tc.c.DeleteMachine(context.Background(), "m1"); err != nil {
+			t.Fatalf("DeleteMachine error: %v", err)
+		}
+		if auth != tc.want {
+			t.Fatalf("Authorization = %q, want %q", auth, tc.want)
+		}
+	}
+
+	c := NewWithLicenseKey("acct", "KEY-1", WithBaseURL(srv.URL), WithLeastPrivilegeEnforced())
+	if err := c.checkDeviceScope(context.Background(), "Validate"); err != nil {
+		t.Fatalf("license key client: unexpected scope error %v", err)
+	}
+
+	c = NewWithToken("acct", "root", "tok", WithBaseURL(srv.URL))
+	if err := c.DeleteMachine(context.Background(), "m1"); err == nil {
+		t.Fatalf("expected an error for an unknown token kind")
+	}
+}
@@ -0,0 +1,77 @@
+package keygen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without contacting the API while the circuit
+// breaker set with WithCircuitBreaker is open. It counts as a transient
+// failure, so offline fallbacks and queues treat it like an outage.
+var ErrCircuitOpen = errors.New("keygen: circuit breaker open")
+
+// WithCircuitBreaker makes the client fail fast with ErrCircuitOpen after
+// threshold consecutive outage failures (5xx responses, timeouts and network
+// errors). After cooldown a single probe request is let through: success
+// closes the circuit, failure keeps it open for another cooldown. Rate
+// limiting (429) and other 4xx answers do not trip the breaker.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return func(c *Client) {
+		if threshold > 0 {
+			c.breaker = &circuitBreaker{threshold: threshold, cooldown: cooldown, now: time.Now}
+		}
+	}
+}
+
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	failures int       // consecutive outage failures
+	openedAt time.Time // zero while closed
+	probing  bool      // a half-open probe is in flight
+}
+
+// allow reports whether a request may be sent, admitting one probe once the
+// cooldown has elapsed.
+func (b *circuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.openedAt.IsZero() {
+		return nil
+	}
+	if b.probing || b.now().Sub(b.openedAt) < b.cooldown {
+		return fmt.Errorf("%w after %d consecutive failures", ErrCircuitOpen, b.failures)
+	}
+	b.probing = true
+	return nil
+}
+
+// record updates the breaker with the outcome of a request made with ctx.
+// Requests abandoned by the caller say nothing about the API's health.
+func (b *circuitBreaker) record(ctx context.Context, err error) {
+	if ctx.Err() != nil {
+		b.mu.Lock()
+		b.probing = false
+		b.mu.Unlock()
+		return
+	}
+	outage := isTransient(err) && !errors.Is(err, ErrRateLimited)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	if !outage {
+		b.failures, b.openedAt = 0, time.Time{}
+		return
+	}
+	b.failures++
+	if !b.openedAt.IsZero() || b.failures >= b.threshold {
+		b.openedAt = b.now()
+	}
+}
@@ -0,0 +1,64 @@
+package keygen
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWithCircuitBreaker(t *testing.T) {
+	status := http.StatusServiceUnavailable
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := New("acct", "token", WithBaseURL(srv.URL), WithCircuitBreaker(2, time.Minute))
+	c.breaker.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if err := c.DeleteLicense(ctx, "lic"); errors.Is(err, ErrCircuitOpen) || err == nil {
+			t.Fatalf("call %d: expected a 503, got %v", i, err)
+		}
+	}
+	err := c.DeleteLicense(ctx, "lic")
+	if !errors.Is(err, ErrCircuitOpen) || hits != 2 {
+		t.Fatalf("open circuit: got %v after %d requests", err, hits)
+	}
+	if !isTransient(err) {
+		t.Fatalf("ErrCircuitOpen should be transient")
+	}
+
+	// The probe after the cooldown fails: the circuit stays open.
+	now = now.Add(time.Minute)
+	if err := c.DeleteLicense(ctx, "lic"); errors.Is(err, ErrCircuitOpen) || hits != 3 {
+		t.Fatalf("probe: got %v after %d requests", err, hits)
+	}
+	if err := c.DeleteLicense(ctx, "lic"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("after failed probe: expected ErrCircuitOpen, got %v", err)
+	}
+
+	// A successful probe closes it again.
+	now = now.Add(time.Minute)
+	status = http.StatusNoContent
+	for i := 0; i < 2; i++ {
+		if err := c.DeleteLicense(ctx, "lic"); err != nil {
+			t.Fatalf("closed circuit: unexpected error %v", err)
+		}
+	}
+
+	// Rate limiting does not trip the breaker.
+	status = http.StatusTooManyRequests
+	for i := 0; i < 3; i++ {
+		if err := c.DeleteLicense(ctx, "lic"); errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("429 tripped the breaker")
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package keygen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// BulkLicenseResult is the outcome of one item passed to CreateLicenses.
+type BulkLicenseResult struct {
+	Index    int             // position in the input slice
+	Metadata LicenseMetadata // input metadata
+	Key      string          // created license key, when Err is nil
+	Err      error
+}
+
+// CreateLicenses creates one license per metadata entry under policyID,
+// running up to WithConcurrency requests in parallel and backing off when
+// rate limited (HTTP 429). Results are returned in input order; items not
+// attempted because ctx was cancelled carry ctx's error.
+func (c *Client) CreateLicenses(ctx context.Context, policyID string, metas []LicenseMetadata, opts ...LicenseOption) []BulkLicenseResult {
+	results := make([]BulkLicenseResult, len(metas))
+	for i, m := range metas {
+		results[i] = BulkLicenseResult{Index: i, Metadata: m}
+	}
+
+	c.forEachConcurrently(ctx, len(metas), func(i int) {
+		var key string
+		err := retryRateLimited(ctx, func(ctx context.Context) error {
+			var err error
+			key, err = c.CreateLicense(ctx, policyID, metas[i], opts...)
+			return err
+		})
+		results[i].Key, results[i].Err = key, err
+	}, func(i int, err error) {
+		results[i].Err = err
+	})
+	return results
+}
+
+// SuspendLicensesWhere suspends every license matching f that is not
+// already suspended and returns the IDs it changed, in listing order.
+// Suspensions run up to WithConcurrency at a time and back off when rate
+// limited. With dryRun set nothing is suspended and the returned IDs are
+// those that would have been. Failed suspensions do not stop the sweep; they
+// are joined into the returned error.
+func (c *Client) SuspendLicensesWhere(ctx context.Context, f LicenseFilter, dryRun bool) ([]string, error) {
+	licenses, err := c.ListLicenses(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, l := range licenses {
+		if l.Status != "SUSPENDED" {
+			ids = append(ids, l.ID)
+		}
+	}
+	if dryRun {
+		return ids, nil
+	}
+
+	errs := make([]error, len(ids))
+	c.forEachConcurrently(ctx, len(ids), func(i int) {
+		errs[i] = retryRateLimited(ctx, func(ctx context.Context) error {
+			return c.SuspendLicense(ctx, ids[i])
+		})
+	}, func(i int, err error) {
+		errs[i] = err
+	})
+
+	var changed []string
+	var failed []error
+	for i, id := range ids {
+		if errs[i] != nil {
+			failed = append(failed, fmt.Errorf("keygen: suspend %s: %w", id, errs[i]))
+			continue
+		}
+		changed = append(changed, id)
+	}
+	return changed, errors.Join(failed...)
+}
+
+// DeactivateAllMachines deletes every machine of licenseID (e.g. on
+// subscription cancellation) and returns how many were removed. Deletions
+// run up to WithConcurrency at a time and back off when rate limited;
+// failures do not stop the sweep and are joined into the returned error.
+func (c *Client) DeactivateAllMachines(ctx context.Context, licenseID string) (int, error) {
+	machines, err := c.ListMachines(ctx, licenseID)
+	if err != nil {
+		return 0, err
+	}
+
+	errs := make([]error, len(machines))
+	c.forEachConcurrently(ctx, len(machines), func(i int) {
+		errs[i] = retryRateLimited(ctx, func(ctx context.Context) error {
+			return c.DeleteMachine(ctx, machines[i].ID)
+		})
+	}, func(i int, err error) {
+		errs[i] = err
+	})
+
+	removed := 0
+	var failed []error
+	for i, m := range machines {
+		if errs[i] != nil {
+			failed = append(failed, fmt.Errorf("keygen: deactivate machine %s: %w", m.ID, errs[i]))
+			continue
+		}
+		removed++
+	}
+	return removed, errors.Join(failed...)
+}
+
+// forEachConcurrently calls fn(i) for i in [0, n) with at most c.concurrency
+// calls in flight. Indexes skipped because ctx is done are reported to skip.
+func (c *Client) forEachConcurrently(ctx context.Context, n int, fn func(i int), skip func(i int, err error)) {
+	sem := make(chan struct{}, c.concurrency)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			for ; i < n; i++ {
+				skip(i, ctx.Err())
+			}
+			wg.Wait()
+			return
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// retryRateLimited runs fn, retrying with exponential backoff (or the
+// server's Retry-After) while it fails with HTTP 429. The context passed to
+// fn carries the retry count for request logging.
+func retryRateLimited(ctx context.Context, fn func(context.Context) error) error {
+	const maxAttempts = 5
+	backoff := time.Second
+	for attempt := 1; ; attempt++ {
+		err := fn(withRetry(ctx, attempt-1))
+		var herr *HTTPError
+		if err == nil || attempt == maxAttempts || !errors.As(err, &herr) || herr.StatusCode != http.StatusTooManyRequests {
+			return err
+		}
+		wait := backoff
+		if herr.RetryAfter > 0 {
+			wait = herr.RetryAfter
+		}
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return err
+		case <-t.C:
+		}
+		if backoff < 30*time.Second {
+			backoff *= 2
+		}
+	}
+}
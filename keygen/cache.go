@@ -0,0 +1,149 @@
+package keygen
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// etagRetention is how long a response with an ETag is kept after its TTL
+// for conditional revalidation.
+const etagRetention = 24 * time.Hour
+
+// Cache stores serialized API responses for WithCache. Implementations must
+// be safe for concurrent use and may drop entries at any time.
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte, ttl time.Duration)
+}
+
+// WithCache serves repeated reads (GET requests, validations and searches)
+// from cache for ttl. Keys cover the credentials, environment and request
+// body, so clients sharing a cache never see each other's data. Any mutation
+// made through this client invalidates everything it cached before; changes
+// made elsewhere become visible after ttl or InvalidateCache.
+//
+// When Keygen sends an ETag, a GET whose entry has expired is revalidated
+// with If-None-Match and a 304 Not Modified reuses the cached body.
+func WithCache(cache Cache, ttl time.Duration) Option {
+	return func(c *Client) {
+		if cache != nil && ttl > 0 {
+			c.cache, c.cacheTTL = cache, ttl
+		}
+	}
+}
+
+// InvalidateCache drops every response cached by this client, e.g. when a
+// webhook reports a change made from the dashboard.
+func (c *Client) InvalidateCache() {
+	c.cacheGen.Add(1)
+}
+
+// cachedResponse is the value stored in the Cache.
+type cachedResponse struct {
+	Header http.Header `json:"header"`
+	Body   []byte      `json:"body"`
+	Stored time.Time   `json:"stored"`
+}
+
+// cacheable reports whether a request may be answered from the cache.
+// Requests with extra headers (e.g. for signed artifact URLs) never are.
+func (c *Client) cacheable(method, path string, hdr http.Header) bool {
+	return c.cache != nil && hdr == nil && !isMutation(method, path)
+}
+
+// cacheKey hashes everything that determines the response; the generation
+// makes entries stored before the latest mutation unreachable.
+func (c *Client) cacheKey(method, path, token string, body []byte) string {
+	h := sha256.New()
+	for _, s := range []string{
+		strconv.FormatUint(c.cacheGen.Load(), 10),
+		c.baseURL, c.environment, c.apiVersion, c.authScheme, token, method, path,
+	} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// cacheGet returns the entry stored under key and whether it is still
+// fresh. Stale entries are only kept when they can be revalidated.
+func (c *Client) cacheGet(key string) (r cachedResponse, fresh, ok bool) {
+	b, ok := c.cache.Get(key)
+	if !ok {
+		return cachedResponse{}, false, false
+	}
+	if err := json.Unmarshal(b, &r); err != nil {
+		return cachedResponse{}, false, false
+	}
+	return r, time.Since(r.Stored) <= c.cacheTTL, true
+}
+
+// cacheSet stores a response; entries with an ETag outlive their TTL so they
+// can be revalidated with If-None-Match.
+func (c *Client) cacheSet(key string, h http.Header, body []byte) {
+	b, err := json.Marshal(cachedResponse{Header: h, Body: body, Stored: time.Now()})
+	if err != nil {
+		return
+	}
+	ttl := c.cacheTTL
+	if h.Get("ETag") != "" && ttl < etagRetention {
+		ttl = etagRetention
+	}
+	c.cache.Set(key, b, ttl)
+}
+
+// MemoryCache is an in-process Cache. Expired entries are evicted lazily.
+type MemoryCache struct {
+	now func() time.Time
+
+	mu        sync.Mutex
+	entries   map[string]memoryEntry
+	nextSweep int
+}
+
+type memoryEntry struct {
+	value   []byte
+	expires time.Time
+}
+
+// NewMemoryCache returns an empty MemoryCache.
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{now: time.Now, entries: map[string]memoryEntry{}, nextSweep: 64}
+}
+
+// Get returns the value stored under key, unless it has expired.
+func (m *MemoryCache) Get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if m.now().After(e.expires) {
+		delete(m.entries, key)
+		return nil, false
+	}
+	return e.value, true
+}
+
+// Set stores value under key for ttl.
+func (m *MemoryCache) Set(key string, value []byte, ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	if len(m.entries) >= m.nextSweep {
+		for k, e := range m.entries {
+			if now.After(e.expires) {
+				delete(m.entries, k)
+			}
+		}
+		m.nextSweep = 2*len(m.entries) + 64
+	}
+	m.entries[key] = memoryEntry{value: value, expires: now.Add(ttl)}
+}
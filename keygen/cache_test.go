@@ -0,0 +1,106 @@
+package keygen
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWithCache(t *testing.T) {
+	hits := map[string]int{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits[r.Method+" "+r.URL.Path]++
+		switch r.Method + " " + r.URL.Path {
+		case "GET /accounts/acct/licenses/lic":
+			_, _ = w.Write([]byte(`{"data":{"id":"lic","attributes":{"key":"K","status":"ACTIVE"}}}`))
+		case "POST /accounts/acct/licenses/actions/validate-key":
+			_, _ = w.Write([]byte(`{"data":{"id":"lic"},"meta":{"valid":true,"code":"VALID"}}`))
+		case "POST /accounts/acct/licenses/lic/actions/suspend":
+			_, _ = w.Write([]byte(`{"data":{"id":"lic","attributes":{"status":"SUSPENDED"}}}`))
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	cache := NewMemoryCache()
+	c := New("acct", "token", WithBaseURL(srv.URL), WithCache(cache, time.Minute))
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		if l, err := c.GetLicense(ctx, "lic"); err != nil || l.ID != "lic" {
+			t.Fatalf("GetLicense = %+v, %v", l, err)
+		}
+		if v, err := c.Validate(ctx, "K", "fp-1"); err != nil || !v.Valid {
+			t.Fatalf("Validate = %+v, %v", v, err)
+		}
+	}
+	if _, err := c.Validate(ctx, "K", "fp-2"); err != nil {
+		t.Fatalf("Validate error: %v", err)
+	}
+	if hits["GET /accounts/acct/licenses/lic"] != 1 || hits["POST /accounts/acct/licenses/actions/validate-key"] != 2 {
+		t.Fatalf("requests reached the API: %v", hits)
+	}
+
+	// Another token must not read this client's entries.
+	other := New("acct", "other", WithBaseURL(srv.URL), WithCache(cache, time.Minute))
+	if _, err := other.GetLicense(ctx, "lic"); err != nil {
+		t.Fatalf("GetLicense error: %v", err)
+	}
+	if hits["GET /accounts/acct/licenses/lic"] != 2 {
+		t.Fatalf("cache shared across credentials: %v", hits)
+	}
+
+	// A mutation invalidates the client's entries.
+	if err := c.SuspendLicense(ctx, "lic"); err != nil {
+		t.Fatalf("SuspendLicense error: %v", err)
+	}
+	if _, err := c.GetLicense(ctx, "lic"); err != nil {
+		t.Fatalf("GetLicense error: %v", err)
+	}
+	if hits["GET /accounts/acct/licenses/lic"] != 3 {
+		t.Fatalf("cache not invalidated by a mutation: %v", hits)
+	}
+}
+
+func TestMemoryCache(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := NewMemoryCache()
+	m.now = func() time.Time { return now }
+	m.Set("k", []byte("v"), time.Minute)
+	if v, ok := m.Get("k"); !ok || string(v) != "v" {
+		t.Fatalf("Get = %q, %v", v, ok)
+	}
+	now = now.Add(2 * time.Minute)
+	if _, ok := m.Get("k"); ok {
+		t.Fatalf("expired entry returned")
+	}
+}
+
+func TestWithCache_ETag(t *testing.T) {
+	var full, notModified int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `W/"v1"`)
+		if r.Header.Get("If-None-Match") == `W/"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		full++
+		_, _ = w.Write([]byte(`{"data":{"id":"lic","attributes":{"key":"K","status":"ACTIVE"}}}`))
+	}))
+	defer srv.Close()
+
+	// Entries go stale immediately, so every call revalidates.
+	c := New("acct", "token", WithBaseURL(srv.URL), WithCache(NewMemoryCache(), time.Nanosecond))
+	for i := 0; i < 3; i++ {
+		l, err := c.GetLicense(context.Background(), "lic")
+		if err != nil || l.ID != "lic" || l.Status != "ACTIVE" {
+			t.Fatalf("GetLicense = %+v, %v", l, err)
+		}
+	}
+	if full != 1 || notModified != 2 {
+		t.Fatalf("got %d full responses and %d 304s, want 1 and 2", full, notModified)
+	}
+}
@@ -3,22 +3,66 @@ package keygen
 import (
 	"bytes"
 	"context"
+	"crypto/ed25519"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // Client is a thin Keygen API wrapper.
 type Client struct {
 	accountID          string
 	apiToken           string
+	authScheme         string    // "Bearer" for tokens, "License" for license keys
+	tokenKind          TokenKind // declared by NewWithToken/NewWithLicenseKey
+	credentials        CredentialProvider
+	breaker            *circuitBreaker
+	cache              Cache
+	cacheTTL           time.Duration
+	cacheGen           atomic.Uint64
 	baseURL            string
 	http               *http.Client
+	transport          http.RoundTripper
+	proxyURL           string
+	tlsConfig          *tls.Config
+	pins               []string
 	defaultMachineName string
 	defaultPlatform    string
+	metadataValidators []func(map[string]any) error
+	strictTLS          bool
+	verifyDigest       bool
+	scope              scopeGuard
+	audit              func(context.Context, AuditEntry)
+	concurrency        int
+	environment        string
+	userAgent          string
+	apiVersion         string
+	artifactKey        ed25519.PublicKey
+	responseKey        ed25519.PublicKey
+	responseTolerance  time.Duration
+	limiter            RateLimiter
+	requestHooks       []func(*http.Request)
+	responseHooks      []func(*http.Response, time.Duration)
+	logger             *slog.Logger
+	timeout            time.Duration
+
+	rateMu    sync.Mutex
+	rateLimit RateLimit
+
+	// initErr is a configuration error detected by New; it is returned by
+	// every call instead of sending a request.
+	initErr error
 }
 
 // Option configures the Client.
@@ -29,6 +73,12 @@ func WithHTTPClient(h *http.Client) Option {
 	return func(c *Client) { c.http = h }
 }
 
+// WithTransport sets the RoundTripper used for API calls, keeping the rest
+// of the http.Client (default or set with WithHTTPClient) unchanged.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(c *Client) { c.transport = rt }
+}
+
 // WithBaseURL overrides the API base URL (default: https://api.keygen.sh/v1).
 func WithBaseURL(u string) Option {
 	return func(c *Client) { c.baseURL = u }
@@ -46,26 +96,112 @@ func WithDefaultMachine(name, platform string) Option {
 	}
 }
 
-// New creates a new Client.
+// WithConcurrency bounds the parallel requests made by bulk helpers such as
+// CreateLicenses (default: 4).
+func WithConcurrency(n int) Option {
+	return func(c *Client) {
+		if n > 0 {
+			c.concurrency = n
+		}
+	}
+}
+
+// WithEnvironment targets a Keygen environment (by ID or code, e.g.
+// "sandbox") by sending the Keygen-Environment header on every request.
+func WithEnvironment(env string) Option {
+	return func(c *Client) { c.environment = env }
+}
+
+// New creates a new Client authenticating with an admin, environment or
+// product token. See NewWithToken and NewWithLicenseKey for device
+// credentials.
 func New(accountID, apiToken string, opts ...Option) *Client {
 	c := &Client{
 		accountID:          accountID,
 		apiToken:           apiToken,
+		authScheme:         "Bearer",
 		baseURL:            "https://api.keygen.sh/v1",
-		http:               http.DefaultClient,
+		http:               defaultHTTPClient(),
 		defaultMachineName: "dappnode",
 		defaultPlatform:    "linux",
+		concurrency:        4,
+		timeout:            defaultTimeout,
+		responseTolerance:  defaultResponseTolerance,
+		userAgent:          defaultUserAgent,
 	}
 	for _, opt := range opts {
 		opt(c)
 	}
+	if c.transport != nil {
+		h := *c.http // never mutate a caller's client
+		h.Transport = c.transport
+		c.http = &h
+	}
+	if err := c.configureTransport(); err != nil {
+		c.initErr = err
+	} else if c.strictTLS {
+		c.initErr = c.applyTLSPolicy()
+	}
 	return c
 }
 
 // --- Licenses ---
 
+// LicenseOption customizes CreateLicense.
+type LicenseOption func(*licenseCreateAttributes)
+
+// WithExpiry overrides the policy's default expiry (e.g. for custom trials).
+func WithExpiry(t time.Time) LicenseOption {
+	return func(a *licenseCreateAttributes) {
+		s := t.UTC().Format(time.RFC3339)
+		a.Expiry = &s
+	}
+}
+
+// WithKey sets an explicit license key instead of a generated one (e.g. to
+// preserve keys migrated from a legacy system). The policy must allow it.
+func WithKey(key string) LicenseOption {
+	return func(a *licenseCreateAttributes) { a.Key = key }
+}
+
+// WithName sets the license's human-readable name.
+func WithName(name string) LicenseOption {
+	return func(a *licenseCreateAttributes) { a.Name = name }
+}
+
+// WithProtected marks the license as protected (only admins may change it).
+func WithProtected(protected bool) LicenseOption {
+	return func(a *licenseCreateAttributes) { a.Protected = &protected }
+}
+
+// WithPermissions sets the license's permissions (e.g. "license.validate",
+// "machine.read"), which also bound the tokens minted from it. Useful for
+// read-only licenses handed to monitoring agents.
+func WithPermissions(permissions ...string) LicenseOption {
+	return func(a *licenseCreateAttributes) { a.Permissions = permissions }
+}
+
 // CreateLicense creates a new license under a policy, returning its key.
-func (c *Client) CreateLicense(ctx context.Context, policyID string, meta LicenseMetadata) (string, error) {
+func (c *Client) CreateLicense(ctx context.Context, policyID string, meta LicenseMetadata, opts ...LicenseOption) (string, error) {
+	created, err := c.CreateLicenseFull(ctx, policyID, meta, opts...)
+	if err != nil {
+		return "", err
+	}
+	return created.Key, nil
+}
+
+// CreateLicenseFull is like CreateLicense but also returns the new license's
+// ID, expiry and status, saving a ResolveLicenseID round trip.
+func (c *Client) CreateLicenseFull(ctx context.Context, policyID string, meta LicenseMetadata, opts ...LicenseOption) (CreatedLicense, error) {
+	return c.createLicense(ctx, policyID, meta, opts...)
+}
+
+// createLicense backs CreateLicenseFull; meta may also be a map[string]any
+// so that arbitrary metadata can be carried over (see CloneLicense).
+func (c *Client) createLicense(ctx context.Context, policyID string, meta any, opts ...LicenseOption) (CreatedLicense, error) {
+	if err := c.validateMetadata(meta); err != nil {
+		return CreatedLicense{}, err
+	}
 	path := fmt.Sprintf("/accounts/%s/licenses", c.accountID)
 	req := licenseCreateRequest{
 		Data: licenseCreateData{
@@ -81,15 +217,83 @@ func (c *Client) CreateLicense(ctx context.Context, policyID string, meta Licens
 		},
 	}
 
-	var resp licenseCreateResponse
+	for _, opt := range opts {
+		opt(&req.Data.Attributes)
+	}
+
+	var resp licenseResponse
 	if err := c.do(ctx, http.MethodPost, path, req, &resp); err != nil {
-		return "", err
+		return CreatedLicense{}, err
 	}
-	key := resp.Data.Attributes.Key
-	if key == "" {
-		return "", fmt.Errorf("keygen: license creation returned empty key")
+	a := resp.Data.Attributes
+	if a.Key == "" {
+		return CreatedLicense{}, fmt.Errorf("keygen: license creation returned empty key")
 	}
-	return key, nil
+	return CreatedLicense{ID: resp.Data.ID, Key: a.Key, Expiry: a.Expiry, Status: a.Status}, nil
+}
+
+// CloneOptions controls CloneLicense.
+type CloneOptions struct {
+	// CopyMachines re-activates the source license's machines on the clone.
+	// The source activations are left in place.
+	CopyMachines bool
+	// LicenseOptions are applied when creating the clone, after the name
+	// has been copied from the source.
+	LicenseOptions []LicenseOption
+}
+
+// CloneLicense creates a license under targetPolicyID with the same name,
+// metadata and entitlements as licenseID, e.g. when splitting a policy into
+// regional ones. If a step fails after the clone was created, the clone is
+// returned together with the error so the caller can retry or delete it.
+func (c *Client) CloneLicense(ctx context.Context, licenseID, targetPolicyID string, opts CloneOptions) (CreatedLicense, error) {
+	src, err := c.GetLicense(ctx, licenseID)
+	if err != nil {
+		return CreatedLicense{}, err
+	}
+	ents, err := c.ListLicenseEntitlements(ctx, licenseID)
+	if err != nil {
+		return CreatedLicense{}, err
+	}
+	var machines []Machine
+	if opts.CopyMachines {
+		if machines, err = c.ListMachines(ctx, licenseID); err != nil {
+			return CreatedLicense{}, err
+		}
+	}
+
+	var lopts []LicenseOption
+	if src.Name != "" {
+		lopts = append(lopts, WithName(src.Name))
+	}
+	lopts = append(lopts, opts.LicenseOptions...)
+	meta := src.Metadata
+	if meta == nil {
+		meta = map[string]any{}
+	}
+	clone, err := c.createLicense(ctx, targetPolicyID, meta, lopts...)
+	if err != nil {
+		return CreatedLicense{}, err
+	}
+
+	if len(ents) > 0 {
+		ids := make([]string, len(ents))
+		for i, e := range ents {
+			ids[i] = e.ID
+		}
+		if err := c.AttachEntitlements(ctx, clone.ID, ids); err != nil {
+			return clone, fmt.Errorf("keygen: clone license %s: copy entitlements: %w", licenseID, err)
+		}
+	}
+	for _, m := range machines {
+		// The clone's ID is known: activating by ID skips the key lookup,
+		// which would count as a validation of the clone.
+		mopts := []MachineOption{WithHostname(m.Hostname), WithIP(m.IP), WithCores(m.Cores), WithMachineMetadata(m.Metadata)}
+		if _, err := c.activateMachine(ctx, clone.ID, m.Fingerprint, m.Name, m.Platform, mopts); err != nil {
+			return clone, fmt.Errorf("keygen: clone license %s: copy machine %s: %w", licenseID, m.ID, err)
+		}
+	}
+	return clone, nil
 }
 
 // DeleteLicense deletes a license by ID (204 on success).
@@ -98,6 +302,82 @@ func (c *Client) DeleteLicense(ctx context.Context, licenseID string) error {
 	return c.do(ctx, http.MethodDelete, path, nil, nil)
 }
 
+// GetLicense returns the full license by ID.
+func (c *Client) GetLicense(ctx context.Context, licenseID string) (License, error) {
+	path := fmt.Sprintf("/accounts/%s/licenses/%s", c.accountID, licenseID)
+
+	var resp licenseResponse
+	if err := c.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return License{}, err
+	}
+	return resp.Data.toLicense(), nil
+}
+
+// GetLicenseByKey looks a license up by key with a plain GET, unlike
+// ResolveLicenseID it does not count as a validation event.
+func (c *Client) GetLicenseByKey(ctx context.Context, licenseKey string) (License, error) {
+	path := fmt.Sprintf("/accounts/%s/licenses/%s", c.accountID, url.PathEscape(licenseKey))
+
+	var resp licenseResponse
+	if err := c.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return License{}, err
+	}
+	return resp.Data.toLicense(), nil
+}
+
+// UpdateLicenseMetadata replaces a license's metadata (e.g. a new
+// customerEmail or subscriptionId) in place, keeping its machines.
+// Keygen replaces the whole object, so pass every key that should remain.
+func (c *Client) UpdateLicenseMetadata(ctx context.Context, licenseID string, meta map[string]any) error {
+	if meta == nil {
+		meta = map[string]any{}
+	}
+	if err := c.validateMetadata(meta); err != nil {
+		return err
+	}
+	req := licenseUpdateRequest{
+		Data: licenseUpdateData{
+			Type:       "licenses",
+			Attributes: licenseUpdateAttributes{Metadata: &meta},
+		},
+	}
+	path := fmt.Sprintf("/accounts/%s/licenses/%s", c.accountID, licenseID)
+	return c.do(ctx, http.MethodPatch, path, req, nil)
+}
+
+// UpdateLicense PATCHes the attributes set in u (e.g. flipping protected or
+// raising maxMachines for an enterprise deal) and returns the updated license.
+func (c *Client) UpdateLicense(ctx context.Context, licenseID string, u LicenseUpdate) (License, error) {
+	attrs := licenseUpdateAttributes{
+		Name:        u.Name,
+		Protected:   u.Protected,
+		MaxMachines: u.MaxMachines,
+		MaxUses:     u.MaxUses,
+		MaxCores:    u.MaxCores,
+		Permissions: listOrNil(u.Permissions),
+	}
+	if u.Expiry != nil {
+		s := u.Expiry.UTC().Format(time.RFC3339)
+		attrs.Expiry = &s
+	}
+	if u.Metadata != nil {
+		if err := c.validateMetadata(u.Metadata); err != nil {
+			return License{}, err
+		}
+		attrs.Metadata = &u.Metadata
+	}
+	req := licenseUpdateRequest{
+		Data: licenseUpdateData{Type: "licenses", Attributes: attrs},
+	}
+	path := fmt.Sprintf("/accounts/%s/licenses/%s", c.accountID, licenseID)
+
+	var resp licenseResponse
+	if err := c.do(ctx, http.MethodPatch, path, req, &resp); err != nil {
+		return License{}, err
+	}
+	return resp.Data.toLicense(), nil
+}
+
 // GetLicenseBySubscriptionID returns the license ID for a metadata[subscriptionId].
 func (c *Client) GetLicenseBySubscriptionID(ctx context.Context, subscriptionID string) (string, error) {
 	q := url.Values{}
@@ -121,43 +401,99 @@ func (c *Client) GetLicenseBySubscriptionID(ctx context.Context, subscriptionID
 	return resp.Data[0].ID, nil
 }
 
-// ListLicensesByPolicy returns a rich view (ID, Key*, Status*, Metadata).
+// ListLicensesBySubscriptionID returns every license whose
+// metadata[subscriptionId] matches, so callers can disambiguate duplicates
+// themselves (GetLicenseBySubscriptionID errors out on them).
+func (c *Client) ListLicensesBySubscriptionID(ctx context.Context, subscriptionID string) ([]LicenseSummary, error) {
+	return c.ListLicenses(ctx, LicenseFilter{Metadata: map[string]string{"subscriptionId": subscriptionID}})
+}
+
+// FindLicensesByMetadata returns licenses whose metadata matches every
+// key/value pair in meta (e.g. orderId, resellerId).
+func (c *Client) FindLicensesByMetadata(ctx context.Context, meta map[string]string) ([]LicenseSummary, error) {
+	return c.ListLicenses(ctx, LicenseFilter{Metadata: meta})
+}
+
+// ListLicensesByGroup returns the licenses belonging to a group.
+func (c *Client) ListLicensesByGroup(ctx context.Context, groupID string) ([]LicenseSummary, error) {
+	return c.ListLicenses(ctx, LicenseFilter{Group: groupID})
+}
+
+// ListLicenses returns licenses matching f, filtered server-side.
 // Key/Status may be empty when the API/resource view omits them.
-func (c *Client) ListLicensesByPolicy(ctx context.Context, policyID string) ([]LicenseSummary, error) {
+// If ctx is cancelled mid-pagination, the licenses fetched so far are
+// returned together with a *PartialResultError.
+func (c *Client) ListLicenses(ctx context.Context, f LicenseFilter) ([]LicenseSummary, error) {
 	var out []LicenseSummary
-	page := 1
-
-	for {
-		q := url.Values{}
-		q.Set("policy", policyID) // <-- FIXED: use correct query param
-		q.Set("page[number]", strconv.Itoa(page))
-		q.Set("page[size]", "100")
-		path := fmt.Sprintf("/accounts/%s/licenses?%s", c.accountID, q.Encode())
-
-		var resp listLicensesByPolicyResponse
-		if err := c.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
-			return nil, err
-		}
-		for _, d := range resp.Data {
-			out = append(out, LicenseSummary{
-				ID:       d.ID,
-				Key:      d.Attributes.Key,
-				Status:   d.Attributes.Status,
-				Metadata: d.Attributes.Metadata,
-			})
+	err := c.ForEachLicense(ctx, f, func(l LicenseSummary) error {
+		out = append(out, l)
+		return nil
+	})
+	var partial *PartialResultError
+	if err != nil && !errors.As(err, &partial) {
+		return nil, err
+	}
+	return out, err
+}
+
+// ForEachLicense calls fn for every license matching f, fetching pages
+// lazily so that large accounts can be walked without holding every
+// LicenseSummary in memory. Returning an error from fn stops the walk and
+// ForEachLicense returns that error. Cancellation after the first page is
+// reported as a *PartialResultError, with fn already called for the
+// licenses of the completed pages.
+func (c *Client) ForEachLicense(ctx context.Context, f LicenseFilter, fn func(LicenseSummary) error) error {
+	return forEachPage(ctx, c, "/licenses", f.values(), func(page []licenseResource) error {
+		for _, r := range page {
+			if err := fn(r.toSummary()); err != nil {
+				return err
+			}
 		}
-		if resp.Links.Next == nil {
-			break
+		return nil
+	})
+}
+
+// CountLicenses returns how many licenses match f without downloading them.
+func (c *Client) CountLicenses(ctx context.Context, f LicenseFilter) (int, error) {
+	return c.count(ctx, "/licenses", f.values())
+}
+
+// ListExpiringLicenses returns the licenses (with their metadata, e.g. the
+// customer email) that expire within the given window, soonest first.
+// Partial results are propagated like in ListLicenses.
+func (c *Client) ListExpiringLicenses(ctx context.Context, within time.Duration) ([]License, error) {
+	f := LicenseFilter{ExpiresWithin: within}
+	all, err := listAll(ctx, c, "/licenses", f.values(), licenseResource.toLicense)
+	var partial *PartialResultError
+	if err != nil && !errors.As(err, &partial) {
+		return nil, err
+	}
+
+	out := all[:0]
+	for _, l := range all {
+		if l.Expiry != nil {
+			out = append(out, l)
 		}
-		page++
 	}
-	return out, nil
+	slices.SortStableFunc(out, func(a, b License) int {
+		return a.Expiry.Compare(*b.Expiry)
+	})
+	return out, err
+}
+
+// ListLicensesByPolicy returns a rich view (ID, Key*, Status*, Metadata).
+// Key/Status may be empty when the API/resource view omits them.
+// Partial results are propagated like in ListLicenses.
+func (c *Client) ListLicensesByPolicy(ctx context.Context, policyID string) ([]LicenseSummary, error) {
+	return c.ListLicenses(ctx, LicenseFilter{Policy: policyID})
 }
 
 // ListLicenseKeysByPolicy is a convenience wrapper returning only keys.
+// Partial results are propagated like in ListLicensesByPolicy.
 func (c *Client) ListLicenseKeysByPolicy(ctx context.Context, policyID string) ([]string, error) {
 	items, err := c.ListLicensesByPolicy(ctx, policyID)
-	if err != nil {
+	var partial *PartialResultError
+	if err != nil && !errors.As(err, &partial) {
 		return nil, err
 	}
 	keys := make([]string, 0, len(items))
@@ -166,18 +502,306 @@ func (c *Client) ListLicenseKeysByPolicy(ctx context.Context, policyID string) (
 			keys = append(keys, it.Key)
 		}
 	}
-	return keys, nil
+	return keys, err
+}
+
+// RenewLicense extends a license's expiry by its policy duration and returns
+// the new expiry. Machine activations are preserved.
+func (c *Client) RenewLicense(ctx context.Context, licenseID string) (time.Time, error) {
+	var resp licenseResponse
+	if err := c.licenseAction(ctx, licenseID, "renew", nil, &resp); err != nil {
+		return time.Time{}, err
+	}
+	if resp.Data.Attributes.Expiry == nil {
+		return time.Time{}, fmt.Errorf("keygen: renewed license %s has no expiry", licenseID)
+	}
+	return *resp.Data.Attributes.Expiry, nil
+}
+
+// ExtendLicenseExpiry pushes a license's expiry d into the future, counting
+// from the current expiry or from now if the license has already expired.
+// The PATCH carries If-Match with the ETag of the license it was computed
+// from, and the read/compute/patch cycle is retried when the license changed
+// in between (412 Precondition Failed), so concurrent extensions are not
+// lost. If the API sends no ETag the update is unconditional and can race.
+// Licenses without an expiry cannot be extended.
+func (c *Client) ExtendLicenseExpiry(ctx context.Context, licenseID string, d time.Duration) (time.Time, error) {
+	const maxAttempts = 3
+	path := fmt.Sprintf("/accounts/%s/licenses/%s", c.accountID, licenseID)
+
+	for attempt := 1; ; attempt++ {
+		ctx := withRetry(ctx, attempt-1)
+		// An extra header keeps the read out of WithCache, whose ETag may be
+		// stale.
+		var resp etaggedLicenseResponse
+		if err := c.doHeader(ctx, http.MethodGet, path, http.Header{"Cache-Control": {"no-cache"}}, nil, &resp); err != nil {
+			return time.Time{}, err
+		}
+		lic := resp.Data.toLicense()
+		if lic.Expiry == nil {
+			return time.Time{}, fmt.Errorf("keygen: license %s has no expiry to extend", licenseID)
+		}
+		base := *lic.Expiry
+		if now := time.Now(); base.Before(now) {
+			base = now
+		}
+		expiry := base.Add(d).UTC().Truncate(time.Second)
+		s := expiry.Format(time.RFC3339)
+
+		req := licenseUpdateRequest{
+			Data: licenseUpdateData{
+				Type:       "licenses",
+				Attributes: licenseUpdateAttributes{Expiry: &s},
+			},
+		}
+		var hdr http.Header
+		if resp.etag != "" {
+			hdr = http.Header{"If-Match": {resp.etag}}
+		}
+		err := c.doHeader(ctx, http.MethodPatch, path, hdr, req, nil)
+		var herr *HTTPError
+		if attempt < maxAttempts && errors.As(err, &herr) && herr.StatusCode == http.StatusPreconditionFailed {
+			continue
+		}
+		if err != nil {
+			return time.Time{}, err
+		}
+		return expiry, nil
+	}
+}
+
+// SuspendLicense suspends a license, pausing access without deleting it or
+// its machines.
+func (c *Client) SuspendLicense(ctx context.Context, licenseID string) error {
+	return c.licenseAction(ctx, licenseID, "suspend", nil, nil)
+}
+
+// ReinstateLicense reinstates a previously suspended license.
+func (c *Client) ReinstateLicense(ctx context.Context, licenseID string) error {
+	return c.licenseAction(ctx, licenseID, "reinstate", nil, nil)
+}
+
+// RevokeLicense revokes a license through Keygen's revoke action so its key
+// can no longer be used. Prefer it over DeleteLicense when the revocation
+// needs to show up as such in the event log (e.g. for chargeback disputes).
+func (c *Client) RevokeLicense(ctx context.Context, licenseID string) error {
+	path := fmt.Sprintf("/accounts/%s/licenses/%s/actions/revoke", c.accountID, licenseID)
+	return c.do(ctx, http.MethodDelete, path, nil, nil)
+}
+
+// ChangeLicensePolicy moves a license to another policy (tier upgrade or
+// downgrade) without recreating it, so machine activations are kept.
+func (c *Client) ChangeLicensePolicy(ctx context.Context, licenseID, newPolicyID string) error {
+	req := licenseRelationship{Data: relationshipData{Type: "policies", ID: newPolicyID}}
+	path := fmt.Sprintf("/accounts/%s/licenses/%s/policy", c.accountID, licenseID)
+	return c.do(ctx, http.MethodPut, path, req, nil)
+}
+
+// ListLicenseEntitlements lists the entitlements attached to a license.
+func (c *Client) ListLicenseEntitlements(ctx context.Context, licenseID string) ([]Entitlement, error) {
+	return listAll(ctx, c, "/licenses/"+licenseID+"/entitlements", nil, entitlementResource.toEntitlement)
+}
+
+// AttachEntitlements attaches entitlements (by ID) to a license.
+func (c *Client) AttachEntitlements(ctx context.Context, licenseID string, entitlementIDs []string) error {
+	path := fmt.Sprintf("/accounts/%s/licenses/%s/entitlements", c.accountID, licenseID)
+	return c.do(ctx, http.MethodPost, path, newRelationshipList("entitlements", entitlementIDs), nil)
+}
+
+// DetachEntitlements detaches entitlements (by ID) from a license.
+func (c *Client) DetachEntitlements(ctx context.Context, licenseID string, entitlementIDs []string) error {
+	path := fmt.Sprintf("/accounts/%s/licenses/%s/entitlements", c.accountID, licenseID)
+	return c.do(ctx, http.MethodDelete, path, newRelationshipList("entitlements", entitlementIDs), nil)
+}
+
+// ListLicenseUsers lists the users attached to a license.
+func (c *Client) ListLicenseUsers(ctx context.Context, licenseID string) ([]User, error) {
+	return listAll(ctx, c, "/licenses/"+licenseID+"/users", nil, userResource.toUser)
+}
+
+// AttachLicenseUsers attaches users (by ID) to a license.
+func (c *Client) AttachLicenseUsers(ctx context.Context, licenseID string, userIDs []string) error {
+	path := fmt.Sprintf("/accounts/%s/licenses/%s/users", c.accountID, licenseID)
+	return c.do(ctx, http.MethodPost, path, newRelationshipList("users", userIDs), nil)
+}
+
+// DetachLicenseUsers detaches users (by ID) from a license.
+func (c *Client) DetachLicenseUsers(ctx context.Context, licenseID string, userIDs []string) error {
+	path := fmt.Sprintf("/accounts/%s/licenses/%s/users", c.accountID, licenseID)
+	return c.do(ctx, http.MethodDelete, path, newRelationshipList("users", userIDs), nil)
+}
+
+// TransferLicenseOwner re-homes a license to another user without
+// recreating it.
+func (c *Client) TransferLicenseOwner(ctx context.Context, licenseID, newUserID string) error {
+	req := optionalRelationship{Data: &relationshipData{Type: "users", ID: newUserID}}
+	path := fmt.Sprintf("/accounts/%s/licenses/%s/owner", c.accountID, licenseID)
+	return c.do(ctx, http.MethodPut, path, req, nil)
+}
+
+// SetLicenseGroup moves a license into a group (e.g. a reseller).
+func (c *Client) SetLicenseGroup(ctx context.Context, licenseID, groupID string) error {
+	req := optionalRelationship{Data: &relationshipData{Type: "groups", ID: groupID}}
+	path := fmt.Sprintf("/accounts/%s/licenses/%s/group", c.accountID, licenseID)
+	return c.do(ctx, http.MethodPut, path, req, nil)
+}
+
+// ClearLicenseGroup removes a license from its group.
+func (c *Client) ClearLicenseGroup(ctx context.Context, licenseID string) error {
+	path := fmt.Sprintf("/accounts/%s/licenses/%s/group", c.accountID, licenseID)
+	return c.do(ctx, http.MethodPut, path, optionalRelationship{}, nil)
+}
+
+// CreateLicenseToken mints a license-scoped activation token, so devices can
+// activate machines without holding the account-wide API token.
+func (c *Client) CreateLicenseToken(ctx context.Context, licenseID string, opts LicenseTokenOptions) (Token, error) {
+	return c.createToken(ctx, "/licenses/"+licenseID+"/tokens", opts)
+}
+
+// IncrementLicenseUsage increments a license's uses counter by n (n <= 0
+// increments by 1). Fails once the policy's maxUses is reached.
+func (c *Client) IncrementLicenseUsage(ctx context.Context, licenseID string, n int) error {
+	req := licenseUsageRequest{}
+	if n > 0 {
+		req.Meta.Increment = n
+	}
+	return c.licenseAction(ctx, licenseID, "increment-usage", req, nil)
+}
+
+// DecrementLicenseUsage decrements a license's uses counter by n (n <= 0
+// decrements by 1).
+func (c *Client) DecrementLicenseUsage(ctx context.Context, licenseID string, n int) error {
+	req := licenseUsageRequest{}
+	if n > 0 {
+		req.Meta.Decrement = n
+	}
+	return c.licenseAction(ctx, licenseID, "decrement-usage", req, nil)
+}
+
+// ResetLicenseUsage resets a license's uses counter to 0.
+func (c *Client) ResetLicenseUsage(ctx context.Context, licenseID string) error {
+	return c.licenseAction(ctx, licenseID, "reset-usage", nil, nil)
+}
+
+// CheckoutLicense checks out a signed license file for licenseID, so
+// air-gapped devices can validate the license offline until it expires.
+func (c *Client) CheckoutLicense(ctx context.Context, licenseID string, opts CheckoutOptions) (LicenseFile, error) {
+	var resp certificateFileResponse
+	if err := c.licenseAction(ctx, licenseID, "check-out"+opts.query(), nil, &resp); err != nil {
+		return LicenseFile{}, err
+	}
+	return resp.toLicenseFile(), nil
+}
+
+// licenseAction POSTs to /licenses/{id}/actions/{action}.
+func (c *Client) licenseAction(ctx context.Context, licenseID, action string, in, out any) error {
+	path := fmt.Sprintf("/accounts/%s/licenses/%s/actions/%s", c.accountID, licenseID, action)
+	return c.do(ctx, http.MethodPost, path, in, out)
 }
 
 // --- Machines ---
 
+// MachineOption customizes ActivateMachine.
+type MachineOption func(*machineData)
+
+// WithHostname records the machine's hostname.
+func WithHostname(hostname string) MachineOption {
+	return func(d *machineData) { d.Attributes.Hostname = hostname }
+}
+
+// WithIP records the machine's IP address.
+func WithIP(ip string) MachineOption {
+	return func(d *machineData) { d.Attributes.IP = ip }
+}
+
+// WithCores records the machine's CPU core count, which counts towards the
+// policy's maxCores.
+func WithCores(cores int) MachineOption {
+	return func(d *machineData) { d.Attributes.Cores = cores }
+}
+
+// WithMachineMetadata attaches arbitrary metadata (e.g. hardware model or
+// dappnode version) to the machine.
+func WithMachineMetadata(meta map[string]any) MachineOption {
+	return func(d *machineData) { d.Attributes.Metadata = meta }
+}
+
+// WithMachineOwner sets the machine's owner, for policies that limit
+// machines per user.
+func WithMachineOwner(userID string) MachineOption {
+	return func(d *machineData) {
+		d.Relationships.Owner = &optionalRelationship{Data: &relationshipData{Type: "users", ID: userID}}
+	}
+}
+
 // ActivateMachine creates a machine bound to the license (by key).
 // name/platform default to the client defaults if empty.
-func (c *Client) ActivateMachine(ctx context.Context, licenseKey, fingerprint, name, platform string) error {
+func (c *Client) ActivateMachine(ctx context.Context, licenseKey, fingerprint, name, platform string, opts ...MachineOption) error {
+	if err := c.checkDeviceScope(ctx, "ActivateMachine"); err != nil {
+		return err
+	}
 	licenseID, err := c.ResolveLicenseID(ctx, licenseKey)
 	if err != nil {
 		return err
 	}
+	_, err = c.activateMachine(ctx, licenseID, fingerprint, name, platform, opts)
+	return err
+}
+
+// EnsureMachineActivated makes sure fingerprint is activated on the license:
+// it is a no-op when a machine with that fingerprint already exists, and
+// activates one otherwise. With opts.RotateOldest, hitting the license's
+// machine limit deactivates its oldest machine and retries once.
+func (c *Client) EnsureMachineActivated(ctx context.Context, licenseKey, fingerprint string, opts EnsureMachineOptions) (EnsureMachineResult, error) {
+	if err := c.checkDeviceScope(ctx, "EnsureMachineActivated"); err != nil {
+		return EnsureMachineResult{}, err
+	}
+	licenseID, err := c.ResolveLicenseID(ctx, licenseKey)
+	if err != nil {
+		return EnsureMachineResult{}, err
+	}
+	machines, err := c.ListMachines(ctx, licenseID)
+	if err != nil {
+		return EnsureMachineResult{}, err
+	}
+	for _, m := range machines {
+		if m.Fingerprint == fingerprint {
+			return EnsureMachineResult{Machine: m}, nil
+		}
+	}
+
+	ctx, key := idempotencyKeyOr(ctx)
+	m, err := c.activateMachine(ctx, licenseID, fingerprint, opts.Name, opts.Platform, opts.Options)
+	if err == nil {
+		return EnsureMachineResult{Machine: m, Activated: true}, nil
+	}
+	if !opts.RotateOldest || !errors.Is(err, ErrMachineLimitExceeded) || len(machines) == 0 {
+		return EnsureMachineResult{}, err
+	}
+
+	oldest := machines[0]
+	for _, m := range machines[1:] {
+		if m.Created.Before(oldest.Created) {
+			oldest = m
+		}
+	}
+	if err := c.DeleteMachine(ContextWithIdempotencyKey(ctx, key+":rotate"), oldest.ID); err != nil {
+		return EnsureMachineResult{}, fmt.Errorf("keygen: rotate machine %s: %w", oldest.ID, err)
+	}
+	// The retry is a new request: under the first key it would replay the 422.
+	res := EnsureMachineResult{Rotated: &oldest}
+	retryCtx := ContextWithIdempotencyKey(ctx, key+":retry")
+	if res.Machine, err = c.activateMachine(retryCtx, licenseID, fingerprint, opts.Name, opts.Platform, opts.Options); err != nil {
+		return res, err
+	}
+	res.Activated = true
+	return res, nil
+}
+
+// activateMachine creates a machine bound to licenseID under ctx's
+// idempotency key, or a fresh one if the caller set none.
+func (c *Client) activateMachine(ctx context.Context, licenseID, fingerprint, name, platform string, opts []MachineOption) (Machine, error) {
+	ctx, _ = idempotencyKeyOr(ctx)
 	if name == "" {
 		name = c.defaultMachineName
 	}
@@ -200,12 +824,22 @@ func (c *Client) ActivateMachine(ctx context.Context, licenseKey, fingerprint, n
 			},
 		},
 	}
-	return c.do(ctx, http.MethodPost, fmt.Sprintf("/accounts/%s/machines", c.accountID), req, &machineResponse{})
+	for _, opt := range opts {
+		opt(&req.Data)
+	}
+	var resp machineResponse
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("/accounts/%s/machines", c.accountID), req, &resp); err != nil {
+		return Machine{}, err
+	}
+	return resp.Data.toMachine(), nil
 }
 
 // DeactivateMachine deletes a machine (by matching fingerprint) from the license.
 // Returns (found, error). When found==false and err==nil, no machine matched.
 func (c *Client) DeactivateMachine(ctx context.Context, licenseKey, fingerprint string) (bool, error) {
+	if err := c.checkDeviceScope(ctx, "DeactivateMachine"); err != nil {
+		return false, err
+	}
 	licenseID, err := c.ResolveLicenseID(ctx, licenseKey)
 	if err != nil {
 		return false, err
@@ -218,82 +852,195 @@ func (c *Client) DeactivateMachine(ctx context.Context, licenseKey, fingerprint
 
 	for _, m := range list {
 		if m.Fingerprint == fingerprint {
-			return true, c.do(ctx, http.MethodDelete,
-				fmt.Sprintf("/accounts/%s/machines/%s", c.accountID, m.ID), nil, nil)
+			return true, c.DeleteMachine(ctx, m.ID)
 		}
 	}
 	return false, nil
 }
 
-// ListMachines lists machines for a license by licenseID.
-// If no machines exist, returns an empty slice.
-func (c *Client) ListMachines(ctx context.Context, licenseID string) ([]Machine, error) {
-	q := url.Values{}
-	page := 1
-	q.Set("license", licenseID) // <-- FIXED: use correct query param
-	q.Set("page[number]", strconv.Itoa(page))
-	q.Set("page[size]", "100")
-	path := fmt.Sprintf("/accounts/%s/machines?%s", c.accountID, q.Encode())
+// DeleteMachine deactivates a machine by ID, skipping the key resolution and
+// machine listing DeactivateMachine performs.
+func (c *Client) DeleteMachine(ctx context.Context, machineID string) error {
+	path := fmt.Sprintf("/accounts/%s/machines/%s", c.accountID, machineID)
+	return c.do(ctx, http.MethodDelete, path, nil, nil)
+}
 
-	var resp machinesListResponse
-	if err := c.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
-		return nil, err
+// UpdateMachine PATCHes the attributes set in u (e.g. a new hostname after
+// a hardware swap) without deactivating the machine, and returns the updated
+// machine.
+func (c *Client) UpdateMachine(ctx context.Context, machineID string, u MachineUpdate) (Machine, error) {
+	var req machineUpdateRequest
+	req.Data.Type = "machines"
+	req.Data.Attributes = machineUpdateAttributes{
+		Name:     u.Name,
+		Platform: u.Platform,
+		Hostname: u.Hostname,
+		IP:       u.IP,
+		Cores:    u.Cores,
+	}
+	if u.Metadata != nil {
+		req.Data.Attributes.Metadata = &u.Metadata
+	}
+	path := fmt.Sprintf("/accounts/%s/machines/%s", c.accountID, machineID)
+
+	var resp machineResponse
+	if err := c.do(ctx, http.MethodPatch, path, req, &resp); err != nil {
+		return Machine{}, err
 	}
-	out := make([]Machine, 0, len(resp.Data))
-	for _, d := range resp.Data {
-		out = append(out, Machine{
-			ID:          d.ID,
-			Fingerprint: d.Attributes.Fingerprint,
-			Platform:    d.Attributes.Platform,
-			Name:        d.Attributes.Name,
-		})
+	return resp.Data.toMachine(), nil
+}
+
+// pingMachineHeartbeat sends a heartbeat ping for a machine.
+func (c *Client) pingMachineHeartbeat(ctx context.Context, machineID string) error {
+	if err := c.checkDeviceScope(ctx, "PingHeartbeat"); err != nil {
+		return err
 	}
-	return out, nil
+	return c.machineAction(ctx, machineID, "ping", nil, nil)
+}
+
+// ResetMachineHeartbeat resets a machine's heartbeat monitor, clearing a
+// dead status so the machine can start pinging again (e.g. after support
+// re-homes a device).
+func (c *Client) ResetMachineHeartbeat(ctx context.Context, machineID string) error {
+	return c.machineAction(ctx, machineID, "reset", nil, nil)
+}
+
+// ChangeMachineOwner assigns a machine to another user, e.g. when a customer
+// re-subscribes under a different account.
+func (c *Client) ChangeMachineOwner(ctx context.Context, machineID, newUserID string) error {
+	req := optionalRelationship{Data: &relationshipData{Type: "users", ID: newUserID}}
+	path := fmt.Sprintf("/accounts/%s/machines/%s/owner", c.accountID, machineID)
+	return c.do(ctx, http.MethodPut, path, req, nil)
+}
+
+// machineAction POSTs to /machines/{id}/actions/{action}.
+func (c *Client) machineAction(ctx context.Context, machineID, action string, in, out any) error {
+	path := fmt.Sprintf("/accounts/%s/machines/%s/actions/%s", c.accountID, machineID, action)
+	return c.do(ctx, http.MethodPost, path, in, out)
+}
+
+// ListMachines lists machines for a license by licenseID, following
+// pagination so floating licenses with more than 100 machines are complete.
+// If no machines exist, returns an empty slice. If ctx is cancelled
+// mid-pagination, the machines fetched so far are returned together with a
+// *PartialResultError.
+func (c *Client) ListMachines(ctx context.Context, licenseID string) ([]Machine, error) {
+	q := url.Values{}
+	q.Set("license", licenseID)
+	out, err := listAll(ctx, c, "/machines", q, machineData.toMachine)
+	if out == nil && err == nil {
+		out = []Machine{}
+	}
+	return out, err
 }
 
 // ListAllMachines lists all machines for the account.
+// If ctx is cancelled mid-pagination, the machines fetched so far are
+// returned together with a *PartialResultError.
 func (c *Client) ListAllMachines(ctx context.Context) ([]Machine, error) {
-	var out []Machine
-	page := 1
+	return c.ListMachinesFiltered(ctx, MachineFilter{})
+}
 
-	for {
-		q := url.Values{}
-		q.Set("page[number]", strconv.Itoa(page))
-		q.Set("page[size]", "100")
-		path := fmt.Sprintf("/accounts/%s/machines?%s", c.accountID, q.Encode())
+// ListMachinesFiltered lists the machines matching f, filtering server-side
+// instead of downloading the whole fleet. Pagination behaves like
+// ListAllMachines.
+func (c *Client) ListMachinesFiltered(ctx context.Context, f MachineFilter) ([]Machine, error) {
+	return listAll(ctx, c, "/machines", f.values(), machineData.toMachine)
+}
 
-		var resp machinesListResponse
-		if err := c.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
-			return nil, err
-		}
-		for _, d := range resp.Data {
-			out = append(out, Machine{
-				ID:          d.ID,
-				LicenseId:   d.Relationships.License.Data.ID,
-				Fingerprint: d.Attributes.Fingerprint,
-				Platform:    d.Attributes.Platform,
-				Name:        d.Attributes.Name,
-			})
+// ListDeadMachines returns the machines whose heartbeat is DEAD and whose
+// last ping (or creation, if they never pinged) is older than olderThan, so
+// cleanup jobs can cull zombie activations. Each result carries LicenseId.
+// If ctx is cancelled mid-pagination, the dead machines among the pages
+// fetched so far are returned together with a *PartialResultError.
+func (c *Client) ListDeadMachines(ctx context.Context, olderThan time.Duration) ([]Machine, error) {
+	machines, err := c.ListMachinesFiltered(ctx, MachineFilter{HeartbeatStatus: "DEAD"})
+	var partial *PartialResultError
+	if err != nil && !errors.As(err, &partial) {
+		return nil, err
+	}
+	cutoff := time.Now().Add(-olderThan)
+	var out []Machine
+	for _, m := range machines {
+		last := m.Created
+		if m.LastHeartbeat != nil {
+			last = *m.LastHeartbeat
 		}
-		// Get out of the loop if no more pages
-		if resp.Links.Next == nil {
-			break
+		if last.Before(cutoff) {
+			out = append(out, m)
 		}
-		page++
 	}
-	return out, nil
+	return out, err
+}
+
+// SearchMachinesByFingerprint returns the machines whose fingerprint
+// contains fragment (at least 3 characters), so operators can look a device
+// up from a truncated fingerprint. For an exact match use
+// ListMachinesFiltered with MachineFilter.Fingerprint.
+func (c *Client) SearchMachinesByFingerprint(ctx context.Context, fragment string) ([]Machine, error) {
+	if len(fragment) < 3 {
+		return nil, fmt.Errorf("keygen: fingerprint search needs at least 3 characters")
+	}
+	return searchAll(ctx, c, "machines", map[string]string{"fingerprint": fragment}, machineData.toMachine)
+}
+
+// CountMachines returns how many machines match f without downloading them.
+func (c *Client) CountMachines(ctx context.Context, f MachineFilter) (int, error) {
+	return c.count(ctx, "/machines", f.values())
+}
+
+// CheckoutMachine checks out a signed machine file for machineID, so
+// air-gapped devices can prove their activation offline until it expires.
+func (c *Client) CheckoutMachine(ctx context.Context, machineID string, opts MachineCheckoutOptions) (MachineFile, error) {
+	path := fmt.Sprintf("/accounts/%s/machines/%s/actions/check-out", c.accountID, machineID) + opts.query()
+
+	var resp certificateFileResponse
+	if err := c.do(ctx, http.MethodPost, path, nil, &resp); err != nil {
+		return MachineFile{}, err
+	}
+	return resp.toMachineFile(), nil
+}
+
+// query encodes opts as a check-out query string ("" when all are zero).
+func (opts CheckoutOptions) query() string {
+	q := url.Values{}
+	if opts.TTL > 0 {
+		q.Set("ttl", strconv.Itoa(int(opts.TTL/time.Second)))
+	}
+	if len(opts.Include) > 0 {
+		q.Set("include", strings.Join(opts.Include, ","))
+	}
+	if opts.Encrypt {
+		q.Set("encrypt", "true")
+	}
+	if len(q) == 0 {
+		return ""
+	}
+	return "?" + q.Encode()
 }
 
 // --- Validation ---
 
 // Validate checks a key within a fingerprint scope.
 func (c *Client) Validate(ctx context.Context, licenseKey, fingerprint string) (LicenseValidation, error) {
+	return c.validateScoped(ctx, "Validate", licenseKey, ValidationScope{Fingerprint: fingerprint})
+}
+
+// ValidateScoped checks a key within scope, e.g. requiring a product and a
+// set of entitlements in one call.
+func (c *Client) ValidateScoped(ctx context.Context, licenseKey string, scope ValidationScope) (LicenseValidation, error) {
+	return c.validateScoped(ctx, "ValidateScoped", licenseKey, scope)
+}
+
+func (c *Client) validateScoped(ctx context.Context, op, licenseKey string, scope ValidationScope) (LicenseValidation, error) {
+	if err := c.checkDeviceScope(ctx, op); err != nil {
+		return LicenseValidation{}, err
+	}
 	req := validateLicenseRequest{
 		Meta: validateMeta{
-			Key: licenseKey,
-			Scope: fingerprintScope{
-				Fingerprint: fingerprint,
-			},
+			Key:   licenseKey,
+			Nonce: scope.Nonce,
+			Scope: newValidationScope(scope),
 		},
 	}
 
@@ -303,17 +1050,51 @@ func (c *Client) Validate(ctx context.Context, licenseKey, fingerprint string) (
 		req, &resp); err != nil {
 		return LicenseValidation{}, err
 	}
+	return checkNonce(resp.toValidation(), scope.Nonce)
+}
 
-	return LicenseValidation{
-		Key:         resp.Data.Attributes.Key,
-		Expiry:      resp.Data.Attributes.Expiry,
-		Status:      resp.Data.Attributes.Status,
-		Valid:       resp.Meta.Valid,
-		Code:        resp.Meta.Code,
-		Detail:      resp.Meta.Detail,
-		Timestamp:   resp.Meta.Timestamp,
-		Fingerprint: resp.Meta.Scope.Fingerprint,
-	}, nil
+// ValidateOrActivate validates licenseKey for fingerprint and, when the only
+// problem is that the machine is not activated (NO_MACHINE, NO_MACHINES or
+// FINGERPRINT_SCOPE_MISMATCH), activates it as EnsureMachineActivated does
+// and validates again. Other failures are returned as is in the result.
+func (c *Client) ValidateOrActivate(ctx context.Context, licenseKey, fingerprint string, opts EnsureMachineOptions) (ValidateOrActivateResult, error) {
+	val, err := c.Validate(ctx, licenseKey, fingerprint)
+	if err != nil || !val.NeedsActivation() {
+		return ValidateOrActivateResult{Validation: val}, err
+	}
+	ensured, err := c.EnsureMachineActivated(ctx, licenseKey, fingerprint, opts)
+	res := ValidateOrActivateResult{Validation: val, Rotated: ensured.Rotated}
+	if err != nil {
+		return res, err
+	}
+	res.Machine, res.Activated = &ensured.Machine, ensured.Activated
+	if res.Validation, err = c.Validate(ctx, licenseKey, fingerprint); err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+// ValidateLicenseByID validates a license by ID within scope using the
+// authenticated validate action, so backends need not store license keys.
+func (c *Client) ValidateLicenseByID(ctx context.Context, licenseID string, scope ValidationScope) (LicenseValidation, error) {
+	var req validateByIDRequest
+	req.Meta.Nonce = scope.Nonce
+	req.Meta.Scope = newValidationScope(scope)
+
+	var resp licenseValidationResponse
+	if err := c.licenseAction(ctx, licenseID, "validate", req, &resp); err != nil {
+		return LicenseValidation{}, err
+	}
+	return checkNonce(resp.toValidation(), scope.Nonce)
+}
+
+// checkNonce rejects a validation that does not echo the nonce sent with the
+// request, e.g. a replayed response.
+func checkNonce(val LicenseValidation, nonce int64) (LicenseValidation, error) {
+	if nonce != 0 && val.Nonce != nonce {
+		return LicenseValidation{}, fmt.Errorf("keygen: validation nonce mismatch: sent %d, got %d", nonce, val.Nonce)
+	}
+	return val, nil
 }
 
 // ResolveLicenseID gets the license ID from a key using validate-key.
@@ -336,16 +1117,80 @@ func (c *Client) ResolveLicenseID(ctx context.Context, licenseKey string) (strin
 // --- HTTP plumbing ---
 
 func (c *Client) do(ctx context.Context, method, path string, in any, out any) error {
-	var body io.Reader
+	return c.doHeader(ctx, method, path, nil, in, out)
+}
+
+// headerReceiver is implemented by response types that keep response
+// headers (e.g. the Keygen-Signature of a validation).
+type headerReceiver interface {
+	setHeader(http.Header)
+}
+
+// doHeader is do with extra request headers (e.g. Prefer: no-redirect).
+func (c *Client) doHeader(ctx context.Context, method, path string, hdr http.Header, in any, out any) (err error) {
+	if c.initErr != nil {
+		return c.initErr
+	}
+
+	var ar auditResponse
+	if c.audit != nil && isMutation(method, path) {
+		defer func() { c.recordAudit(ctx, method, path, ar, err) }()
+	}
+	if c.logger != nil {
+		start := time.Now()
+		defer func() { c.logRequest(ctx, method, path, ar, time.Since(start), err) }()
+	}
+
+	rctx, cancel := c.requestContext(ctx)
+	defer cancel()
+
+	token := c.apiToken
+	if c.credentials != nil {
+		if token, err = c.credentials.Token(rctx); err != nil {
+			return fmt.Errorf("keygen: credentials: %w", err)
+		}
+	}
+
+	var reqBody []byte
 	if in != nil {
-		var buf bytes.Buffer
-		if err := json.NewEncoder(&buf).Encode(in); err != nil {
+		if reqBody, err = json.Marshal(in); err != nil {
 			return fmt.Errorf("keygen: encode request: %w", err)
 		}
-		body = &buf
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	var cacheKey string
+	var stale *cachedResponse // expired entry to revalidate with If-None-Match
+	if c.cacheable(method, path, hdr) {
+		cacheKey = c.cacheKey(method, path, token, reqBody)
+		r, fresh, ok := c.cacheGet(cacheKey)
+		switch {
+		case ok && fresh:
+			ar.status, ar.cached = http.StatusOK, true
+			return decodeResponse(r.Header, r.Body, out)
+		case ok && method == http.MethodGet && r.Header.Get("ETag") != "":
+			stale = &r
+		}
+	}
+
+	if c.breaker != nil {
+		if err := c.breaker.allow(); err != nil {
+			return err
+		}
+		defer func() { c.breaker.record(ctx, err) }()
+	}
+
+	if c.limiter != nil {
+		if err := c.limiter.Wait(rctx); err != nil {
+			return fmt.Errorf("keygen: rate limiter: %w", err)
+		}
+	}
+
+	var body io.Reader
+	if in != nil {
+		body = bytes.NewReader(reqBody)
+	}
+
+	req, err := http.NewRequestWithContext(rctx, method, c.baseURL+path, body)
 	if err != nil {
 		return fmt.Errorf("keygen: new request: %w", err)
 	}
@@ -353,29 +1198,96 @@ func (c *Client) do(ctx context.Context, method, path string, in any, out any) e
 		req.Header.Set("Content-Type", "application/vnd.api+json")
 	}
 	req.Header.Set("Accept", "application/vnd.api+json")
-	req.Header.Set("Authorization", "Bearer "+c.apiToken)
+	req.Header.Set("Authorization", c.authScheme+" "+token)
+	req.Header.Set("User-Agent", c.userAgent)
+	if c.apiVersion != "" {
+		req.Header.Set("Keygen-Version", c.apiVersion)
+	}
+	if c.environment != "" {
+		req.Header.Set("Keygen-Environment", c.environment)
+	}
+	if key := idempotencyKeyFrom(ctx); key != "" && isMutation(method, path) {
+		req.Header.Set("Idempotency-Key", key)
+	}
+	for k, v := range hdr {
+		req.Header[k] = v
+	}
+	if stale != nil {
+		req.Header.Set("If-None-Match", stale.Header.Get("ETag"))
+	}
+	for _, hook := range c.requestHooks {
+		hook(req)
+	}
 
+	start := time.Now()
 	resp, err := c.http.Do(req)
+	if c.cache != nil && isMutation(method, path) {
+		c.InvalidateCache() // even failed mutations may have been applied
+	}
 	if err != nil {
 		return fmt.Errorf("keygen: do request: %w", err)
 	}
 	defer resp.Body.Close()
+	for _, hook := range c.responseHooks {
+		hook(resp, time.Since(start))
+	}
+	ar.status = resp.StatusCode
+	ar.requestID = resp.Header.Get("X-Request-Id")
+	c.recordRateLimit(resp.Header, time.Now())
 
-	// Non-2xx => return body as plain error string (no custom types)
+	if resp.StatusCode == http.StatusNotModified && stale != nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		ar.cached = true
+		c.cacheSet(cacheKey, stale.Header, stale.Body)
+		return decodeResponse(stale.Header, stale.Body, out)
+	}
+
+	// Non-2xx => return the raw body in an *HTTPError
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		b, _ := io.ReadAll(resp.Body)
-		if len(b) == 0 {
-			return fmt.Errorf("keygen: %s %s -> HTTP %d", method, path, resp.StatusCode)
+		return &HTTPError{
+			Method:     method,
+			Path:       path,
+			StatusCode: resp.StatusCode,
+			Body:       string(b),
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+			Errors:     parseAPIErrors(string(b)),
+		}
+	}
+
+	// Read the whole body (this also drains it for keep-alives).
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("keygen: read response: %w", err)
+	}
+	ar.body = b
+	if c.verifyDigest {
+		if err := verifyDigest(resp.Header.Get("Digest"), b); err != nil {
+			return err
+		}
+	}
+	if c.responseKey != nil {
+		if reason := verifyResponseSignature(c.responseKey, req, resp, b, time.Now(), c.responseTolerance); reason != "" {
+			return &ResponseSignatureError{Method: method, Path: path, RequestID: ar.requestID, Reason: reason}
 		}
-		return fmt.Errorf("keygen: %s %s -> HTTP %d: %s", method, path, resp.StatusCode, string(b))
 	}
 
-	if out == nil {
-		// Drain for keep-alives anyway
-		io.Copy(io.Discard, resp.Body)
+	if cacheKey != "" {
+		c.cacheSet(cacheKey, resp.Header, b)
+	}
+	return decodeResponse(resp.Header, b, out)
+}
+
+// decodeResponse hands a successful response to out.
+func decodeResponse(h http.Header, b []byte, out any) error {
+	if hr, ok := out.(headerReceiver); ok {
+		hr.setHeader(h)
+	}
+	// 204 No Content (e.g. no upgrade available) leaves out untouched.
+	if out == nil || len(b) == 0 {
 		return nil
 	}
-	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+	if err := json.Unmarshal(b, out); err != nil {
 		return fmt.Errorf("keygen: decode response: %w", err)
 	}
 	return nil
@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"testing"
+	"time"
 )
 
 func getTestClient() *Client {
@@ -29,6 +30,80 @@ func TestCreateLicense(t *testing.T) {
 	t.Logf("CreateLicense result: %v", result)
 }
 
+func TestCreateLicenseFull(t *testing.T) {
+	ctx := context.Background()
+	client := getTestClient()
+	policyID := os.Getenv("KEYGEN_POLICY_ID")
+	meta := LicenseMetadata{
+		SubscriptionID: os.Getenv("KEYGEN_SUBSCRIPTION_ID"),
+		CustomerEmail:  os.Getenv("KEYGEN_CUSTOMER_EMAIL"),
+	}
+	result, err := client.CreateLicenseFull(ctx, policyID, meta)
+	if err != nil {
+		t.Fatalf("CreateLicenseFull error: %v", err)
+	}
+	t.Logf("CreateLicenseFull result: %+v", result)
+}
+
+func TestCreateLicenseWithExpiry(t *testing.T) {
+	ctx := context.Background()
+	client := getTestClient()
+	policyID := os.Getenv("KEYGEN_POLICY_ID")
+	meta := LicenseMetadata{
+		SubscriptionID: os.Getenv("KEYGEN_SUBSCRIPTION_ID"),
+		CustomerEmail:  os.Getenv("KEYGEN_CUSTOMER_EMAIL"),
+	}
+	result, err := client.CreateLicense(ctx, policyID, meta, WithExpiry(time.Now().Add(14*24*time.Hour)))
+	if err != nil {
+		t.Fatalf("CreateLicense error: %v", err)
+	}
+	t.Logf("CreateLicense result: %v", result)
+}
+
+func TestCreateLicenses(t *testing.T) {
+	ctx := context.Background()
+	client := getTestClient()
+	policyID := os.Getenv("KEYGEN_POLICY_ID")
+	email := os.Getenv("KEYGEN_CUSTOMER_EMAIL")
+	metas := []LicenseMetadata{
+		{SubscriptionID: "bulk-1", CustomerEmail: email},
+		{SubscriptionID: "bulk-2", CustomerEmail: email},
+	}
+	for _, r := range client.CreateLicenses(ctx, policyID, metas) {
+		if r.Err != nil {
+			t.Fatalf("CreateLicenses item %d error: %v", r.Index, r.Err)
+		}
+		t.Logf("CreateLicenses item %d key: %s", r.Index, r.Key)
+	}
+}
+
+func TestCreateLicenseWithPermissions(t *testing.T) {
+	ctx := context.Background()
+	client := getTestClient()
+	policyID := os.Getenv("KEYGEN_POLICY_ID")
+	meta := LicenseMetadata{
+		SubscriptionID: os.Getenv("KEYGEN_SUBSCRIPTION_ID"),
+		CustomerEmail:  os.Getenv("KEYGEN_CUSTOMER_EMAIL"),
+	}
+	result, err := client.CreateLicense(ctx, policyID, meta, WithPermissions("license.read", "license.validate"))
+	if err != nil {
+		t.Fatalf("CreateLicense error: %v", err)
+	}
+	t.Logf("CreateLicense result: %v", result)
+}
+
+func TestCloneLicense(t *testing.T) {
+	ctx := context.Background()
+	client := getTestClient()
+	licenseID := os.Getenv("KEYGEN_LICENSE_ID")
+	policyID := os.Getenv("KEYGEN_POLICY_ID")
+	result, err := client.CloneLicense(ctx, licenseID, policyID, CloneOptions{})
+	if err != nil {
+		t.Fatalf("CloneLicense error: %v", err)
+	}
+	t.Logf("CloneLicense result: %+v", result)
+}
+
 func TestDeleteLicense(t *testing.T) {
 	ctx := context.Background()
 	client := getTestClient()
@@ -40,6 +115,149 @@ func TestDeleteLicense(t *testing.T) {
 	t.Log("DeleteLicense succeeded")
 }
 
+func TestGetLicense(t *testing.T) {
+	ctx := context.Background()
+	client := getTestClient()
+	licenseID := os.Getenv("KEYGEN_LICENSE_ID")
+	result, err := client.GetLicense(ctx, licenseID)
+	if err != nil {
+		t.Fatalf("GetLicense error: %v", err)
+	}
+	t.Logf("GetLicense result: %+v", result)
+}
+
+func TestGetLicenseByKey(t *testing.T) {
+	ctx := context.Background()
+	client := getTestClient()
+	licenseKey := os.Getenv("KEYGEN_LICENSE_KEY")
+	result, err := client.GetLicenseByKey(ctx, licenseKey)
+	if err != nil {
+		t.Fatalf("GetLicenseByKey error: %v", err)
+	}
+	t.Logf("GetLicenseByKey result: %+v", result)
+}
+
+func TestUpdateLicenseMetadata(t *testing.T) {
+	ctx := context.Background()
+	client := getTestClient()
+	licenseID := os.Getenv("KEYGEN_LICENSE_ID")
+	meta := map[string]any{
+		"subscriptionId": os.Getenv("KEYGEN_SUBSCRIPTION_ID"),
+		"customerEmail":  os.Getenv("KEYGEN_CUSTOMER_EMAIL"),
+	}
+	if err := client.UpdateLicenseMetadata(ctx, licenseID, meta); err != nil {
+		t.Fatalf("UpdateLicenseMetadata error: %v", err)
+	}
+	t.Log("UpdateLicenseMetadata succeeded")
+}
+
+func TestUpdateLicense(t *testing.T) {
+	ctx := context.Background()
+	client := getTestClient()
+	licenseID := os.Getenv("KEYGEN_LICENSE_ID")
+	protected := true
+	result, err := client.UpdateLicense(ctx, licenseID, LicenseUpdate{Protected: &protected})
+	if err != nil {
+		t.Fatalf("UpdateLicense error: %v", err)
+	}
+	t.Logf("UpdateLicense result: %+v", result)
+}
+
+func TestChangeLicensePolicy(t *testing.T) {
+	ctx := context.Background()
+	client := getTestClient()
+	licenseID := os.Getenv("KEYGEN_LICENSE_ID")
+	policyID := os.Getenv("KEYGEN_POLICY_ID2")
+	if err := client.ChangeLicensePolicy(ctx, licenseID, policyID); err != nil {
+		t.Fatalf("ChangeLicensePolicy error: %v", err)
+	}
+	t.Log("ChangeLicensePolicy succeeded")
+}
+
+func TestLicenseUsage(t *testing.T) {
+	ctx := context.Background()
+	client := getTestClient()
+	licenseID := os.Getenv("KEYGEN_LICENSE_ID")
+	if err := client.IncrementLicenseUsage(ctx, licenseID, 2); err != nil {
+		t.Fatalf("IncrementLicenseUsage error: %v", err)
+	}
+	if err := client.DecrementLicenseUsage(ctx, licenseID, 1); err != nil {
+		t.Fatalf("DecrementLicenseUsage error: %v", err)
+	}
+	if err := client.ResetLicenseUsage(ctx, licenseID); err != nil {
+		t.Fatalf("ResetLicenseUsage error: %v", err)
+	}
+	t.Log("license usage actions succeeded")
+}
+
+func TestSuspendLicensesWhere(t *testing.T) {
+	ctx := context.Background()
+	client := getTestClient()
+	result, err := client.SuspendLicensesWhere(ctx, LicenseFilter{Policy: os.Getenv("KEYGEN_POLICY_ID")}, true)
+	if err != nil {
+		t.Fatalf("SuspendLicensesWhere error: %v", err)
+	}
+	t.Logf("SuspendLicensesWhere result: %v", result)
+}
+
+func TestRevokeLicense(t *testing.T) {
+	ctx := context.Background()
+	client := getTestClient()
+	licenseID := os.Getenv("KEYGEN_LICENSE_ID")
+	if err := client.RevokeLicense(ctx, licenseID); err != nil {
+		t.Fatalf("RevokeLicense error: %v", err)
+	}
+	t.Log("RevokeLicense succeeded")
+}
+
+func TestListLicenseEntitlements(t *testing.T) {
+	ctx := context.Background()
+	client := getTestClient()
+	licenseID := os.Getenv("KEYGEN_LICENSE_ID")
+	result, err := client.ListLicenseEntitlements(ctx, licenseID)
+	if err != nil {
+		t.Fatalf("ListLicenseEntitlements error: %v", err)
+	}
+	t.Logf("ListLicenseEntitlements result: %+v", result)
+}
+
+func TestListLicenseUsers(t *testing.T) {
+	ctx := context.Background()
+	client := getTestClient()
+	licenseID := os.Getenv("KEYGEN_LICENSE_ID")
+	result, err := client.ListLicenseUsers(ctx, licenseID)
+	if err != nil {
+		t.Fatalf("ListLicenseUsers error: %v", err)
+	}
+	t.Logf("ListLicenseUsers result: %+v", result)
+}
+
+func TestTransferLicenseOwner(t *testing.T) {
+	ctx := context.Background()
+	client := getTestClient()
+	licenseID := os.Getenv("KEYGEN_LICENSE_ID")
+	userID := os.Getenv("KEYGEN_USER_ID")
+	if err := client.TransferLicenseOwner(ctx, licenseID, userID); err != nil {
+		t.Fatalf("TransferLicenseOwner error: %v", err)
+	}
+	t.Log("TransferLicenseOwner succeeded")
+}
+
+func TestCreateLicenseToken(t *testing.T) {
+	ctx := context.Background()
+	client := getTestClient()
+	licenseID := os.Getenv("KEYGEN_LICENSE_ID")
+	result, err := client.CreateLicenseToken(ctx, licenseID, LicenseTokenOptions{
+		Name:           "test",
+		Expiry:         time.Now().Add(time.Hour),
+		MaxActivations: 1,
+	})
+	if err != nil {
+		t.Fatalf("CreateLicenseToken error: %v", err)
+	}
+	t.Logf("CreateLicenseToken result: id=%s kind=%s", result.ID, result.Kind)
+}
+
 func TestGetLicenseBySubscriptionID(t *testing.T) {
 	ctx := context.Background()
 	client := getTestClient()
@@ -51,6 +269,86 @@ func TestGetLicenseBySubscriptionID(t *testing.T) {
 	t.Logf("GetLicenseBySubscriptionID result: %v", result)
 }
 
+func TestListLicensesBySubscriptionID(t *testing.T) {
+	ctx := context.Background()
+	client := getTestClient()
+	subscriptionID := os.Getenv("KEYGEN_SUBSCRIPTION_ID")
+	result, err := client.ListLicensesBySubscriptionID(ctx, subscriptionID)
+	if err != nil {
+		t.Fatalf("ListLicensesBySubscriptionID error: %v", err)
+	}
+	t.Logf("ListLicensesBySubscriptionID result: %+v", result)
+}
+
+func TestFindLicensesByMetadata(t *testing.T) {
+	ctx := context.Background()
+	client := getTestClient()
+	meta := map[string]string{"customerEmail": os.Getenv("KEYGEN_CUSTOMER_EMAIL")}
+	result, err := client.FindLicensesByMetadata(ctx, meta)
+	if err != nil {
+		t.Fatalf("FindLicensesByMetadata error: %v", err)
+	}
+	t.Logf("FindLicensesByMetadata result: %+v", result)
+}
+
+func TestListLicensesByGroup(t *testing.T) {
+	ctx := context.Background()
+	client := getTestClient()
+	groupID := os.Getenv("KEYGEN_GROUP_ID")
+	result, err := client.ListLicensesByGroup(ctx, groupID)
+	if err != nil {
+		t.Fatalf("ListLicensesByGroup error: %v", err)
+	}
+	t.Logf("ListLicensesByGroup result: %+v", result)
+}
+
+func TestListLicenses(t *testing.T) {
+	ctx := context.Background()
+	client := getTestClient()
+	result, err := client.ListLicenses(ctx, LicenseFilter{
+		Policy: os.Getenv("KEYGEN_POLICY_ID"),
+		Status: "ACTIVE",
+	})
+	if err != nil {
+		t.Fatalf("ListLicenses error: %v", err)
+	}
+	t.Logf("ListLicenses result: %+v", result)
+}
+
+func TestForEachLicense(t *testing.T) {
+	ctx := context.Background()
+	client := getTestClient()
+	count := 0
+	err := client.ForEachLicense(ctx, LicenseFilter{Policy: os.Getenv("KEYGEN_POLICY_ID")}, func(l LicenseSummary) error {
+		count++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ForEachLicense error: %v", err)
+	}
+	t.Logf("ForEachLicense result: %d licenses", count)
+}
+
+func TestCountLicenses(t *testing.T) {
+	ctx := context.Background()
+	client := getTestClient()
+	result, err := client.CountLicenses(ctx, LicenseFilter{Policy: os.Getenv("KEYGEN_POLICY_ID")})
+	if err != nil {
+		t.Fatalf("CountLicenses error: %v", err)
+	}
+	t.Logf("CountLicenses result: %d", result)
+}
+
+func TestListExpiringLicenses(t *testing.T) {
+	ctx := context.Background()
+	client := getTestClient()
+	result, err := client.ListExpiringLicenses(ctx, 30*24*time.Hour)
+	if err != nil {
+		t.Fatalf("ListExpiringLicenses error: %v", err)
+	}
+	t.Logf("ListExpiringLicenses result: %+v", result)
+}
+
 func TestListLicensesByPolicy(t *testing.T) {
 	ctx := context.Background()
 	client := getTestClient()
@@ -78,7 +376,9 @@ func TestActivateMachine(t *testing.T) {
 	client := getTestClient()
 	licenseKey := os.Getenv("KEYGEN_LICENSE_KEY")
 	fingerprint := os.Getenv("KEYGEN_FINGERPRINT")
-	err := client.ActivateMachine(ctx, licenseKey, fingerprint, "", "")
+	hostname, _ := os.Hostname()
+	err := client.ActivateMachine(ctx, licenseKey, fingerprint, "", "",
+		WithHostname(hostname), WithMachineMetadata(map[string]any{"source": "test"}))
 	if err != nil {
 		t.Fatalf("ActivateMachine error: %v", err)
 	}
@@ -118,6 +418,132 @@ func TestListAllMachines(t *testing.T) {
 	t.Logf("ListAllMachines result: %+v", result)
 }
 
+func TestListMachinesFiltered(t *testing.T) {
+	ctx := context.Background()
+	client := getTestClient()
+	result, err := client.ListMachinesFiltered(ctx, MachineFilter{Platform: "linux", HeartbeatStatus: "ALIVE"})
+	if err != nil {
+		t.Fatalf("ListMachinesFiltered error: %v", err)
+	}
+	t.Logf("ListMachinesFiltered result: %d machines", len(result))
+}
+
+func TestSearchMachinesByFingerprint(t *testing.T) {
+	ctx := context.Background()
+	client := getTestClient()
+	fingerprint := os.Getenv("KEYGEN_FINGERPRINT")
+	if len(fingerprint) > 8 {
+		fingerprint = fingerprint[:8]
+	}
+	result, err := client.SearchMachinesByFingerprint(ctx, fingerprint)
+	if err != nil {
+		t.Fatalf("SearchMachinesByFingerprint error: %v", err)
+	}
+	t.Logf("SearchMachinesByFingerprint result: %+v", result)
+}
+
+func TestListDeadMachines(t *testing.T) {
+	ctx := context.Background()
+	client := getTestClient()
+	result, err := client.ListDeadMachines(ctx, 7*24*time.Hour)
+	if err != nil {
+		t.Fatalf("ListDeadMachines error: %v", err)
+	}
+	t.Logf("ListDeadMachines result: %d machines", len(result))
+}
+
+func TestCountMachines(t *testing.T) {
+	ctx := context.Background()
+	client := getTestClient()
+	result, err := client.CountMachines(ctx, MachineFilter{Platform: "linux"})
+	if err != nil {
+		t.Fatalf("CountMachines error: %v", err)
+	}
+	t.Logf("CountMachines result: %d", result)
+}
+
+func TestUpdateMachine(t *testing.T) {
+	ctx := context.Background()
+	client := getTestClient()
+	machineID := os.Getenv("KEYGEN_MACHINE_ID")
+	hostname := "dappnode-test"
+	result, err := client.UpdateMachine(ctx, machineID, MachineUpdate{Hostname: &hostname})
+	if err != nil {
+		t.Fatalf("UpdateMachine error: %v", err)
+	}
+	t.Logf("UpdateMachine result: %+v", result)
+}
+
+func TestCheckoutMachine(t *testing.T) {
+	ctx := context.Background()
+	client := getTestClient()
+	machineID := os.Getenv("KEYGEN_MACHINE_ID")
+	result, err := client.CheckoutMachine(ctx, machineID, MachineCheckoutOptions{
+		TTL:     7 * 24 * time.Hour,
+		Include: []string{"license"},
+	})
+	if err != nil {
+		t.Fatalf("CheckoutMachine error: %v", err)
+	}
+	t.Logf("CheckoutMachine result: expiry=%v ttl=%d", result.Expiry, result.TTL)
+}
+
+func TestCheckoutLicense(t *testing.T) {
+	ctx := context.Background()
+	client := getTestClient()
+	licenseID := os.Getenv("KEYGEN_LICENSE_ID")
+	result, err := client.CheckoutLicense(ctx, licenseID, CheckoutOptions{
+		TTL:     30 * 24 * time.Hour,
+		Include: []string{"entitlements"},
+	})
+	if err != nil {
+		t.Fatalf("CheckoutLicense error: %v", err)
+	}
+	t.Logf("CheckoutLicense result: expiry=%v encrypted=%v", result.Expiry, result.Encrypted)
+}
+
+func TestResetMachineHeartbeat(t *testing.T) {
+	ctx := context.Background()
+	client := getTestClient()
+	machineID := os.Getenv("KEYGEN_MACHINE_ID")
+	if err := client.ResetMachineHeartbeat(ctx, machineID); err != nil {
+		t.Fatalf("ResetMachineHeartbeat error: %v", err)
+	}
+	t.Log("ResetMachineHeartbeat succeeded")
+}
+
+func TestChangeMachineOwner(t *testing.T) {
+	ctx := context.Background()
+	client := getTestClient()
+	machineID := os.Getenv("KEYGEN_MACHINE_ID")
+	userID := os.Getenv("KEYGEN_USER_ID")
+	if err := client.ChangeMachineOwner(ctx, machineID, userID); err != nil {
+		t.Fatalf("ChangeMachineOwner error: %v", err)
+	}
+	t.Log("ChangeMachineOwner succeeded")
+}
+
+func TestDeleteMachine(t *testing.T) {
+	ctx := context.Background()
+	client := getTestClient()
+	machineID := os.Getenv("KEYGEN_MACHINE_ID")
+	if err := client.DeleteMachine(ctx, machineID); err != nil {
+		t.Fatalf("DeleteMachine error: %v", err)
+	}
+	t.Log("DeleteMachine succeeded")
+}
+
+func TestDeactivateAllMachines(t *testing.T) {
+	ctx := context.Background()
+	client := getTestClient()
+	licenseID := os.Getenv("KEYGEN_LICENSE_ID")
+	removed, err := client.DeactivateAllMachines(ctx, licenseID)
+	if err != nil {
+		t.Fatalf("DeactivateAllMachines error: %v", err)
+	}
+	t.Logf("DeactivateAllMachines removed: %d", removed)
+}
+
 func TestValidate(t *testing.T) {
 	ctx := context.Background()
 	client := getTestClient()
@@ -130,6 +556,48 @@ func TestValidate(t *testing.T) {
 	t.Logf("Validate result: %+v", result)
 }
 
+func TestValidateScoped(t *testing.T) {
+	ctx := context.Background()
+	client := getTestClient()
+	licenseKey := os.Getenv("KEYGEN_LICENSE_KEY")
+	result, err := client.ValidateScoped(ctx, licenseKey, ValidationScope{
+		Fingerprint: os.Getenv("KEYGEN_FINGERPRINT"),
+		Policy:      os.Getenv("KEYGEN_POLICY_ID"),
+	})
+	if err != nil {
+		t.Fatalf("ValidateScoped error: %v", err)
+	}
+	t.Logf("ValidateScoped result: %+v", result)
+}
+
+func TestValidateScopedNonce(t *testing.T) {
+	ctx := context.Background()
+	client := getTestClient()
+	licenseKey := os.Getenv("KEYGEN_LICENSE_KEY")
+	nonce := time.Now().UnixNano()
+	result, err := client.ValidateScoped(ctx, licenseKey, ValidationScope{
+		Fingerprint: os.Getenv("KEYGEN_FINGERPRINT"),
+		Nonce:       nonce,
+	})
+	if err != nil {
+		t.Fatalf("ValidateScoped error: %v", err)
+	}
+	t.Logf("ValidateScoped result: nonce=%d signature=%q", result.Nonce, result.Signature)
+}
+
+func TestValidateLicenseByID(t *testing.T) {
+	ctx := context.Background()
+	client := getTestClient()
+	licenseID := os.Getenv("KEYGEN_LICENSE_ID")
+	result, err := client.ValidateLicenseByID(ctx, licenseID, ValidationScope{
+		Fingerprint: os.Getenv("KEYGEN_FINGERPRINT"),
+	})
+	if err != nil {
+		t.Fatalf("ValidateLicenseByID error: %v", err)
+	}
+	t.Logf("ValidateLicenseByID result: %+v", result)
+}
+
 func TestResolveLicenseID(t *testing.T) {
 	ctx := context.Background()
 	client := getTestClient()
@@ -140,3 +608,58 @@ func TestResolveLicenseID(t *testing.T) {
 	}
 	t.Logf("ResolveLicenseID result: %v", result)
 }
+
+func TestGetAccount(t *testing.T) {
+	ctx := context.Background()
+	client := getTestClient()
+	result, err := client.GetAccount(ctx)
+	if err != nil {
+		t.Fatalf("GetAccount error: %v", err)
+	}
+	t.Logf("GetAccount result: %s ed25519=%s", result.Slug, result.Ed25519PublicKey)
+}
+
+func TestWhoAmI(t *testing.T) {
+	ctx := context.Background()
+	client := getTestClient()
+	result, err := client.WhoAmI(ctx)
+	if err != nil {
+		t.Fatalf("WhoAmI error: %v", err)
+	}
+	t.Logf("WhoAmI result: %+v", result)
+}
+
+func TestRenewLicense(t *testing.T) {
+	ctx := context.Background()
+	client := getTestClient()
+	licenseID := os.Getenv("KEYGEN_LICENSE_ID")
+	result, err := client.RenewLicense(ctx, licenseID)
+	if err != nil {
+		t.Fatalf("RenewLicense error: %v", err)
+	}
+	t.Logf("RenewLicense result: %v", result)
+}
+
+func TestExtendLicenseExpiry(t *testing.T) {
+	ctx := context.Background()
+	client := getTestClient()
+	licenseID := os.Getenv("KEYGEN_LICENSE_ID")
+	result, err := client.ExtendLicenseExpiry(ctx, licenseID, 30*24*time.Hour)
+	if err != nil {
+		t.Fatalf("ExtendLicenseExpiry error: %v", err)
+	}
+	t.Logf("ExtendLicenseExpiry result: %v", result)
+}
+
+func TestSuspendAndReinstateLicense(t *testing.T) {
+	ctx := context.Background()
+	client := getTestClient()
+	licenseID := os.Getenv("KEYGEN_LICENSE_ID")
+	if err := client.SuspendLicense(ctx, licenseID); err != nil {
+		t.Fatalf("SuspendLicense error: %v", err)
+	}
+	if err := client.ReinstateLicense(ctx, licenseID); err != nil {
+		t.Fatalf("ReinstateLicense error: %v", err)
+	}
+	t.Log("SuspendLicense/ReinstateLicense succeeded")
+}
@@ -0,0 +1,67 @@
+package keygen
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// CreateComponent registers a hardware component with the given fingerprint
+// on machineID.
+func (c *Client) CreateComponent(ctx context.Context, machineID, fingerprint, name string, meta map[string]any) (Component, error) {
+	var req componentCreateRequest
+	req.Data.Type = "components"
+	req.Data.Attributes.Fingerprint = fingerprint
+	req.Data.Attributes.Name = name
+	req.Data.Attributes.Metadata = meta
+	req.Data.Relationships.Machine.Data = relationshipData{Type: "machines", ID: machineID}
+
+	var resp componentResponse
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("/accounts/%s/components", c.accountID), req, &resp); err != nil {
+		return Component{}, err
+	}
+	return resp.Data.toComponent(), nil
+}
+
+// GetComponent returns a component by ID.
+func (c *Client) GetComponent(ctx context.Context, componentID string) (Component, error) {
+	path := fmt.Sprintf("/accounts/%s/components/%s", c.accountID, componentID)
+
+	var resp componentResponse
+	if err := c.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return Component{}, err
+	}
+	return resp.Data.toComponent(), nil
+}
+
+// UpdateComponent PATCHes the attributes set in u and returns the updated
+// component. A component's fingerprint cannot be changed.
+func (c *Client) UpdateComponent(ctx context.Context, componentID string, u ComponentUpdate) (Component, error) {
+	var req componentUpdateRequest
+	req.Data.Type = "components"
+	req.Data.Attributes.Name = u.Name
+	if u.Metadata != nil {
+		req.Data.Attributes.Metadata = &u.Metadata
+	}
+	path := fmt.Sprintf("/accounts/%s/components/%s", c.accountID, componentID)
+
+	var resp componentResponse
+	if err := c.do(ctx, http.MethodPatch, path, req, &resp); err != nil {
+		return Component{}, err
+	}
+	return resp.Data.toComponent(), nil
+}
+
+// DeleteComponent removes a component from its machine.
+func (c *Client) DeleteComponent(ctx context.Context, componentID string) error {
+	path := fmt.Sprintf("/accounts/%s/components/%s", c.accountID, componentID)
+	return c.do(ctx, http.MethodDelete, path, nil, nil)
+}
+
+// ListComponents lists the components registered on machineID.
+func (c *Client) ListComponents(ctx context.Context, machineID string) ([]Component, error) {
+	q := url.Values{}
+	q.Set("machine", machineID)
+	return listAll(ctx, c, "/components", q, componentResource.toComponent)
+}
@@ -0,0 +1,65 @@
+package keygen
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestComponents(t *testing.T) {
+	const resource = `{"id":"c1","attributes":{"fingerprint":"gpu-abc","name":"GPU"},
+		"relationships":{"machine":{"data":{"type":"machines","id":"m1"}}}}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "POST /accounts/acct/components":
+			var req componentCreateRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Errorf("decode: %v", err)
+			}
+			if req.Data.Attributes.Fingerprint != "gpu-abc" || req.Data.Relationships.Machine.Data.ID != "m1" {
+				t.Errorf("unexpected create body: %+v", req)
+			}
+			w.Write([]byte(`{"data":` + resource + `}`))
+		case "PATCH /accounts/acct/components/c1":
+			var req componentUpdateRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Errorf("decode: %v", err)
+			}
+			if req.Data.Attributes.Name == nil || *req.Data.Attributes.Name != "GPU" || req.Data.Attributes.Metadata != nil {
+				t.Errorf("unexpected update body: %+v", req)
+			}
+			w.Write([]byte(`{"data":` + resource + `}`))
+		case "GET /accounts/acct/components":
+			if got := r.URL.Query().Get("machine"); got != "m1" {
+				t.Errorf("machine filter = %q", got)
+			}
+			w.Write([]byte(`{"data":[` + resource + `],"links":{}}`))
+		case "DELETE /accounts/acct/components/c1":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	c := New("acct", "token", WithBaseURL(srv.URL))
+	comp, err := c.CreateComponent(ctx, "m1", "gpu-abc", "GPU", nil)
+	if err != nil || comp.ID != "c1" || comp.MachineID != "m1" {
+		t.Fatalf("CreateComponent = %+v, %v", comp, err)
+	}
+	name := "GPU"
+	if _, err := c.UpdateComponent(ctx, "c1", ComponentUpdate{Name: &name}); err != nil {
+		t.Fatalf("UpdateComponent: %v", err)
+	}
+	list, err := c.ListComponents(ctx, "m1")
+	if err != nil || len(list) != 1 || list[0].Fingerprint != "gpu-abc" {
+		t.Fatalf("ListComponents = %+v, %v", list, err)
+	}
+	if err := c.DeleteComponent(ctx, "c1"); err != nil {
+		t.Fatalf("DeleteComponent: %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+package keygen
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// CredentialProvider supplies the token sent with each request, so that
+// rotated or short-lived tokens (e.g. read from Vault) are picked up without
+// recreating the client. Token is called once per request and must be safe
+// for concurrent use; providers that fetch remotely should cache (see
+// RefreshingCredentials).
+type CredentialProvider interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// WithCredentials makes the client ask p for its token on every request
+// instead of using the static token given to New. The authentication scheme
+// (bearer token or license key) is kept.
+func WithCredentials(p CredentialProvider) Option {
+	return func(c *Client) { c.credentials = p }
+}
+
+// RefreshingCredentials caches a token returned by a fetch function until
+// shortly before it expires.
+type RefreshingCredentials struct {
+	fetch  func(ctx context.Context) (token string, expiry time.Time, err error)
+	margin time.Duration
+	now    func() time.Time
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// NewRefreshingCredentials returns a provider calling fetch for the first
+// request and again once the cached token is within margin of its expiry.
+// A zero expiry means the token never expires. Concurrent requests share a
+// single fetch.
+func NewRefreshingCredentials(fetch func(ctx context.Context) (string, time.Time, error), margin time.Duration) *RefreshingCredentials {
+	return &RefreshingCredentials{fetch: fetch, margin: margin, now: time.Now}
+}
+
+// Token returns the cached token, refreshing it first if needed.
+func (r *RefreshingCredentials) Token(ctx context.Context) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.token != "" && (r.expiry.IsZero() || r.now().Add(r.margin).Before(r.expiry)) {
+		return r.token, nil
+	}
+	token, expiry, err := r.fetch(ctx)
+	if err != nil {
+		return "", err
+	}
+	r.token, r.expiry = token, expiry
+	return token, nil
+}
+
+// Invalidate drops the cached token so the next request fetches a new one,
+// e.g. after the token was revoked early.
+func (r *RefreshingCredentials) Invalidate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.token, r.expiry = "", time.Time{}
+}
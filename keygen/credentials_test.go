@@ -0,0 +1,54 @@
+package keygen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWithCredentials(t *testing.T) {
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	fetches := 0
+	creds := NewRefreshingCredentials(func(context.Context) (string, time.Time, error) {
+		fetches++
+		return fmt.Sprintf("tok-%d", fetches), now.Add(time.Hour), nil
+	}, 5*time.Minute)
+	creds.now = func() time.Time { return now }
+
+	c := New("acct", "static", WithBaseURL(srv.URL), WithCredentials(creds))
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if err := c.DeleteLicense(ctx, "lic"); err != nil {
+			t.Fatalf("DeleteLicense error: %v", err)
+		}
+	}
+	if auth != "Bearer tok-1" || fetches != 1 {
+		t.Fatalf("cached token: Authorization %q after %d fetches", auth, fetches)
+	}
+
+	now = now.Add(56 * time.Minute) // within the refresh margin
+	if err := c.DeleteLicense(ctx, "lic"); err != nil {
+		t.Fatalf("DeleteLicense error: %v", err)
+	}
+	if auth != "Bearer tok-2" {
+		t.Fatalf("refreshed token: Authorization %q", auth)
+	}
+
+	boom := errors.New("vault sealed")
+	c = New("acct", "static", WithBaseURL(srv.URL), WithCredentials(NewRefreshingCredentials(
+		func(context.Context) (string, time.Time, error) { return "", time.Time{}, boom }, 0)))
+	if err := c.DeleteLicense(ctx, "lic"); !errors.Is(err, boom) {
+		t.Fatalf("expected provider error, got %v", err)
+	}
+}
@@ -0,0 +1,65 @@
+package keygen
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash"
+	"strings"
+)
+
+// ErrDigestMismatch is returned when WithDigestVerification is enabled and a
+// response body does not match (or lacks) its Digest header.
+var ErrDigestMismatch = errors.New("keygen: response digest mismatch")
+
+// WithDigestVerification verifies every successful response body against the
+// Digest header Keygen sends (e.g. "sha-256=<base64>") before decoding it.
+// This catches truncation or corruption by misbehaving proxies independently
+// of full signature verification.
+func WithDigestVerification() Option {
+	return func(c *Client) { c.verifyDigest = true }
+}
+
+// verifyDigest checks body against a Digest header value (RFC 3230). At least
+// one supported algorithm must be present and all supported ones must match.
+func verifyDigest(header string, body []byte) error {
+	if header == "" {
+		if len(body) == 0 {
+			return nil
+		}
+		return fmt.Errorf("%w: missing Digest header", ErrDigestMismatch)
+	}
+
+	checked := 0
+	for _, part := range strings.Split(header, ",") {
+		alg, val, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		var h hash.Hash
+		switch strings.ToLower(alg) {
+		case "sha-256":
+			h = sha256.New()
+		case "sha-512":
+			h = sha512.New()
+		default:
+			continue
+		}
+		want, err := base64.StdEncoding.DecodeString(val)
+		if err != nil {
+			return fmt.Errorf("%w: malformed %s value", ErrDigestMismatch, alg)
+		}
+		h.Write(body)
+		if subtle.ConstantTimeCompare(h.Sum(nil), want) != 1 {
+			return fmt.Errorf("%w: %s does not match body (%d bytes)", ErrDigestMismatch, alg, len(body))
+		}
+		checked++
+	}
+	if checked == 0 {
+		return fmt.Errorf("%w: no supported algorithm in %q", ErrDigestMismatch, header)
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package keygen
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func TestVerifyDigest(t *testing.T) {
+	body := []byte(`{"data":{"id":"lic-1"}}`)
+	sum := sha256.Sum256(body)
+	good := "sha-256=" + base64.StdEncoding.EncodeToString(sum[:])
+
+	if err := verifyDigest(good, body); err != nil {
+		t.Fatalf("matching digest: %v", err)
+	}
+	if err := verifyDigest(good, body[:len(body)-2]); !errors.Is(err, ErrDigestMismatch) {
+		t.Fatalf("truncated body: expected ErrDigestMismatch, got %v", err)
+	}
+	if err := verifyDigest("", body); !errors.Is(err, ErrDigestMismatch) {
+		t.Fatalf("missing header: expected ErrDigestMismatch, got %v", err)
+	}
+	if err := verifyDigest("", nil); err != nil {
+		t.Fatalf("empty body without header: %v", err)
+	}
+}
@@ -0,0 +1,14 @@
+// Package keygen is a thin client for the Keygen licensing API.
+//
+// # Build tags
+//
+// Building with -tags keygen_minimal excludes optional subsystems that pull
+// in server-side code, keeping binaries small for embedded agents: the LAN
+// ProxyServer, publisher-side distribution (creating, publishing, yanking and
+// deleting releases, UploadArtifact, YankArtifact and packages) and webhook
+// endpoint management. The core client, validation, ProxyClient and the
+// device-side distribution calls (GetRelease, ListReleases, ListArtifacts,
+// CheckForUpgrade and DownloadArtifact) are always available. The gRPC
+// facade lives in the separate keygengrpc package and is only linked into
+// binaries that import it.
+package keygen
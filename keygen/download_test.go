@@ -0,0 +1,82 @@
+package keygen
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDownloadArtifact(t *testing.T) {
+	payload := []byte("dappnode package v1.1.0")
+	digest := sha512.Sum512(payload)
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	sig, err := priv.Sign(nil, digest[:], &ed25519.Options{Hash: crypto.SHA512})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checksum := base64.StdEncoding.EncodeToString(digest[:])
+
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /accounts/acct/releases/rel-1/artifacts/pkg.tar.xz":
+			if r.Header.Get("Prefer") != "no-redirect" {
+				t.Errorf("missing Prefer: no-redirect header")
+			}
+			_, _ = w.Write([]byte(`{"data":{"id":"art-1","attributes":{"filename":"pkg.tar.xz","filesize":23,` +
+				`"checksum":"` + checksum + `","signature":"` + base64.StdEncoding.EncodeToString(sig) + `"}},` +
+				`"links":{"redirect":"` + srv.URL + `/storage/art-1"}}`))
+		case "GET /accounts/acct/releases/rel-1/artifacts/bare.tar.xz":
+			_, _ = w.Write([]byte(`{"data":{"id":"art-2","attributes":{"filename":"bare.tar.xz"}},` +
+				`"links":{"redirect":"` + srv.URL + `/storage/art-1"}}`))
+		case "GET /storage/art-1":
+			if r.Header.Get("Authorization") != "" {
+				t.Errorf("download must not carry the API token")
+			}
+			_, _ = w.Write(payload)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	c := New("acct", "token", WithBaseURL(srv.URL), WithArtifactPublicKey(pub))
+	a, err := c.DownloadArtifact(context.Background(), "rel-1", "pkg.tar.xz", &buf)
+	if err != nil || a.ID != "art-1" || !bytes.Equal(buf.Bytes(), payload) {
+		t.Fatalf("DownloadArtifact = %+v, %q, %v", a, buf.String(), err)
+	}
+
+	other, _, _ := ed25519.GenerateKey(nil)
+	c = New("acct", "token", WithBaseURL(srv.URL), WithArtifactPublicKey(other))
+	if _, err := c.DownloadArtifact(context.Background(), "rel-1", "pkg.tar.xz", &bytes.Buffer{}); !errors.Is(err, ErrArtifactIntegrity) {
+		t.Fatalf("DownloadArtifact with wrong key: got %v, want ErrArtifactIntegrity", err)
+	}
+
+	// Without a checksum or a key nothing can be verified.
+	c = New("acct", "token", WithBaseURL(srv.URL))
+	if _, err := c.DownloadArtifact(context.Background(), "rel-1", "bare.tar.xz", &bytes.Buffer{}); !errors.Is(err, ErrArtifactIntegrity) {
+		t.Fatalf("DownloadArtifact without checksum: got %v, want ErrArtifactIntegrity", err)
+	}
+	if _, err := c.DownloadArtifact(context.Background(), "rel-1", "pkg.tar.xz", &bytes.Buffer{}); err != nil {
+		t.Fatalf("DownloadArtifact with checksum only: %v", err)
+	}
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	sum := sha512.Sum512([]byte("x"))
+	if err := verifyChecksum(base64.StdEncoding.EncodeToString(sum[:]), nil, sum[:]); err != nil {
+		t.Fatalf("base64 sha512: %v", err)
+	}
+	if err := verifyChecksum("00ff", nil, sum[:]); !errors.Is(err, ErrArtifactIntegrity) {
+		t.Fatalf("short checksum: got %v", err)
+	}
+}
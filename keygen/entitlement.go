@@ -0,0 +1,46 @@
+package keygen
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// CreateEntitlement creates an entitlement; code is the stable identifier
+// checked by feature gates (e.g. "PKG_ROTKI").
+func (c *Client) CreateEntitlement(ctx context.Context, name, code string, meta map[string]any) (Entitlement, error) {
+	var req entitlementCreateRequest
+	req.Data.Type = "entitlements"
+	req.Data.Attributes.Name = name
+	req.Data.Attributes.Code = code
+	req.Data.Attributes.Metadata = meta
+
+	var resp entitlementResponse
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("/accounts/%s/entitlements", c.accountID), req, &resp); err != nil {
+		return Entitlement{}, err
+	}
+	return resp.Data.toEntitlement(), nil
+}
+
+// GetEntitlement returns an entitlement by ID.
+func (c *Client) GetEntitlement(ctx context.Context, entitlementID string) (Entitlement, error) {
+	path := fmt.Sprintf("/accounts/%s/entitlements/%s", c.accountID, entitlementID)
+
+	var resp entitlementResponse
+	if err := c.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return Entitlement{}, err
+	}
+	return resp.Data.toEntitlement(), nil
+}
+
+// DeleteEntitlement deletes an entitlement, detaching it from every policy
+// and license.
+func (c *Client) DeleteEntitlement(ctx context.Context, entitlementID string) error {
+	path := fmt.Sprintf("/accounts/%s/entitlements/%s", c.accountID, entitlementID)
+	return c.do(ctx, http.MethodDelete, path, nil, nil)
+}
+
+// ListEntitlements lists the account's entitlements.
+func (c *Client) ListEntitlements(ctx context.Context) ([]Entitlement, error) {
+	return listAll(ctx, c, "/entitlements", nil, entitlementResource.toEntitlement)
+}
@@ -0,0 +1,39 @@
+package keygen
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestEntitlements(t *testing.T) {
+	const resource = `{"id":"ent-1","attributes":{"name":"Rotki","code":"PKG_ROTKI"}}`
+	api := newFakeAPI(t, map[string]http.HandlerFunc{
+		"POST /entitlements":         jsonData(resource),
+		"GET /entitlements/ent-1":    jsonData(resource),
+		"GET /entitlements":          jsonList(resource),
+		"DELETE /entitlements/ent-1": noContent,
+	})
+
+	ctx := context.Background()
+	c := api.client()
+	e, err := c.CreateEntitlement(ctx, "Rotki", "PKG_ROTKI", nil)
+	if err != nil || e.ID != "ent-1" || e.Code != "PKG_ROTKI" {
+		t.Fatalf("CreateEntitlement = %+v, %v", e, err)
+	}
+	var req entitlementCreateRequest
+	api.decodeBody("POST /entitlements", &req)
+	if req.Data.Attributes.Code != "PKG_ROTKI" {
+		t.Fatalf("create body = %+v", req)
+	}
+
+	if e, err := c.GetEntitlement(ctx, "ent-1"); err != nil || e.Name != "Rotki" {
+		t.Fatalf("GetEntitlement = %+v, %v", e, err)
+	}
+	if list, err := c.ListEntitlements(ctx); err != nil || len(list) != 1 || list[0].Code != "PKG_ROTKI" {
+		t.Fatalf("ListEntitlements = %+v, %v", list, err)
+	}
+	if err := c.DeleteEntitlement(ctx, "ent-1"); err != nil {
+		t.Fatalf("DeleteEntitlement: %v", err)
+	}
+}
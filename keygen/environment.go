@@ -0,0 +1,61 @@
+package keygen
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// CreateEnvironment creates an environment. isolationStrategy is ISOLATED
+// (separate data) or SHARED (sees the global environment's data).
+func (c *Client) CreateEnvironment(ctx context.Context, name, code, isolationStrategy string) (Environment, error) {
+	var req environmentRequest
+	req.Data.Type = "environments"
+	req.Data.Attributes.Name = name
+	req.Data.Attributes.Code = code
+	req.Data.Attributes.IsolationStrategy = isolationStrategy
+
+	var resp environmentResponse
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("/accounts/%s/environments", c.accountID), req, &resp); err != nil {
+		return Environment{}, err
+	}
+	return resp.Data.toEnvironment(), nil
+}
+
+// GetEnvironment returns an environment by ID or code.
+func (c *Client) GetEnvironment(ctx context.Context, environmentID string) (Environment, error) {
+	path := fmt.Sprintf("/accounts/%s/environments/%s", c.accountID, environmentID)
+
+	var resp environmentResponse
+	if err := c.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return Environment{}, err
+	}
+	return resp.Data.toEnvironment(), nil
+}
+
+// UpdateEnvironment renames an environment; empty arguments are left
+// untouched.
+func (c *Client) UpdateEnvironment(ctx context.Context, environmentID, name, code string) (Environment, error) {
+	var req environmentRequest
+	req.Data.Type = "environments"
+	req.Data.Attributes.Name = name
+	req.Data.Attributes.Code = code
+	path := fmt.Sprintf("/accounts/%s/environments/%s", c.accountID, environmentID)
+
+	var resp environmentResponse
+	if err := c.do(ctx, http.MethodPatch, path, req, &resp); err != nil {
+		return Environment{}, err
+	}
+	return resp.Data.toEnvironment(), nil
+}
+
+// DeleteEnvironment deletes an environment and all of its data.
+func (c *Client) DeleteEnvironment(ctx context.Context, environmentID string) error {
+	path := fmt.Sprintf("/accounts/%s/environments/%s", c.accountID, environmentID)
+	return c.do(ctx, http.MethodDelete, path, nil, nil)
+}
+
+// ListEnvironments lists the account's environments.
+func (c *Client) ListEnvironments(ctx context.Context) ([]Environment, error) {
+	return listAll(ctx, c, "/environments", nil, environmentResource.toEnvironment)
+}
@@ -0,0 +1,24 @@
+package keygen
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWithEnvironment_SetsHeader(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Keygen-Environment"); got != "sandbox" {
+			t.Errorf("Keygen-Environment = %q, want sandbox", got)
+		}
+		_, _ = w.Write([]byte(`{"data":[{"id":"env-1","attributes":{"code":"sandbox","isolationStrategy":"ISOLATED"}}],"links":{}}`))
+	}))
+	defer srv.Close()
+
+	c := New("acct", "token", WithBaseURL(srv.URL), WithEnvironment("sandbox"))
+	envs, err := c.ListEnvironments(context.Background())
+	if err != nil || len(envs) != 1 || envs[0].IsolationStrategy != "ISOLATED" {
+		t.Fatalf("ListEnvironments = %+v, %v", envs, err)
+	}
+}
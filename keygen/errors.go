@@ -0,0 +1,190 @@
+package keygen
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Sentinel errors matched by *HTTPError, e.g.
+// errors.Is(err, keygen.ErrMachineLimitExceeded).
+var (
+	ErrNotFound             = errors.New("keygen: not found")              // HTTP 404
+	ErrUnauthorized         = errors.New("keygen: unauthorized")           // HTTP 401 or 403
+	ErrRateLimited          = errors.New("keygen: rate limited")           // HTTP 429
+	ErrMachineLimitExceeded = errors.New("keygen: machine limit exceeded") // MACHINE_LIMIT_EXCEEDED
+	ErrLicenseExpired       = errors.New("keygen: license expired")        // LICENSE_EXPIRED
+)
+
+// PartialResultError is returned by paginated list calls when the context is
+// cancelled or its deadline expires after at least one page was fetched.
+// The results fetched so far are returned alongside it.
+type PartialResultError struct {
+	Pages int   // pages fetched before the interruption
+	Err   error // underlying error (wraps context.Canceled/DeadlineExceeded)
+}
+
+func (e *PartialResultError) Error() string {
+	return fmt.Sprintf("keygen: partial result after %d page(s): %v", e.Pages, e.Err)
+}
+
+func (e *PartialResultError) Unwrap() error { return e.Err }
+
+// HTTPError is returned for non-2xx API responses. Body holds the raw
+// response body, if any.
+type HTTPError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Body       string
+	// RetryAfter is parsed from the Retry-After header (e.g. on 429), or 0.
+	RetryAfter time.Duration
+	// Errors is the parsed JSON:API "errors" array of Body, if any.
+	Errors []APIError
+}
+
+// APIError is one entry of a JSON:API error response.
+type APIError struct {
+	Title         string `json:"title"`
+	Detail        string `json:"detail"`
+	Code          string `json:"code"`           // e.g. "MACHINE_LIMIT_EXCEEDED"
+	SourcePointer string `json:"source_pointer"` // e.g. "/data/attributes/fingerprint"
+}
+
+// parseAPIErrors decodes a JSON:API error body; it returns nil for bodies
+// that are not one.
+func parseAPIErrors(body string) []APIError {
+	var doc struct {
+		Errors []struct {
+			Title  string `json:"title"`
+			Detail string `json:"detail"`
+			Code   string `json:"code"`
+			Source struct {
+				Pointer string `json:"pointer"`
+			} `json:"source"`
+		} `json:"errors"`
+	}
+	if json.Unmarshal([]byte(body), &doc) != nil {
+		return nil
+	}
+	var errs []APIError
+	for _, e := range doc.Errors {
+		errs = append(errs, APIError{Title: e.Title, Detail: e.Detail, Code: e.Code, SourcePointer: e.Source.Pointer})
+	}
+	return errs
+}
+
+func (e *HTTPError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("keygen: %s %s -> HTTP %d", e.Method, e.Path, e.StatusCode)
+	}
+	return fmt.Sprintf("keygen: %s %s -> HTTP %d: %s", e.Method, e.Path, e.StatusCode, e.Body)
+}
+
+// Is matches the sentinel errors from the status code and error codes.
+func (e *HTTPError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrMachineLimitExceeded:
+		return e.HasCode("MACHINE_LIMIT_EXCEEDED")
+	case ErrLicenseExpired:
+		return e.HasCode("LICENSE_EXPIRED")
+	}
+	return false
+}
+
+// Codes returns the error codes (e.g. "MACHINE_LIMIT_EXCEEDED") listed in a
+// JSON:API error body, or nil if the body is not one.
+func (e *HTTPError) Codes() []string {
+	errs := e.Errors
+	if errs == nil {
+		errs = parseAPIErrors(e.Body) // e.g. an HTTPError built by hand
+	}
+	var codes []string
+	for _, ae := range errs {
+		if ae.Code != "" {
+			codes = append(codes, ae.Code)
+		}
+	}
+	return codes
+}
+
+// HasCode reports whether the response carried the JSON:API error code.
+func (e *HTTPError) HasCode(code string) bool {
+	return slices.Contains(e.Codes(), code)
+}
+
+// hasErrorCode reports whether err is an *HTTPError carrying code.
+func hasErrorCode(err error, code string) bool {
+	var herr *HTTPError
+	return errors.As(err, &herr) && herr.HasCode(code)
+}
+
+// isTransient reports whether err is worth retrying later: network failures,
+// timeouts, rate limiting, 5xx responses and an open circuit breaker. Other
+// API rejections, TLS/certificate failures and cancellation by the caller are
+// final.
+func isTransient(err error) bool {
+	var herr *HTTPError
+	if errors.As(err, &herr) {
+		return herr.StatusCode == 429 || herr.StatusCode >= 500
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrCircuitOpen) {
+		return true
+	}
+	if errors.Is(err, context.Canceled) || isTLSFailure(err) {
+		return false
+	}
+	// *url.Error is itself a net.Error: classify what it wraps.
+	var uerr *url.Error
+	if errors.As(err, &uerr) {
+		err = uerr.Err
+	}
+	var nerr net.Error
+	return errors.As(err, &nerr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// isTLSFailure reports certificate and handshake failures, which a retry
+// cannot fix (or which signal an interception).
+func isTLSFailure(err error) bool {
+	var (
+		verr  *tls.CertificateVerificationError
+		rerr  tls.RecordHeaderError
+		aerr  tls.AlertError
+		uaerr x509.UnknownAuthorityError
+		hoerr x509.HostnameError
+		ierr  x509.CertificateInvalidError
+	)
+	return errors.Is(err, ErrCertificatePin) || errors.As(err, &verr) || errors.As(err, &rerr) ||
+		errors.As(err, &aerr) || errors.As(err, &uaerr) || errors.As(err, &hoerr) || errors.As(err, &ierr)
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an
+// HTTP date.
+func parseRetryAfter(v string, now time.Time) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
@@ -0,0 +1,262 @@
+package keygen
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestListDeadMachines_PartialResultOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page[number]") == "1" {
+			_, _ = w.Write([]byte(`{"data":[
+				{"id":"m1","type":"machines","attributes":{"fingerprint":"fp1","created":"2024-01-01T00:00:00Z"}},
+				{"id":"m2","type":"machines","attributes":{"fingerprint":"fp2","created":"2999-01-01T00:00:00Z"}}],"links":{"next":"/next"}}`))
+			return
+		}
+		cancel()
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	c := New("acct", "token", WithBaseURL(srv.URL))
+	got, err := c.ListDeadMachines(ctx, time.Hour)
+
+	var partial *PartialResultError
+	if !errors.As(err, &partial) {
+		t.Fatalf("expected *PartialResultError, got %v", err)
+	}
+	if len(got) != 1 || got[0].ID != "m1" {
+		t.Fatalf("unexpected partial result: %+v", got)
+	}
+}
+
+func TestListAllMachines_PartialResultOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page[number]") == "1" {
+			_, _ = w.Write([]byte(`{"data":[{"id":"m1","type":"machines","attributes":{"fingerprint":"fp1"}}],"links":{"next":"/next"}}`))
+			return
+		}
+		cancel()
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	c := New("acct", "token", WithBaseURL(srv.URL))
+	got, err := c.ListAllMachines(ctx)
+
+	var partial *PartialResultError
+	if !errors.As(err, &partial) {
+		t.Fatalf("expected *PartialResultError, got %v", err)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected error to wrap context.Canceled, got %v", err)
+	}
+	if partial.Pages != 1 || len(got) != 1 || got[0].ID != "m1" {
+		t.Fatalf("unexpected partial result: pages=%d machines=%+v", partial.Pages, got)
+	}
+}
+
+func TestEnsureMachineActivated_RotatesOldestOnLimit(t *testing.T) {
+	var deleted string
+	activations := 0
+	// Like an idempotency-honouring proxy, replay the first answer for a key.
+	replies := map[string]int{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if key := r.Header.Get("Idempotency-Key"); key != "" {
+			if status, ok := replies[key]; ok {
+				w.WriteHeader(status)
+				_, _ = w.Write([]byte(`{"errors":[{"title":"Replayed","code":"MACHINE_LIMIT_EXCEEDED"}]}`))
+				return
+			}
+			defer func() { replies[key] = http.StatusUnprocessableEntity }()
+		}
+		switch r.Method + " " + r.URL.Path {
+		case "POST /accounts/acct/licenses/actions/validate-key":
+			_, _ = w.Write([]byte(`{"data":{"id":"lic-1"}}`))
+		case "GET /accounts/acct/machines":
+			_, _ = w.Write([]byte(`{"data":[
+				{"id":"m2","attributes":{"fingerprint":"fp2","created":"2024-02-01T00:00:00Z"}},
+				{"id":"m1","attributes":{"fingerprint":"fp1","created":"2024-01-01T00:00:00Z"}}],"links":{}}`))
+		case "POST /accounts/acct/machines":
+			activations++
+			if deleted == "" {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				_, _ = w.Write([]byte(`{"errors":[{"title":"Unprocessable resource","code":"MACHINE_LIMIT_EXCEEDED"}]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"data":{"id":"m3","type":"machines","attributes":{"fingerprint":"fp3"}}}`))
+		case "DELETE /accounts/acct/machines/m1":
+			deleted = "m1"
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	c := New("acct", "token", WithBaseURL(srv.URL))
+	res, err := c.EnsureMachineActivated(context.Background(), "KEY", "fp3", EnsureMachineOptions{RotateOldest: true})
+	if err != nil {
+		t.Fatalf("EnsureMachineActivated: %v", err)
+	}
+	if !res.Activated || res.Machine.ID != "m3" || res.Rotated == nil || res.Rotated.ID != "m1" || activations != 2 {
+		t.Fatalf("unexpected result %+v (activations=%d)", res, activations)
+	}
+
+	res, err = c.EnsureMachineActivated(context.Background(), "KEY", "fp2", EnsureMachineOptions{})
+	if err != nil || res.Activated || res.Machine.ID != "m2" {
+		t.Fatalf("existing fingerprint: %+v, %v", res, err)
+	}
+}
+
+func TestHTTPError_ParsesAPIErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"errors":[{"title":"Unprocessable resource","detail":"has already been taken",` +
+			`"code":"FINGERPRINT_TAKEN","source":{"pointer":"/data/attributes/fingerprint"}}]}`))
+	}))
+	defer srv.Close()
+
+	c := New("acct", "token", WithBaseURL(srv.URL))
+	_, err := c.GetLicense(context.Background(), "lic-1")
+	var herr *HTTPError
+	if !errors.As(err, &herr) {
+		t.Fatalf("expected *HTTPError, got %v", err)
+	}
+	if len(herr.Errors) != 1 || herr.Errors[0].SourcePointer != "/data/attributes/fingerprint" || herr.Errors[0].Detail != "has already been taken" {
+		t.Fatalf("unexpected Errors: %+v", herr.Errors)
+	}
+	if !herr.HasCode("FINGERPRINT_TAKEN") || herr.HasCode("MACHINE_LIMIT_EXCEEDED") {
+		t.Fatalf("HasCode mismatch for %v", herr.Codes())
+	}
+}
+
+func TestHTTPError_Sentinels(t *testing.T) {
+	limit := &HTTPError{StatusCode: http.StatusUnprocessableEntity, Body: `{"errors":[{"code":"MACHINE_LIMIT_EXCEEDED"}]}`}
+	wrapped := fmt.Errorf("activate: %w", limit)
+	if !errors.Is(wrapped, ErrMachineLimitExceeded) || errors.Is(wrapped, ErrNotFound) {
+		t.Fatalf("machine limit error matched wrong sentinels")
+	}
+	for status, want := range map[int]error{
+		http.StatusNotFound:        ErrNotFound,
+		http.StatusUnauthorized:    ErrUnauthorized,
+		http.StatusForbidden:       ErrUnauthorized,
+		http.StatusTooManyRequests: ErrRateLimited,
+	} {
+		if err := (&HTTPError{StatusCode: status}); !errors.Is(err, want) {
+			t.Errorf("HTTP %d: expected errors.Is(%v)", status, want)
+		}
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	tlsSrv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer tlsSrv.Close()
+	closed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	closed.Close()
+
+	ctx := context.Background()
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+
+	for _, tc := range []struct {
+		name string
+		ctx  context.Context
+		url  string
+		want bool
+	}{
+		{"untrusted certificate", ctx, tlsSrv.URL, false},
+		{"cancelled by caller", cctx, tlsSrv.URL, false},
+		{"connection refused", ctx, closed.URL, true},
+	} {
+		err := New("acct", "token", WithBaseURL(tc.url)).DeleteLicense(tc.ctx, "lic")
+		if err == nil || isTransient(err) != tc.want {
+			t.Errorf("%s: isTransient(%v) != %v", tc.name, err, tc.want)
+		}
+	}
+}
+
+func TestExtendLicenseExpiry_RetriesOnPreconditionFailed(t *testing.T) {
+	expiry := "2030-01-01T00:00:00Z"
+	version, reads := 1, 0
+	var patched []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			reads++
+			if reads == 1 {
+				// Another writer extends the license right after our read.
+				defer func() { expiry, version = "2031-01-01T00:00:00Z", 2 }()
+			}
+			w.Header().Set("ETag", fmt.Sprintf(`"v%d"`, version))
+			_, _ = fmt.Fprintf(w, `{"data":{"id":"lic","attributes":{"expiry":%q}}}`, expiry)
+		case http.MethodPatch:
+			if r.Header.Get("If-Match") != fmt.Sprintf(`"v%d"`, version) {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				return
+			}
+			var req licenseUpdateRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			patched = append(patched, *req.Data.Attributes.Expiry)
+			_, _ = w.Write([]byte(`{"data":{"id":"lic"}}`))
+		}
+	}))
+	defer srv.Close()
+
+	c := New("acct", "token", WithBaseURL(srv.URL), WithCache(NewMemoryCache(), time.Minute))
+	got, err := c.ExtendLicenseExpiry(context.Background(), "lic", 24*time.Hour)
+	if err != nil {
+		t.Fatalf("ExtendLicenseExpiry: %v", err)
+	}
+	if want := "2031-01-02T00:00:00Z"; got.Format(time.RFC3339) != want || len(patched) != 1 || patched[0] != want || reads != 2 {
+		t.Fatalf("got %v, patched %q after %d reads", got, patched, reads)
+	}
+}
+
+func TestCloneLicense_CopiesMachinesByID(t *testing.T) {
+	var activated []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /accounts/acct/licenses/src":
+			_, _ = w.Write([]byte(`{"data":{"id":"src","attributes":{"key":"SRC-KEY","name":"Pro"}}}`))
+		case "GET /accounts/acct/licenses/src/entitlements":
+			_, _ = w.Write([]byte(`{"data":[],"links":{}}`))
+		case "GET /accounts/acct/machines":
+			_, _ = w.Write([]byte(`{"data":[{"id":"m1","attributes":{"fingerprint":"fp1"}},{"id":"m2","attributes":{"fingerprint":"fp2"}}],"links":{}}`))
+		case "POST /accounts/acct/licenses":
+			_, _ = w.Write([]byte(`{"data":{"id":"clone","attributes":{"key":"CLONE-KEY"}}}`))
+		case "POST /accounts/acct/machines":
+			var req createMachineRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			activated = append(activated, req.Data.Relationships.License.Data.ID+"/"+req.Data.Attributes.Fingerprint)
+			_, _ = w.Write([]byte(`{"data":{"id":"m","attributes":{}}}`))
+		default:
+			// Notably validate-key: the clone must not be validated.
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	c := New("acct", "token", WithBaseURL(srv.URL))
+	clone, err := c.CloneLicense(context.Background(), "src", "pol-2", CloneOptions{CopyMachines: true})
+	if err != nil || clone.ID != "clone" {
+		t.Fatalf("CloneLicense = %+v, %v", clone, err)
+	}
+	if len(activated) != 2 || activated[0] != "clone/fp1" || activated[1] != "clone/fp2" {
+		t.Fatalf("activations = %q", activated)
+	}
+}
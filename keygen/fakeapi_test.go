@@ -0,0 +1,94 @@
+package keygen
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeAPI is an httptest server answering the routes of account "acct".
+// Routes are keyed by method and path relative to the account, e.g.
+// "GET /policies/pol-1"; requests to any other route fail the test.
+type fakeAPI struct {
+	t   *testing.T
+	url string
+
+	mu     sync.Mutex
+	bodies map[string][]byte // last request body per route
+}
+
+func newFakeAPI(t *testing.T, routes map[string]http.HandlerFunc) *fakeAPI {
+	t.Helper()
+	f := &fakeAPI{t: t, bodies: map[string][]byte{}}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := r.Method + " " + strings.TrimPrefix(r.URL.Path, "/accounts/acct")
+		b, _ := io.ReadAll(r.Body)
+		f.mu.Lock()
+		f.bodies[route] = b
+		f.mu.Unlock()
+		r.Body = io.NopCloser(bytes.NewReader(b))
+
+		h, ok := routes[route]
+		if !ok {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		h(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	f.url = srv.URL
+	return f
+}
+
+// client returns a Client for account "acct" talking to the fake API.
+func (f *fakeAPI) client(opts ...Option) *Client {
+	return New("acct", "token", append([]Option{WithBaseURL(f.url)}, opts...)...)
+}
+
+// decodeBody decodes the last request body sent to route into v.
+func (f *fakeAPI) decodeBody(route string, v any) {
+	f.t.Helper()
+	f.mu.Lock()
+	b := f.bodies[route]
+	f.mu.Unlock()
+	if err := json.Unmarshal(b, v); err != nil {
+		f.t.Fatalf("decode %s body %q: %v", route, b, err)
+	}
+}
+
+// attributes returns data.attributes of the last request body sent to
+// route, so tests can tell omitted attributes from null ones.
+func (f *fakeAPI) attributes(route string) map[string]any {
+	f.t.Helper()
+	var body struct {
+		Data struct {
+			Attributes map[string]any `json:"attributes"`
+		} `json:"data"`
+	}
+	f.decodeBody(route, &body)
+	return body.Data.Attributes
+}
+
+// jsonData answers with a single-resource document.
+func jsonData(resource string) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"data":` + resource + `}`))
+	}
+}
+
+// jsonList answers with a one-page list document.
+func jsonList(resources ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"data":[` + strings.Join(resources, ",") + `],"links":{}}`))
+	}
+}
+
+func noContent(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusNoContent)
+}
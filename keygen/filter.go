@@ -0,0 +1,132 @@
+package keygen
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// values encodes the filter as Keygen query parameters.
+func (f LicenseFilter) values() url.Values {
+	q := url.Values{}
+	setIfNotEmpty(q, "policy", f.Policy)
+	setIfNotEmpty(q, "product", f.Product)
+	setIfNotEmpty(q, "user", f.User)
+	setIfNotEmpty(q, "group", f.Group)
+	setIfNotEmpty(q, "status", f.Status)
+	for k, v := range f.Metadata {
+		q.Set("metadata["+k+"]", v)
+	}
+	if f.ExpiresWithin > 0 {
+		q.Set("expires[within]", formatQueryDuration(f.ExpiresWithin))
+	}
+	if !f.ExpiresBefore.IsZero() {
+		q.Set("expires[before]", f.ExpiresBefore.UTC().Format(time.RFC3339))
+	}
+	if !f.ExpiresAfter.IsZero() {
+		q.Set("expires[after]", f.ExpiresAfter.UTC().Format(time.RFC3339))
+	}
+	return q
+}
+
+// values encodes the filter as Keygen query parameters.
+func (f MachineFilter) values() url.Values {
+	q := url.Values{}
+	setIfNotEmpty(q, "license", f.License)
+	setIfNotEmpty(q, "policy", f.Policy)
+	setIfNotEmpty(q, "product", f.Product)
+	setIfNotEmpty(q, "user", f.User)
+	setIfNotEmpty(q, "fingerprint", f.Fingerprint)
+	setIfNotEmpty(q, "platform", f.Platform)
+	setIfNotEmpty(q, "status", f.HeartbeatStatus)
+	for k, v := range f.Metadata {
+		q.Set("metadata["+k+"]", v)
+	}
+	return q
+}
+
+// values encodes the filter as Keygen query parameters.
+func (f UserFilter) values() url.Values {
+	q := url.Values{}
+	setIfNotEmpty(q, "status", f.Status)
+	setIfNotEmpty(q, "product", f.Product)
+	setIfNotEmpty(q, "group", f.Group)
+	for _, r := range f.Roles {
+		q.Add("roles[]", r)
+	}
+	for k, v := range f.Metadata {
+		q.Set("metadata["+k+"]", v)
+	}
+	return q
+}
+
+// values encodes the filter as Keygen query parameters.
+func (f RequestLogFilter) values() url.Values {
+	q := url.Values{}
+	if !f.Start.IsZero() {
+		q.Set("date[start]", f.Start.UTC().Format(time.DateOnly))
+	}
+	if !f.End.IsZero() {
+		q.Set("date[end]", f.End.UTC().Format(time.DateOnly))
+	}
+	if f.Status != 0 {
+		q.Set("status", strconv.Itoa(f.Status))
+	}
+	setIfNotEmpty(q, "method", f.Method)
+	setIfNotEmpty(q, "url", f.URL)
+	setIfNotEmpty(q, "ip", f.IP)
+	return q
+}
+
+// values encodes the filter as Keygen query parameters.
+func (f EventLogFilter) values() url.Values {
+	q := url.Values{}
+	if !f.Start.IsZero() {
+		q.Set("date[start]", f.Start.UTC().Format(time.DateOnly))
+	}
+	if !f.End.IsZero() {
+		q.Set("date[end]", f.End.UTC().Format(time.DateOnly))
+	}
+	setIfNotEmpty(q, "event", f.Event)
+	setIfNotEmpty(q, "resource[type]", f.ResourceType)
+	setIfNotEmpty(q, "resource[id]", f.ResourceID)
+	setIfNotEmpty(q, "whodunnit[type]", f.WhodunnitType)
+	setIfNotEmpty(q, "whodunnit[id]", f.WhodunnitID)
+	return q
+}
+
+// values encodes the filter as Keygen query parameters.
+func (f ReleaseFilter) values() url.Values {
+	q := url.Values{}
+	setIfNotEmpty(q, "product", f.Product)
+	setIfNotEmpty(q, "package", f.Package)
+	setIfNotEmpty(q, "channel", f.Channel)
+	setIfNotEmpty(q, "status", f.Status)
+	setIfNotEmpty(q, "platform", f.Platform)
+	for _, e := range f.Entitlements {
+		q.Add("entitlements[]", e)
+	}
+	return q
+}
+
+func setIfNotEmpty(q url.Values, k, v string) {
+	if v != "" {
+		q.Set(k, v)
+	}
+}
+
+// formatQueryDuration renders d in the compact form Keygen filters accept
+// ("30d", "12h", "90m"), rounding down to the largest whole unit.
+func formatQueryDuration(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour && d%(24*time.Hour) == 0:
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	case d >= time.Hour && d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d >= time.Minute:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	default:
+		return fmt.Sprintf("%ds", d/time.Second)
+	}
+}
@@ -0,0 +1,133 @@
+// Package fingerprint derives stable machine fingerprints for Keygen machine
+// activation, so every dappnode package identifies a host the same way.
+//
+// On Linux the machine ID is read from /etc/machine-id (or the D-Bus copy),
+// falling back to the DMI product UUID and finally to the hardware MAC
+// address of one physical network interface. The raw identifier is never
+// sent: it is hashed together with an optional salt and product namespace.
+package fingerprint
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNoSource is returned when none of the identifier sources is available.
+var ErrNoSource = errors.New("fingerprint: no machine identifier available")
+
+// Option configures Generate.
+type Option func(*config)
+
+type config struct {
+	salt      string
+	namespace string
+	root      string                          // filesystem root, overridden in tests
+	ifaces    func() ([]net.Interface, error) // overridden in tests
+}
+
+// WithSalt mixes an application secret into the hash so fingerprints cannot
+// be correlated with the host's machine ID by third parties.
+func WithSalt(salt string) Option {
+	return func(c *config) { c.salt = salt }
+}
+
+// WithNamespace scopes fingerprints to a product, so the same host yields a
+// different fingerprint for each product.
+func WithNamespace(product string) Option {
+	return func(c *config) { c.namespace = product }
+}
+
+// Generate returns the host's fingerprint as a hex-encoded SHA-256 digest.
+func Generate(opts ...Option) (string, error) {
+	c := config{root: "/", ifaces: net.Interfaces}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	id, err := c.machineID()
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	for _, part := range []string{c.salt, c.namespace, id} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// machineID walks the source chain and returns the first identifier found,
+// prefixed with its source so sources never collide.
+func (c config) machineID() (string, error) {
+	for _, src := range fileSources {
+		b, err := os.ReadFile(filepath.Join(c.root, src.path))
+		if err != nil {
+			continue
+		}
+		if v := strings.ToLower(strings.TrimSpace(string(b))); v != "" && !src.invalid(v) {
+			return src.name + ":" + v, nil
+		}
+	}
+	if mac := c.hardwareMAC(); mac != "" {
+		return "mac:" + mac, nil
+	}
+	return "", ErrNoSource
+}
+
+// hardwareMAC returns the lowest globally administered MAC address among the
+// host's physical, non-loopback interfaces, or "" if there is none. A single
+// address is used so interfaces coming and going (a USB dongle, a VPN, a
+// docker network) cannot change the fingerprint unless they carry a lower
+// MAC. Locally administered addresses are skipped since docker bridges and
+// veth pairs get random ones that change across restarts, and virtual
+// devices are skipped even when their address looks global. The interface's
+// up/down state is ignored, since it changes with cabling.
+func (c config) hardwareMAC() string {
+	ifaces, err := c.ifaces()
+	if err != nil {
+		return ""
+	}
+	var lowest string
+	for _, ifc := range ifaces {
+		hw := ifc.HardwareAddr
+		if ifc.Flags&net.FlagLoopback != 0 || len(hw) == 0 || hw[0]&0x02 != 0 || c.virtual(ifc.Name) {
+			continue
+		}
+		if mac := hw.String(); lowest == "" || mac < lowest {
+			lowest = mac
+		}
+	}
+	return lowest
+}
+
+// virtualPrefixes name interfaces created by container runtimes,
+// hypervisors and VPNs, for platforms where sysfs cannot tell.
+var virtualPrefixes = []string{"docker", "br-", "veth", "virbr", "vnet", "vmnet", "vboxnet", "tun", "tap", "utun", "wg", "zt", "awdl", "llw", "bridge"}
+
+// virtual reports whether the interface is a virtual device: by name prefix,
+// or on Linux by its presence under /sys/devices/virtual/net.
+func (c config) virtual(name string) bool {
+	for _, p := range virtualPrefixes {
+		if strings.HasPrefix(name, p) {
+			return true
+		}
+	}
+	return virtualNetDevice(c.root, name)
+}
+
+type fileSource struct {
+	name    string
+	path    string
+	invalid func(string) bool
+}
+
+func none(string) bool { return false }
+
+// placeholderUUID rejects DMI UUIDs that vendors leave unset.
+func placeholderUUID(v string) bool {
+	return strings.Trim(v, "0-") == "" || strings.Trim(v, "f-") == ""
+}
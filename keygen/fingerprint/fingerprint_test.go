@@ -0,0 +1,79 @@
+package fingerprint
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func generateAt(t *testing.T, root string, ifaces []net.Interface, opts ...Option) (string, error) {
+	t.Helper()
+	opts = append(opts, func(c *config) {
+		c.root = root
+		c.ifaces = func() ([]net.Interface, error) { return ifaces, nil }
+	})
+	return Generate(opts...)
+}
+
+func TestGenerate_SaltAndNamespace(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "etc"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "etc/machine-id"), []byte("abc123\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := generateAt(t, root, nil)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if again, _ := generateAt(t, root, nil); again != a {
+		t.Fatal("fingerprint is not stable")
+	}
+	salted, _ := generateAt(t, root, nil, WithSalt("s"))
+	nsA, _ := generateAt(t, root, nil, WithNamespace("product-a"))
+	nsB, _ := generateAt(t, root, nil, WithNamespace("product-b"))
+	if salted == a || nsA == a || nsA == nsB {
+		t.Fatalf("salt/namespace do not change the fingerprint: %s %s %s %s", a, salted, nsA, nsB)
+	}
+}
+
+func TestGenerate_MACFallback(t *testing.T) {
+	root := t.TempDir()
+	docker := net.Interface{Name: "docker0", HardwareAddr: net.HardwareAddr{0x02, 0x42, 0xac, 0x11, 0, 2}}
+	eth := net.Interface{Name: "eth0", HardwareAddr: net.HardwareAddr{0x00, 0x1a, 0x2b, 0x3c, 0x4d, 0x5e}}
+
+	if _, err := generateAt(t, root, []net.Interface{docker}); !errors.Is(err, ErrNoSource) {
+		t.Fatalf("only virtual interfaces: expected ErrNoSource, got %v", err)
+	}
+	a, err := generateAt(t, root, []net.Interface{eth})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if b, _ := generateAt(t, root, []net.Interface{docker, eth}); b != a {
+		t.Fatal("virtual interface changed the fingerprint")
+	}
+
+	// Only the lowest physical MAC counts: a higher one coming and going,
+	// or a virtual device with a global-looking address, changes nothing.
+	wlan := net.Interface{Name: "wlan0", HardwareAddr: net.HardwareAddr{0x00, 0x2a, 0, 0, 0, 1}}
+	tun := net.Interface{Name: "tun0", HardwareAddr: net.HardwareAddr{0x00, 0x00, 0, 0, 0, 1}}
+	ifaces := []net.Interface{wlan, tun, eth}
+	if runtime.GOOS == "linux" {
+		vpn := net.Interface{Name: "vpn0", HardwareAddr: net.HardwareAddr{0x00, 0x00, 0, 0, 0, 2}}
+		if err := os.MkdirAll(filepath.Join(root, "sys/devices/virtual/net/vpn0"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		ifaces = append(ifaces, vpn)
+	}
+	if b, _ := generateAt(t, root, ifaces); b != a {
+		t.Fatal("extra interfaces changed the fingerprint")
+	}
+	if b, _ := generateAt(t, root, []net.Interface{wlan}); b == a {
+		t.Fatal("different physical interface yielded the same fingerprint")
+	}
+}
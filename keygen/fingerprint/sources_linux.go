@@ -0,0 +1,21 @@
+//go:build linux
+
+package fingerprint
+
+import (
+	"os"
+	"path/filepath"
+)
+
+var fileSources = []fileSource{
+	{name: "machine-id", path: "etc/machine-id", invalid: none},
+	{name: "machine-id", path: "var/lib/dbus/machine-id", invalid: none},
+	{name: "dmi", path: "sys/class/dmi/id/product_uuid", invalid: placeholderUUID},
+}
+
+// virtualNetDevice reports whether sysfs lists name as a virtual network
+// device (bridges, veth pairs, tun/tap, dummy, ...).
+func virtualNetDevice(root, name string) bool {
+	_, err := os.Stat(filepath.Join(root, "sys/devices/virtual/net", name))
+	return err == nil
+}
@@ -0,0 +1,9 @@
+//go:build !linux
+
+package fingerprint
+
+// Only the MAC address fallback is available outside Linux.
+var fileSources []fileSource
+
+// virtualNetDevice has no sysfs to consult; name prefixes are used instead.
+func virtualNetDevice(root, name string) bool { return false }
@@ -0,0 +1,87 @@
+package keygen
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// CreateGroup creates a group (e.g. for a reseller) with g's name, limits
+// and metadata.
+func (c *Client) CreateGroup(ctx context.Context, g Group) (Group, error) {
+	var req groupRequest
+	req.Data.Type = "groups"
+	req.Data.Attributes = groupAttributes{
+		Name:        &g.Name,
+		MaxUsers:    g.MaxUsers,
+		MaxLicenses: g.MaxLicenses,
+		MaxMachines: g.MaxMachines,
+	}
+	if g.Metadata != nil {
+		req.Data.Attributes.Metadata = &g.Metadata
+	}
+
+	var resp groupResponse
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("/accounts/%s/groups", c.accountID), req, &resp); err != nil {
+		return Group{}, err
+	}
+	return resp.Data.toGroup(), nil
+}
+
+// GetGroup returns a group by ID.
+func (c *Client) GetGroup(ctx context.Context, groupID string) (Group, error) {
+	path := fmt.Sprintf("/accounts/%s/groups/%s", c.accountID, groupID)
+
+	var resp groupResponse
+	if err := c.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return Group{}, err
+	}
+	return resp.Data.toGroup(), nil
+}
+
+// UpdateGroup PATCHes the attributes set in u (e.g. raising a reseller's
+// maxLicenses) and returns the updated group.
+func (c *Client) UpdateGroup(ctx context.Context, groupID string, u GroupUpdate) (Group, error) {
+	var req groupRequest
+	req.Data.Type = "groups"
+	req.Data.Attributes = groupAttributes{
+		Name:        u.Name,
+		MaxUsers:    u.MaxUsers,
+		MaxLicenses: u.MaxLicenses,
+		MaxMachines: u.MaxMachines,
+	}
+	if u.Metadata != nil {
+		req.Data.Attributes.Metadata = &u.Metadata
+	}
+	path := fmt.Sprintf("/accounts/%s/groups/%s", c.accountID, groupID)
+
+	var resp groupResponse
+	if err := c.do(ctx, http.MethodPatch, path, req, &resp); err != nil {
+		return Group{}, err
+	}
+	return resp.Data.toGroup(), nil
+}
+
+// DeleteGroup deletes a group. Its members are kept but leave the group.
+func (c *Client) DeleteGroup(ctx context.Context, groupID string) error {
+	path := fmt.Sprintf("/accounts/%s/groups/%s", c.accountID, groupID)
+	return c.do(ctx, http.MethodDelete, path, nil, nil)
+}
+
+// ListGroups lists the account's groups.
+func (c *Client) ListGroups(ctx context.Context) ([]Group, error) {
+	return listAll(ctx, c, "/groups", nil, groupResource.toGroup)
+}
+
+// AttachGroupOwners makes users (by ID) owners of a group, letting them
+// manage its members.
+func (c *Client) AttachGroupOwners(ctx context.Context, groupID string, userIDs []string) error {
+	path := fmt.Sprintf("/accounts/%s/groups/%s/owners", c.accountID, groupID)
+	return c.do(ctx, http.MethodPost, path, newRelationshipList("users", userIDs), nil)
+}
+
+// DetachGroupOwners removes users (by ID) from a group's owners.
+func (c *Client) DetachGroupOwners(ctx context.Context, groupID string, userIDs []string) error {
+	path := fmt.Sprintf("/accounts/%s/groups/%s/owners", c.accountID, groupID)
+	return c.do(ctx, http.MethodDelete, path, newRelationshipList("users", userIDs), nil)
+}
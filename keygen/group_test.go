@@ -0,0 +1,46 @@
+package keygen
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestGroups(t *testing.T) {
+	const resource = `{"id":"g1","attributes":{"name":"Reseller A","maxLicenses":50}}`
+	api := newFakeAPI(t, map[string]http.HandlerFunc{
+		"GET /groups/g1":         jsonData(resource),
+		"PATCH /groups/g1":       jsonData(resource),
+		"GET /groups":            jsonList(resource),
+		"POST /groups/g1/owners": noContent,
+	})
+
+	ctx := context.Background()
+	c := api.client()
+	maxLicenses := 50
+	g, err := c.UpdateGroup(ctx, "g1", GroupUpdate{MaxLicenses: &maxLicenses})
+	if err != nil || g.MaxLicenses == nil || *g.MaxLicenses != 50 {
+		t.Fatalf("UpdateGroup = %+v, %v", g, err)
+	}
+	var req groupRequest
+	api.decodeBody("PATCH /groups/g1", &req)
+	if a := req.Data.Attributes; a.MaxLicenses == nil || *a.MaxLicenses != 50 || a.Name != nil || a.MaxUsers != nil {
+		t.Fatalf("update attributes = %+v", a)
+	}
+
+	if g, err := c.GetGroup(ctx, "g1"); err != nil || g.Name != "Reseller A" {
+		t.Fatalf("GetGroup = %+v, %v", g, err)
+	}
+	if list, err := c.ListGroups(ctx); err != nil || len(list) != 1 || list[0].ID != "g1" {
+		t.Fatalf("ListGroups = %+v, %v", list, err)
+	}
+
+	if err := c.AttachGroupOwners(ctx, "g1", []string{"u1"}); err != nil {
+		t.Fatalf("AttachGroupOwners: %v", err)
+	}
+	var owners relationshipList
+	api.decodeBody("POST /groups/g1/owners", &owners)
+	if len(owners.Data) != 1 || owners.Data[0].Type != "users" || owners.Data[0].ID != "u1" {
+		t.Fatalf("owners body = %+v", owners)
+	}
+}
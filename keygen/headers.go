@@ -0,0 +1,22 @@
+package keygen
+
+// defaultUserAgent identifies this client to Keygen unless WithUserAgent is
+// set.
+const defaultUserAgent = "dappnode-keygen-client"
+
+// WithUserAgent sets the User-Agent header sent on every request, e.g.
+// "dappmanager/0.2.90". Keygen asks integrators to identify their clients.
+func WithUserAgent(ua string) Option {
+	return func(c *Client) {
+		if ua != "" {
+			c.userAgent = ua
+		}
+	}
+}
+
+// WithAPIVersion pins the Keygen API version (e.g. "1.7") through the
+// Keygen-Version header, so that server-side changes to response formats do
+// not break decoding. By default the account's version is used.
+func WithAPIVersion(version string) Option {
+	return func(c *Client) { c.apiVersion = version }
+}
@@ -0,0 +1,33 @@
+package keygen
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientHeaders(t *testing.T) {
+	var got http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	c := New("acct", "token", WithBaseURL(srv.URL))
+	if err := c.DeleteLicense(context.Background(), "lic"); err != nil {
+		t.Fatalf("DeleteLicense error: %v", err)
+	}
+	if got.Get("User-Agent") != defaultUserAgent || got.Get("Keygen-Version") != "" {
+		t.Fatalf("default headers: User-Agent %q, Keygen-Version %q", got.Get("User-Agent"), got.Get("Keygen-Version"))
+	}
+
+	c = New("acct", "token", WithBaseURL(srv.URL), WithUserAgent("dappmanager/0.2.90"), WithAPIVersion("1.7"))
+	if err := c.DeleteLicense(context.Background(), "lic"); err != nil {
+		t.Fatalf("DeleteLicense error: %v", err)
+	}
+	if got.Get("User-Agent") != "dappmanager/0.2.90" || got.Get("Keygen-Version") != "1.7" {
+		t.Fatalf("configured headers: User-Agent %q, Keygen-Version %q", got.Get("User-Agent"), got.Get("Keygen-Version"))
+	}
+}
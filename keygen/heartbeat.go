@@ -0,0 +1,152 @@
+package keygen
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// HeartbeatManager keeps a machine alive by pinging its heartbeat in the
+// background. Pings are spread with random jitter so a fleet restarted at
+// once does not hit the API in lockstep, and transient failures are retried
+// with backoff before being reported.
+type HeartbeatManager struct {
+	client    *Client
+	machineID string
+	interval  time.Duration
+	jitter    float64
+	onError   func(error)
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// defaultHeartbeatInterval is used for non-positive intervals: half of
+// Keygen's default 10-minute heartbeat duration.
+const defaultHeartbeatInterval = 5 * time.Minute
+
+// HeartbeatOption configures a HeartbeatManager.
+type HeartbeatOption func(*HeartbeatManager)
+
+// WithHeartbeatJitter randomizes each wait by up to ±fraction of the
+// interval (default 0.1). Zero disables jitter.
+func WithHeartbeatJitter(fraction float64) HeartbeatOption {
+	return func(h *HeartbeatManager) {
+		if fraction >= 0 && fraction < 1 {
+			h.jitter = fraction
+		}
+	}
+}
+
+// WithHeartbeatErrorHandler registers fn to be called when a ping still
+// fails after its retries. The loop keeps running afterwards.
+func WithHeartbeatErrorHandler(fn func(error)) HeartbeatOption {
+	return func(h *HeartbeatManager) { h.onError = fn }
+}
+
+// NewHeartbeatManager creates a manager pinging machineID every interval,
+// which should be comfortably below the policy's heartbeat duration. A
+// non-positive interval defaults to 5 minutes.
+func NewHeartbeatManager(c *Client, machineID string, interval time.Duration, opts ...HeartbeatOption) *HeartbeatManager {
+	if interval <= 0 {
+		interval = defaultHeartbeatInterval
+	}
+	h := &HeartbeatManager{
+		client:    c,
+		machineID: machineID,
+		interval:  interval,
+		jitter:    0.1,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// Start launches the heartbeat loop; the first ping is sent immediately.
+// The loop ends when ctx is cancelled or Stop is called, after which Start
+// may be called again. Calling Start on a running manager has no effect.
+func (h *HeartbeatManager) Start(ctx context.Context) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.done != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	h.cancel, h.done = cancel, done
+	go func() {
+		defer close(done)
+		h.run(ctx)
+		cancel()
+		// Forget the loop unless Stop (or a later Start) already did.
+		h.mu.Lock()
+		if h.done == done {
+			h.cancel, h.done = nil, nil
+		}
+		h.mu.Unlock()
+	}()
+}
+
+// Stop ends the heartbeat loop and waits for an in-flight ping to finish.
+func (h *HeartbeatManager) Stop() {
+	h.mu.Lock()
+	cancel, done := h.cancel, h.done
+	h.cancel, h.done = nil, nil
+	h.mu.Unlock()
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+}
+
+func (h *HeartbeatManager) run(ctx context.Context) {
+	for {
+		if err := h.ping(ctx); err != nil && ctx.Err() == nil && h.onError != nil {
+			h.onError(err)
+		}
+		if !sleepCtx(ctx, h.nextWait()) {
+			return
+		}
+	}
+}
+
+// ping sends one heartbeat, retrying transient failures with exponential
+// backoff for at most half an interval.
+func (h *HeartbeatManager) ping(ctx context.Context) error {
+	deadline := time.Now().Add(h.interval / 2)
+	backoff := time.Second
+	for {
+		err := h.client.pingMachineHeartbeat(ctx, h.machineID)
+		if err == nil || !isTransient(err) || time.Now().Add(backoff).After(deadline) {
+			return err
+		}
+		if !sleepCtx(ctx, backoff) {
+			return err
+		}
+		backoff *= 2
+	}
+}
+
+func (h *HeartbeatManager) nextWait() time.Duration {
+	if h.jitter == 0 {
+		return h.interval
+	}
+	delta := (rand.Float64()*2 - 1) * h.jitter * float64(h.interval)
+	return h.interval + time.Duration(delta)
+}
+
+// sleepCtx waits for d and reports false if ctx was cancelled first.
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
@@ -0,0 +1,84 @@
+package keygen
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHeartbeatManager_PingsAndReportsErrors(t *testing.T) {
+	var pings atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/accounts/acct/machines/m1/actions/ping" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if pings.Add(1) > 2 {
+			w.WriteHeader(http.StatusNotFound) // machine deleted: not retried
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	errs := make(chan error, 10)
+	h := NewHeartbeatManager(New("acct", "token", WithBaseURL(srv.URL)), "m1", 10*time.Millisecond,
+		WithHeartbeatJitter(0),
+		WithHeartbeatErrorHandler(func(err error) { errs <- err }))
+	h.Start(context.Background())
+
+	select {
+	case err := <-errs:
+		if !errors.Is(err, ErrNotFound) {
+			t.Fatalf("reported error = %v, want ErrNotFound", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no error reported")
+	}
+	h.Stop()
+
+	n := pings.Load()
+	time.Sleep(30 * time.Millisecond)
+	if pings.Load() != n {
+		t.Fatal("pings continued after Stop")
+	}
+}
+
+func TestHeartbeatManager_RestartsAfterContextCancel(t *testing.T) {
+	var pings atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pings.Add(1)
+	}))
+	defer srv.Close()
+
+	h := NewHeartbeatManager(New("acct", "token", WithBaseURL(srv.URL)), "m1", 0)
+	if h.interval != defaultHeartbeatInterval {
+		t.Fatalf("interval = %v, want the default", h.interval)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	h.Start(ctx)
+	waitFor(t, func() bool { return pings.Load() == 1 })
+	cancel()
+	waitFor(t, func() bool {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		return h.done == nil
+	})
+
+	h.Start(context.Background())
+	defer h.Stop()
+	waitFor(t, func() bool { return pings.Load() == 2 })
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(5 * time.Second); !cond(); time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met")
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package keygen
+
+import (
+	"net/http"
+	"time"
+)
+
+// WithRequestHook registers fn to run on every API request just before it is
+// sent, e.g. to add tracing headers. Hooks run in registration order.
+func WithRequestHook(fn func(*http.Request)) Option {
+	return func(c *Client) { c.requestHooks = append(c.requestHooks, fn) }
+}
+
+// WithResponseHook registers fn to run on every API response with the time
+// taken to receive its headers, e.g. for logging or metrics. fn must not
+// read or close the body. It is not called when the request fails before a
+// response arrives.
+func WithResponseHook(fn func(*http.Response, time.Duration)) Option {
+	return func(c *Client) { c.responseHooks = append(c.responseHooks, fn) }
+}
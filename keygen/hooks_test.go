@@ -0,0 +1,31 @@
+package keygen
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHooks(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Trace-Id") != "trace-1" {
+			t.Errorf("request hook header missing")
+		}
+		_, _ = w.Write([]byte(`{"data":{"id":"lic-1"}}`))
+	}))
+	defer srv.Close()
+
+	var status int
+	c := New("acct", "token", WithBaseURL(srv.URL),
+		WithRequestHook(func(r *http.Request) { r.Header.Set("X-Trace-Id", "trace-1") }),
+		WithResponseHook(func(r *http.Response, d time.Duration) { status = r.StatusCode }),
+	)
+	if _, err := c.GetLicense(context.Background(), "lic-1"); err != nil {
+		t.Fatal(err)
+	}
+	if status != http.StatusOK {
+		t.Fatalf("response hook saw status %d", status)
+	}
+}
@@ -0,0 +1,95 @@
+package keygen
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+)
+
+type idempotencyKey struct{}
+
+// ContextWithIdempotencyKey makes the mutating calls made with ctx send key
+// in an Idempotency-Key header, so that a proxy or API honouring it can drop
+// replays of the same logical operation.
+//
+// Keys belong to one logical operation: create one with NewIdempotencyKey
+// and reuse it when retrying that operation, never for a later one. Calls
+// made without a key (CreateLicenseOnce, ActivateMachine,
+// EnsureMachineActivated) generate a fresh one that only covers their own
+// internal retries.
+func ContextWithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKey{}, key)
+}
+
+func idempotencyKeyFrom(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKey{}).(string)
+	return key
+}
+
+// NewIdempotencyKey returns a random key for one logical operation. Keys are
+// never derived from the resource, so a later operation on it (e.g. a
+// re-activation after a deactivation, or recreating a deleted license) is
+// not deduplicated against an earlier one.
+func NewIdempotencyKey() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// idempotencyKeyOr returns ctx with its caller-supplied key, or with a fresh
+// one if it has none.
+func idempotencyKeyOr(ctx context.Context) (context.Context, string) {
+	if key := idempotencyKeyFrom(ctx); key != "" {
+		return ctx, key
+	}
+	key := NewIdempotencyKey()
+	return ContextWithIdempotencyKey(ctx, key), key
+}
+
+// CreateLicenseOnce creates a license for meta.SubscriptionID unless one
+// already exists under policyID, so that retried webhook deliveries or
+// network retries for one subscription never create two licenses. created
+// reports whether a new license was made.
+//
+// When creation fails with a transient error the request may still have
+// landed, so the lookup is repeated before the error is returned. The
+// creation is sent under ctx's idempotency key (see
+// ContextWithIdempotencyKey); pass the same key when retrying the call.
+func (c *Client) CreateLicenseOnce(ctx context.Context, policyID string, meta LicenseMetadata, opts ...LicenseOption) (lic CreatedLicense, created bool, err error) {
+	if meta.SubscriptionID == "" {
+		return CreatedLicense{}, false, fmt.Errorf("keygen: CreateLicenseOnce needs a subscription ID")
+	}
+	if lic, ok, err := c.findSubscriptionLicense(ctx, policyID, meta.SubscriptionID); err != nil || ok {
+		return lic, false, err
+	}
+
+	ctx, _ = idempotencyKeyOr(ctx)
+	lic, err = c.CreateLicenseFull(ctx, policyID, meta, opts...)
+	if err == nil {
+		return lic, true, nil
+	}
+	if isTransient(err) {
+		if found, ok, ferr := c.findSubscriptionLicense(ctx, policyID, meta.SubscriptionID); ferr == nil && ok {
+			return found, true, nil
+		}
+	}
+	return CreatedLicense{}, false, err
+}
+
+// findSubscriptionLicense returns the license of policyID whose metadata
+// carries subscriptionID, if any.
+func (c *Client) findSubscriptionLicense(ctx context.Context, policyID, subscriptionID string) (CreatedLicense, bool, error) {
+	existing, err := c.ListLicenses(ctx, LicenseFilter{
+		Policy:   policyID,
+		Metadata: map[string]string{"subscriptionId": subscriptionID},
+	})
+	if err != nil || len(existing) == 0 {
+		return CreatedLicense{}, false, err
+	}
+	l, err := c.GetLicense(ctx, existing[0].ID)
+	if err != nil {
+		return CreatedLicense{}, false, err
+	}
+	return CreatedLicense{ID: l.ID, Key: l.Key, Expiry: l.Expiry, Status: l.Status}, true, nil
+}
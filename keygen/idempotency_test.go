@@ -0,0 +1,68 @@
+package keygen
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCreateLicenseOnce(t *testing.T) {
+	landed, posts := false, 0
+	var keys []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /accounts/acct/licenses":
+			if r.URL.Query().Get("metadata[subscriptionId]") != "sub_1" {
+				t.Errorf("unexpected lookup query: %v", r.URL.Query())
+			}
+			if !landed {
+				_, _ = w.Write([]byte(`{"data":[],"links":{}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"data":[{"id":"lic-1","attributes":{"key":"KEY-1"}}],"links":{}}`))
+		case "GET /accounts/acct/licenses/lic-1":
+			_, _ = w.Write([]byte(`{"data":{"id":"lic-1","attributes":{"key":"KEY-1","status":"ACTIVE"}}}`))
+		case "POST /accounts/acct/licenses":
+			posts++
+			keys = append(keys, r.Header.Get("Idempotency-Key"))
+			// The license is created but the response is lost.
+			landed = true
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	c := New("acct", "token", WithBaseURL(srv.URL))
+	meta := LicenseMetadata{SubscriptionID: "sub_1", CustomerEmail: "a@example.com"}
+	ctx := ContextWithIdempotencyKey(context.Background(), "op-1")
+	lic, created, err := c.CreateLicenseOnce(ctx, "pol-1", meta)
+	if err != nil || !created || lic.Key != "KEY-1" {
+		t.Fatalf("first call = %+v, %v, %v", lic, created, err)
+	}
+	lic, created, err = c.CreateLicenseOnce(ctx, "pol-1", meta)
+	if err != nil || created || lic.ID != "lic-1" || posts != 1 {
+		t.Fatalf("retry = %+v, %v, %v (posts=%d)", lic, created, err, posts)
+	}
+	if len(keys) == 0 || keys[0] != "op-1" {
+		t.Fatalf("caller key not sent: %q", keys)
+	}
+
+	// Without a caller key each call gets its own random one, so recreating
+	// a deleted subscription license is never replayed from the first.
+	landed = false
+	if _, _, err := c.CreateLicenseOnce(context.Background(), "pol-1", meta); err != nil {
+		t.Fatalf("CreateLicenseOnce: %v", err)
+	}
+	landed = false
+	if _, _, err := c.CreateLicenseOnce(context.Background(), "pol-1", meta); err != nil {
+		t.Fatalf("CreateLicenseOnce: %v", err)
+	}
+	last := keys[len(keys)-2:]
+	if last[0] == "" || last[0] == last[1] || last[0] == "op-1" {
+		t.Fatalf("generated keys = %q", last)
+	}
+}
@@ -0,0 +1,250 @@
+// Package licensefile decodes and verifies Keygen license file certificates
+// (the "-----BEGIN LICENSE FILE-----" blobs returned by CheckoutLicense), so
+// air-gapped devices can validate a license without reaching the API.
+//
+// A certificate wraps a base64 JSON envelope {"enc","sig","alg"}. The
+// signature covers "license/<enc>" and is verified with the account's
+// Ed25519 public key. When the file was checked out with encryption, enc is
+// "<ciphertext>.<iv>.<tag>" sealed with AES-256-GCM under SHA-256(license
+// key). Machine files ("-----BEGIN MACHINE FILE-----") use the same format
+// with a "machine/" prefix and SHA-256(license key + fingerprint) as the key.
+package licensefile
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalid is returned for malformed certificates and signature
+	// mismatches.
+	ErrInvalid = errors.New("licensefile: invalid certificate")
+	// ErrExpired is returned alongside the decoded dataset when the file's
+	// TTL has elapsed; the caller decides whether to honour a grace period.
+	ErrExpired = errors.New("licensefile: certificate expired")
+	// ErrKeyRequired is returned when an encrypted file is verified without a
+	// decryption key.
+	ErrKeyRequired = errors.New("licensefile: encrypted certificate needs a key")
+)
+
+// Verifier checks certificates against an account's Ed25519 public key.
+type Verifier struct {
+	key ed25519.PublicKey
+	now func() time.Time // overridden in tests
+}
+
+// NewVerifier returns a Verifier for the account's hex-encoded Ed25519 public
+// key (Account.Ed25519PublicKey).
+func NewVerifier(publicKeyHex string) (*Verifier, error) {
+	key, err := hex.DecodeString(publicKeyHex)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("licensefile: malformed Ed25519 public key")
+	}
+	return &Verifier{key: key, now: time.Now}, nil
+}
+
+// Dataset is the verified content of a certificate.
+type Dataset struct {
+	License      License           `json:"license"`
+	Entitlements []string          `json:"entitlements,omitempty"` // codes, when checked out with include=entitlements
+	Issued       time.Time         `json:"issued"`
+	Expiry       time.Time         `json:"expiry"`
+	TTL          time.Duration     `json:"ttl"`
+	Data         json.RawMessage   `json:"data"`     // raw primary resource
+	Included     []json.RawMessage `json:"included"` // raw included resources
+}
+
+// License holds the commonly used attributes of the embedded license (or,
+// for machine files, of the machine's license when included).
+type License struct {
+	ID       string         `json:"id"`
+	Name     string         `json:"name,omitempty"`
+	Key      string         `json:"key,omitempty"`
+	Status   string         `json:"status,omitempty"`
+	Expiry   *time.Time     `json:"expiry,omitempty"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+}
+
+// Expired reports whether the certificate's TTL has elapsed at now. Files
+// checked out without a TTL never expire.
+func (d *Dataset) Expired(now time.Time) bool {
+	return !d.Expiry.IsZero() && now.After(d.Expiry)
+}
+
+// Verify decodes certificate, checks its signature, decrypts it with key if
+// it is encrypted (the license key; license key + fingerprint for machine
+// files) and returns the embedded dataset. An expired but otherwise valid
+// file returns the dataset together with ErrExpired.
+func (v *Verifier) Verify(certificate, key string) (*Dataset, error) {
+	prefix, body, err := decodeCertificate(certificate)
+	if err != nil {
+		return nil, err
+	}
+	var env struct {
+		Enc string `json:"enc"`
+		Sig string `json:"sig"`
+		Alg string `json:"alg"`
+	}
+	if err := json.Unmarshal(body, &env); err != nil {
+		return nil, fmt.Errorf("%w: malformed envelope", ErrInvalid)
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(env.Sig)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature", ErrInvalid)
+	}
+	if !strings.HasSuffix(env.Alg, "+ed25519") {
+		return nil, fmt.Errorf("%w: unsupported algorithm %q", ErrInvalid, env.Alg)
+	}
+	if !ed25519.Verify(v.key, []byte(prefix+"/"+env.Enc), sig) {
+		return nil, fmt.Errorf("%w: signature mismatch", ErrInvalid)
+	}
+
+	var plain []byte
+	switch env.Alg {
+	case "base64+ed25519":
+		if plain, err = base64.StdEncoding.DecodeString(env.Enc); err != nil {
+			return nil, fmt.Errorf("%w: malformed payload", ErrInvalid)
+		}
+	case "aes-256-gcm+ed25519":
+		if key == "" {
+			return nil, ErrKeyRequired
+		}
+		if plain, err = decrypt(env.Enc, key); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%w: unsupported algorithm %q", ErrInvalid, env.Alg)
+	}
+
+	d, err := decodeDataset(plain)
+	if err != nil {
+		return nil, err
+	}
+	if d.Expired(v.now()) {
+		return d, ErrExpired
+	}
+	return d, nil
+}
+
+// decodeCertificate strips the BEGIN/END armour and returns the signing
+// prefix ("license" or "machine") with the decoded envelope.
+func decodeCertificate(cert string) (prefix string, body []byte, err error) {
+	cert = strings.TrimSpace(cert)
+	for _, p := range []string{"license", "machine"} {
+		kind := strings.ToUpper(p) + " FILE"
+		begin, end := "-----BEGIN "+kind+"-----", "-----END "+kind+"-----"
+		if strings.HasPrefix(cert, begin) && strings.HasSuffix(cert, end) {
+			enc := strings.Join(strings.Fields(cert[len(begin):len(cert)-len(end)]), "")
+			body, err := base64.StdEncoding.DecodeString(enc)
+			if err != nil {
+				return "", nil, fmt.Errorf("%w: malformed base64 body", ErrInvalid)
+			}
+			return p, body, nil
+		}
+	}
+	return "", nil, fmt.Errorf("%w: missing license or machine file header", ErrInvalid)
+}
+
+// decrypt opens a "<ciphertext>.<iv>.<tag>" payload sealed with AES-256-GCM
+// under SHA-256(key).
+func decrypt(enc, key string) ([]byte, error) {
+	parts := strings.Split(enc, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed encrypted payload", ErrInvalid)
+	}
+	var raw [3][]byte
+	for i, p := range parts {
+		b, err := base64.StdEncoding.DecodeString(p)
+		if err != nil {
+			return nil, fmt.Errorf("%w: malformed encrypted payload", ErrInvalid)
+		}
+		raw[i] = b
+	}
+	ciphertext, iv, tag := raw[0], raw[1], raw[2]
+	if len(iv) == 0 {
+		return nil, fmt.Errorf("%w: missing IV", ErrInvalid)
+	}
+
+	sum := sha256.Sum256([]byte(key))
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCMWithNonceSize(block, len(iv))
+	if err != nil {
+		return nil, err
+	}
+	plain, err := gcm.Open(nil, iv, append(ciphertext, tag...), nil)
+	if err != nil {
+		return nil, fmt.Errorf("licensefile: decrypt: wrong key or corrupted payload")
+	}
+	return plain, nil
+}
+
+// decodeDataset extracts the license, entitlement codes and TTL metadata
+// from the decrypted JSON:API document.
+func decodeDataset(plain []byte) (*Dataset, error) {
+	type resource struct {
+		ID         string          `json:"id"`
+		Type       string          `json:"type"`
+		Attributes json.RawMessage `json:"attributes"`
+	}
+	var doc struct {
+		Data     json.RawMessage   `json:"data"`
+		Included []json.RawMessage `json:"included"`
+		Meta     struct {
+			Issued time.Time `json:"issued"`
+			Expiry time.Time `json:"expiry"`
+			TTL    int       `json:"ttl"` // seconds
+		} `json:"meta"`
+	}
+	if err := json.Unmarshal(plain, &doc); err != nil {
+		return nil, fmt.Errorf("%w: malformed dataset", ErrInvalid)
+	}
+	d := &Dataset{
+		Issued:   doc.Meta.Issued,
+		Expiry:   doc.Meta.Expiry,
+		TTL:      time.Duration(doc.Meta.TTL) * time.Second,
+		Data:     doc.Data,
+		Included: doc.Included,
+	}
+
+	all := append([]json.RawMessage{doc.Data}, doc.Included...)
+	for _, raw := range all {
+		if len(raw) == 0 {
+			continue
+		}
+		var r resource
+		if err := json.Unmarshal(raw, &r); err != nil {
+			return nil, fmt.Errorf("%w: malformed resource", ErrInvalid)
+		}
+		switch r.Type {
+		case "licenses":
+			if d.License.ID != "" {
+				continue
+			}
+			if err := json.Unmarshal(r.Attributes, &d.License); err != nil {
+				return nil, fmt.Errorf("%w: malformed license", ErrInvalid)
+			}
+			d.License.ID = r.ID
+		case "entitlements":
+			var a struct {
+				Code string `json:"code"`
+			}
+			if err := json.Unmarshal(r.Attributes, &a); err == nil && a.Code != "" {
+				d.Entitlements = append(d.Entitlements, a.Code)
+			}
+		}
+	}
+	return d, nil
+}
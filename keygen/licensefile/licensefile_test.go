@@ -0,0 +1,96 @@
+package licensefile
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+const dataset = `{"data":{"id":"lic-1","type":"licenses","attributes":{"key":"ABC-123","status":"ACTIVE"}},` +
+	`"included":[{"id":"ent-1","type":"entitlements","attributes":{"code":"PRO"}}],` +
+	`"meta":{"issued":"2026-01-01T00:00:00Z","expiry":"2026-02-01T00:00:00Z","ttl":2678400}}`
+
+// certificate builds a license file the way Keygen does; a non-empty
+// licenseKey encrypts the dataset.
+func certificate(t *testing.T, priv ed25519.PrivateKey, licenseKey string) string {
+	t.Helper()
+	alg, enc := "base64+ed25519", base64.StdEncoding.EncodeToString([]byte(dataset))
+	if licenseKey != "" {
+		sum := sha256.Sum256([]byte(licenseKey))
+		block, _ := aes.NewCipher(sum[:])
+		gcm, _ := cipher.NewGCM(block)
+		iv := make([]byte, gcm.NonceSize())
+		sealed := gcm.Seal(nil, iv, []byte(dataset), nil)
+		ct, tag := sealed[:len(sealed)-gcm.Overhead()], sealed[len(sealed)-gcm.Overhead():]
+		b64 := base64.StdEncoding.EncodeToString
+		alg, enc = "aes-256-gcm+ed25519", b64(ct)+"."+b64(iv)+"."+b64(tag)
+	}
+	sig := ed25519.Sign(priv, []byte("license/"+enc))
+	env, err := json.Marshal(map[string]string{"enc": enc, "sig": base64.StdEncoding.EncodeToString(sig), "alg": alg})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return "-----BEGIN LICENSE FILE-----\n" + base64.StdEncoding.EncodeToString(env) + "\n-----END LICENSE FILE-----\n"
+}
+
+func newTestVerifier(t *testing.T, pub ed25519.PublicKey, now string) *Verifier {
+	t.Helper()
+	v, err := NewVerifier(hex.EncodeToString(pub))
+	if err != nil {
+		t.Fatal(err)
+	}
+	at, _ := time.Parse(time.RFC3339, now)
+	v.now = func() time.Time { return at }
+	return v
+}
+
+func TestVerify(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	v := newTestVerifier(t, pub, "2026-01-15T00:00:00Z")
+
+	for _, key := range []string{"", "ABC-123"} {
+		d, err := v.Verify(certificate(t, priv, key), key)
+		if err != nil {
+			t.Fatalf("Verify (key %q): %v", key, err)
+		}
+		if d.License.ID != "lic-1" || d.License.Key != "ABC-123" || len(d.Entitlements) != 1 || d.Entitlements[0] != "PRO" {
+			t.Fatalf("unexpected dataset: %+v", d)
+		}
+		if d.TTL != 31*24*time.Hour {
+			t.Fatalf("TTL = %v", d.TTL)
+		}
+	}
+}
+
+func TestVerify_Rejects(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	otherPub, _, _ := ed25519.GenerateKey(nil)
+	encrypted := certificate(t, priv, "ABC-123")
+
+	if _, err := newTestVerifier(t, otherPub, "2026-01-15T00:00:00Z").Verify(encrypted, "ABC-123"); !errors.Is(err, ErrInvalid) {
+		t.Errorf("wrong public key: got %v, want ErrInvalid", err)
+	}
+	v := newTestVerifier(t, pub, "2026-01-15T00:00:00Z")
+	if _, err := v.Verify(encrypted, ""); !errors.Is(err, ErrKeyRequired) {
+		t.Errorf("missing key: got %v, want ErrKeyRequired", err)
+	}
+	if _, err := v.Verify(encrypted, "WRONG"); err == nil {
+		t.Error("wrong license key: expected an error")
+	}
+	if _, err := v.Verify("not a certificate", ""); !errors.Is(err, ErrInvalid) {
+		t.Errorf("garbage: got %v, want ErrInvalid", err)
+	}
+
+	expired := newTestVerifier(t, pub, "2026-03-01T00:00:00Z")
+	d, err := expired.Verify(encrypted, "ABC-123")
+	if !errors.Is(err, ErrExpired) || d == nil || d.License.ID != "lic-1" {
+		t.Errorf("expired: got %+v, %v; want dataset and ErrExpired", d, err)
+	}
+}
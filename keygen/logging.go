@@ -0,0 +1,93 @@
+package keygen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// WithLogger emits one debug record per API request to l: method, route,
+// status, latency, retry count and Keygen request ID. Credentials, bodies,
+// resource IDs (a license key may stand in for one) and query values are
+// never logged; failed API calls are logged by status and error codes.
+func WithLogger(l *slog.Logger) Option {
+	return func(c *Client) { c.logger = l }
+}
+
+type retryKey struct{}
+
+// withRetry records in ctx that the request is the n-th retry of an
+// operation.
+func withRetry(ctx context.Context, n int) context.Context {
+	return context.WithValue(ctx, retryKey{}, n)
+}
+
+func (c *Client) logRequest(ctx context.Context, method, path string, r auditResponse, latency time.Duration, err error) {
+	if !c.logger.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+	retry, _ := ctx.Value(retryKey{}).(int)
+	attrs := []slog.Attr{
+		slog.String("method", method),
+		slog.String("path", redactPath(path)),
+		slog.Int("status", r.status),
+		slog.Duration("latency", latency),
+		slog.Int("retry", retry),
+		slog.String("request_id", r.requestID),
+	}
+	if r.cached {
+		attrs = append(attrs, slog.Bool("cached", true))
+	}
+	if err != nil {
+		attrs = append(attrs, errorAttrs(err, path)...)
+	}
+	c.logger.LogAttrs(ctx, slog.LevelDebug, "keygen request", attrs...)
+}
+
+// errorAttrs describes err without response bodies or request URLs.
+func errorAttrs(err error, path string) []slog.Attr {
+	var herr *HTTPError
+	var uerr *url.Error
+	switch {
+	case errors.As(err, &herr):
+		return []slog.Attr{
+			slog.String("error", fmt.Sprintf("HTTP %d", herr.StatusCode)),
+			slog.Any("error_codes", herr.Codes()),
+		}
+	case errors.As(err, &uerr):
+		return []slog.Attr{slog.String("error", uerr.Op+": "+uerr.Err.Error())}
+	}
+	return []slog.Attr{slog.String("error", strings.ReplaceAll(err.Error(), path, redactPath(path)))}
+}
+
+// redactPath reduces path to its route: resource IDs (and license keys used
+// in their place) become ":id" and query values are dropped, e.g.
+// /accounts/:id/licenses/:id/actions/check-out?include=REDACTED. IDs sit at
+// odd positions, except after "actions" and "relationships".
+func redactPath(path string) string {
+	p, q, ok := strings.Cut(path, "?")
+	seg := strings.Split(strings.TrimPrefix(p, "/"), "/")
+	for i := 1; i < len(seg); i += 2 {
+		if seg[i] == "actions" {
+			i-- // the action name follows at an even position
+			continue
+		}
+		if seg[i-1] != "actions" && seg[i-1] != "relationships" {
+			seg[i] = ":id"
+		}
+	}
+	p = "/" + strings.Join(seg, "/")
+	if !ok {
+		return p
+	}
+	var names []string
+	for _, kv := range strings.Split(q, "&") {
+		name, _, _ := strings.Cut(kv, "=")
+		names = append(names, name+"=REDACTED")
+	}
+	return p + "?" + strings.Join(names, "&")
+}
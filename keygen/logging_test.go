@@ -0,0 +1,74 @@
+package keygen
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWithLogger(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	c := New("acct", "secret-token", WithBaseURL(srv.URL), WithLogger(logger))
+	_, _ = c.ListLicenses(withRetry(context.Background(), 2), LicenseFilter{Metadata: map[string]string{"email": "user@example.com"}})
+
+	out := buf.String()
+	for _, want := range []string{"method=GET", "status=404", "retry=2", "REDACTED"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log %q missing %q", out, want)
+		}
+	}
+	for _, secret := range []string{"secret-token", "user@example.com", "user%40example.com"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("log leaks %q: %s", secret, out)
+		}
+	}
+}
+
+func TestWithLogger_RedactsKeysAndBodies(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"errors":[{"title":"Not found","detail":"license KEY-SECRET-123 not found","code":"NOT_FOUND"}]}`))
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	c := New("acct", "secret-token", WithBaseURL(srv.URL), WithLogger(logger))
+	if _, err := c.GetLicenseByKey(context.Background(), "KEY-SECRET-123"); err == nil {
+		t.Fatalf("expected a 404")
+	}
+
+	out := buf.String()
+	for _, want := range []string{"path=/accounts/:id/licenses/:id", "status=404", "NOT_FOUND"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log %q missing %q", out, want)
+		}
+	}
+	if strings.Contains(out, "KEY-SECRET-123") {
+		t.Errorf("log leaks the license key: %s", out)
+	}
+}
+
+func TestRedactPath(t *testing.T) {
+	for in, want := range map[string]string{
+		"/accounts/a/licenses/KEY":                        "/accounts/:id/licenses/:id",
+		"/accounts/a/licenses/actions/validate-key":       "/accounts/:id/licenses/actions/validate-key",
+		"/accounts/a/licenses/l/actions/check-out?ttl=60": "/accounts/:id/licenses/:id/actions/check-out?ttl=REDACTED",
+		"/accounts/a/licenses/l/relationships/users":      "/accounts/:id/licenses/:id/relationships/users",
+		"/accounts/a/licenses/l/machines":                 "/accounts/:id/licenses/:id/machines",
+	} {
+		if got := redactPath(in); got != want {
+			t.Errorf("redactPath(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
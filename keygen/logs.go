@@ -0,0 +1,41 @@
+package keygen
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// ListRequestLogs lists the API requests matching f, newest first, e.g. to
+// pull the exact exchanges behind a customer's failed activation.
+func (c *Client) ListRequestLogs(ctx context.Context, f RequestLogFilter) ([]RequestLog, error) {
+	return listAll(ctx, c, "/request-logs", f.values(), requestLogResource.toRequestLog)
+}
+
+// GetRequestLog returns a request log by ID.
+func (c *Client) GetRequestLog(ctx context.Context, logID string) (RequestLog, error) {
+	path := fmt.Sprintf("/accounts/%s/request-logs/%s", c.accountID, logID)
+
+	var resp requestLogResponse
+	if err := c.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return RequestLog{}, err
+	}
+	return resp.Data.toRequestLog(), nil
+}
+
+// ListEventLogs lists the account events matching f, e.g. every suspension
+// of a license with its actor.
+func (c *Client) ListEventLogs(ctx context.Context, f EventLogFilter) ([]EventLog, error) {
+	return listAll(ctx, c, "/event-logs", f.values(), eventLogResource.toEventLog)
+}
+
+// GetEventLog returns an event log by ID.
+func (c *Client) GetEventLog(ctx context.Context, logID string) (EventLog, error) {
+	path := fmt.Sprintf("/accounts/%s/event-logs/%s", c.accountID, logID)
+
+	var resp eventLogResponse
+	if err := c.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return EventLog{}, err
+	}
+	return resp.Data.toEventLog(), nil
+}
@@ -0,0 +1,54 @@
+package keygen
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestListRequestLogs(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/accounts/acct/request-logs" || q.Get("date[start]") != "2024-03-01" || q.Get("status") != "422" {
+			t.Errorf("unexpected request %s?%s", r.URL.Path, r.URL.RawQuery)
+		}
+		_, _ = w.Write([]byte(`{"data":[{"id":"rl1","attributes":{"method":"POST","url":"/v1/accounts/acct/machines","status":"422"},
+			"relationships":{"requestor":{"data":{"type":"licenses","id":"lic-1"}},"resource":{"data":null}}}],"links":{}}`))
+	}))
+	defer srv.Close()
+
+	c := New("acct", "token", WithBaseURL(srv.URL))
+	logs, err := c.ListRequestLogs(context.Background(), RequestLogFilter{
+		Start:  time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+		Status: 422,
+	})
+	if err != nil || len(logs) != 1 {
+		t.Fatalf("ListRequestLogs = %+v, %v", logs, err)
+	}
+	if l := logs[0]; l.Status != 422 || l.RequestorType != "licenses" || l.RequestorID != "lic-1" || l.ResourceID != "" {
+		t.Fatalf("unexpected log %+v", l)
+	}
+}
+
+func TestListEventLogs(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/accounts/acct/event-logs" || q.Get("event") != "license.suspended" || q.Get("resource[id]") != "lic-1" {
+			t.Errorf("unexpected request %s?%s", r.URL.Path, r.URL.RawQuery)
+		}
+		_, _ = w.Write([]byte(`{"data":[{"id":"ev1","attributes":{"event":"license.suspended"},
+			"relationships":{"whodunnit":{"data":{"type":"users","id":"admin-1"}},"resource":{"data":{"type":"licenses","id":"lic-1"}},"request":{"data":{"type":"request-logs","id":"rl1"}}}}],"links":{}}`))
+	}))
+	defer srv.Close()
+
+	c := New("acct", "token", WithBaseURL(srv.URL))
+	logs, err := c.ListEventLogs(context.Background(), EventLogFilter{Event: "license.suspended", ResourceID: "lic-1"})
+	if err != nil || len(logs) != 1 {
+		t.Fatalf("ListEventLogs = %+v, %v", logs, err)
+	}
+	if l := logs[0]; l.WhodunnitID != "admin-1" || l.ResourceType != "licenses" || l.RequestLogID != "rl1" {
+		t.Fatalf("unexpected event %+v", l)
+	}
+}
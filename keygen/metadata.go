@@ -0,0 +1,388 @@
+package keygen
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/mail"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// WithMetadataValidator registers a check run on license metadata before any
+// create/update call is sent. Validators run in registration order and the
+// first error aborts the call, so malformed metadata never reaches Keygen.
+func WithMetadataValidator(v func(map[string]any) error) Option {
+	return func(c *Client) {
+		if v != nil {
+			c.metadataValidators = append(c.metadataValidators, v)
+		}
+	}
+}
+
+// validateMetadata runs the registered validators against meta, which may be
+// a LicenseMetadata or a map[string]any.
+func (c *Client) validateMetadata(meta any) error {
+	if len(c.metadataValidators) == 0 {
+		return nil
+	}
+	m, err := metadataMap(meta)
+	if err != nil {
+		return err
+	}
+	for _, v := range c.metadataValidators {
+		if err := v(m); err != nil {
+			return fmt.Errorf("keygen: invalid metadata: %w", err)
+		}
+	}
+	return nil
+}
+
+// metadataMap normalizes metadata into the generic JSON shape Keygen stores
+// (numbers become float64, structs become maps).
+func metadataMap(meta any) (map[string]any, error) {
+	b, err := json.Marshal(meta)
+	if err != nil {
+		return nil, fmt.Errorf("keygen: encode metadata: %w", err)
+	}
+	var m map[string]any
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("keygen: encode metadata: %w", err)
+	}
+	return m, nil
+}
+
+// --- JSON Schema ---
+
+// JSONSchemaValidator compiles a JSON Schema document into a metadata
+// validator suitable for WithMetadataValidator.
+//
+// Only the commonly needed subset of the specification is supported: type,
+// properties, required, additionalProperties (boolean or schema), enum,
+// const, minLength, maxLength, pattern, format (email, date-time, date, uuid),
+// minimum, maximum, items, minItems and maxItems. The annotations $schema,
+// $id, $comment, title, description, default and examples are accepted and
+// ignored. Any other keyword, or a format outside the list above, is a
+// compile error rather than a silently skipped check.
+func JSONSchemaValidator(schema []byte) (func(map[string]any) error, error) {
+	var raw any
+	if err := json.Unmarshal(schema, &raw); err != nil {
+		return nil, fmt.Errorf("keygen: parse schema: %w", err)
+	}
+	if err := checkSchemaKeywords("", raw); err != nil {
+		return nil, fmt.Errorf("keygen: compile schema: %w", err)
+	}
+	var s jsonSchema
+	if err := json.Unmarshal(schema, &s); err != nil {
+		return nil, fmt.Errorf("keygen: parse schema: %w", err)
+	}
+	if err := s.compile(); err != nil {
+		return nil, fmt.Errorf("keygen: compile schema: %w", err)
+	}
+	return func(m map[string]any) error {
+		return s.validate("metadata", m)
+	}, nil
+}
+
+type jsonSchema struct {
+	Type                 any                    `json:"type"`
+	Properties           map[string]*jsonSchema `json:"properties"`
+	Required             []string               `json:"required"`
+	AdditionalProperties json.RawMessage        `json:"additionalProperties"`
+	Enum                 []any                  `json:"enum"`
+	Const                *json.RawMessage       `json:"const"`
+	MinLength            *int                   `json:"minLength"`
+	MaxLength            *int                   `json:"maxLength"`
+	Pattern              string                 `json:"pattern"`
+	Format               string                 `json:"format"`
+	Minimum              *float64               `json:"minimum"`
+	Maximum              *float64               `json:"maximum"`
+	Items                *jsonSchema            `json:"items"`
+	MinItems             *int                   `json:"minItems"`
+	MaxItems             *int                   `json:"maxItems"`
+
+	pattern       *regexp.Regexp
+	noAdditional  bool
+	additional    *jsonSchema
+	constValue    any
+	hasConstValue bool
+}
+
+// schemaKeywords lists the keywords jsonSchema enforces (true) or accepts
+// as annotations without effect (false).
+var schemaKeywords = map[string]bool{
+	"type": true, "properties": true, "required": true, "additionalProperties": true,
+	"enum": true, "const": true, "minLength": true, "maxLength": true, "pattern": true,
+	"format": true, "minimum": true, "maximum": true, "items": true, "minItems": true,
+	"maxItems": true,
+
+	"$schema": false, "$id": false, "$comment": false, "title": false,
+	"description": false, "default": false, "examples": false,
+}
+
+var schemaFormats = map[string]bool{"email": true, "date-time": true, "date": true, "uuid": true}
+
+// checkSchemaKeywords rejects keywords and formats the validator would
+// otherwise ignore, so a schema never passes metadata it was meant to reject.
+func checkSchemaKeywords(path string, raw any) error {
+	obj, ok := raw.(map[string]any)
+	if !ok {
+		if _, isBool := raw.(bool); isBool && path != "" {
+			return nil // additionalProperties: false
+		}
+		return fmt.Errorf("%sschema must be an object", schemaPathPrefix(path))
+	}
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if _, ok := schemaKeywords[k]; !ok {
+			return fmt.Errorf("%sunsupported keyword %q", schemaPathPrefix(path), k)
+		}
+	}
+	if f, ok := obj["format"].(string); ok && !schemaFormats[f] {
+		return fmt.Errorf("%sunsupported format %q", schemaPathPrefix(path), f)
+	}
+	if props, ok := obj["properties"].(map[string]any); ok {
+		for name, p := range props {
+			if err := checkSchemaKeywords(joinSchemaPath(path, name), p); err != nil {
+				return err
+			}
+		}
+	}
+	for _, k := range []string{"additionalProperties", "items"} {
+		if sub, ok := obj[k]; ok {
+			if err := checkSchemaKeywords(joinSchemaPath(path, k), sub); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func joinSchemaPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func schemaPathPrefix(path string) string {
+	if path == "" {
+		return ""
+	}
+	return path + ": "
+}
+
+var uuidRe = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+func (s *jsonSchema) compile() error {
+	if s.Pattern != "" {
+		re, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("pattern %q: %w", s.Pattern, err)
+		}
+		s.pattern = re
+	}
+	if s.Const != nil {
+		if err := json.Unmarshal(*s.Const, &s.constValue); err != nil {
+			return fmt.Errorf("const: %w", err)
+		}
+		s.hasConstValue = true
+	}
+	if len(s.AdditionalProperties) > 0 {
+		var b bool
+		if err := json.Unmarshal(s.AdditionalProperties, &b); err == nil {
+			s.noAdditional = !b
+		} else {
+			s.additional = &jsonSchema{}
+			if err := json.Unmarshal(s.AdditionalProperties, s.additional); err != nil {
+				return fmt.Errorf("additionalProperties: %w", err)
+			}
+			if err := s.additional.compile(); err != nil {
+				return err
+			}
+		}
+	}
+	for name, p := range s.Properties {
+		if err := p.compile(); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	if s.Items != nil {
+		if err := s.Items.compile(); err != nil {
+			return fmt.Errorf("items: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *jsonSchema) validate(path string, v any) error {
+	if err := s.checkType(path, v); err != nil {
+		return err
+	}
+	if s.hasConstValue && !jsonEqual(v, s.constValue) {
+		return fmt.Errorf("%s: must equal %v", path, s.constValue)
+	}
+	if len(s.Enum) > 0 {
+		ok := false
+		for _, e := range s.Enum {
+			if jsonEqual(v, e) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return fmt.Errorf("%s: must be one of %v", path, s.Enum)
+		}
+	}
+
+	switch x := v.(type) {
+	case string:
+		return s.validateString(path, x)
+	case float64:
+		if s.Minimum != nil && x < *s.Minimum {
+			return fmt.Errorf("%s: must be >= %v", path, *s.Minimum)
+		}
+		if s.Maximum != nil && x > *s.Maximum {
+			return fmt.Errorf("%s: must be <= %v", path, *s.Maximum)
+		}
+	case []any:
+		if s.MinItems != nil && len(x) < *s.MinItems {
+			return fmt.Errorf("%s: must have at least %d items", path, *s.MinItems)
+		}
+		if s.MaxItems != nil && len(x) > *s.MaxItems {
+			return fmt.Errorf("%s: must have at most %d items", path, *s.MaxItems)
+		}
+		if s.Items != nil {
+			for i, it := range x {
+				if err := s.Items.validate(fmt.Sprintf("%s[%d]", path, i), it); err != nil {
+					return err
+				}
+			}
+		}
+	case map[string]any:
+		return s.validateObject(path, x)
+	}
+	return nil
+}
+
+func (s *jsonSchema) validateString(path, x string) error {
+	n := len([]rune(x))
+	if s.MinLength != nil && n < *s.MinLength {
+		return fmt.Errorf("%s: must be at least %d characters", path, *s.MinLength)
+	}
+	if s.MaxLength != nil && n > *s.MaxLength {
+		return fmt.Errorf("%s: must be at most %d characters", path, *s.MaxLength)
+	}
+	if s.pattern != nil && !s.pattern.MatchString(x) {
+		return fmt.Errorf("%s: must match %q", path, s.Pattern)
+	}
+	switch s.Format {
+	case "email":
+		if a, err := mail.ParseAddress(x); err != nil || a.Address != x {
+			return fmt.Errorf("%s: must be a valid email address", path)
+		}
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, x); err != nil {
+			return fmt.Errorf("%s: must be an RFC 3339 date-time", path)
+		}
+	case "date":
+		if _, err := time.Parse(time.DateOnly, x); err != nil {
+			return fmt.Errorf("%s: must be a YYYY-MM-DD date", path)
+		}
+	case "uuid":
+		if !uuidRe.MatchString(x) {
+			return fmt.Errorf("%s: must be a UUID", path)
+		}
+	}
+	return nil
+}
+
+func (s *jsonSchema) validateObject(path string, x map[string]any) error {
+	for _, r := range s.Required {
+		if v, ok := x[r]; !ok || v == nil {
+			return fmt.Errorf("%s.%s: is required", path, r)
+		}
+	}
+	// Deterministic order keeps error messages stable.
+	keys := make([]string, 0, len(x))
+	for k := range x {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		p, ok := s.Properties[k]
+		switch {
+		case ok:
+			if err := p.validate(path+"."+k, x[k]); err != nil {
+				return err
+			}
+		case s.noAdditional:
+			return fmt.Errorf("%s.%s: is not allowed", path, k)
+		case s.additional != nil:
+			if err := s.additional.validate(path+"."+k, x[k]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *jsonSchema) checkType(path string, v any) error {
+	var types []string
+	switch t := s.Type.(type) {
+	case nil:
+		return nil
+	case string:
+		types = []string{t}
+	case []any:
+		for _, e := range t {
+			if str, ok := e.(string); ok {
+				types = append(types, str)
+			}
+		}
+	}
+	for _, t := range types {
+		if jsonTypeMatches(t, v) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s: must be of type %s", path, strings.Join(types, " or "))
+}
+
+func jsonTypeMatches(t string, v any) bool {
+	switch t {
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "number":
+		_, ok := v.(float64)
+		return ok
+	case "integer":
+		f, ok := v.(float64)
+		return ok && f == math.Trunc(f)
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "object":
+		_, ok := v.(map[string]any)
+		return ok
+	case "array":
+		_, ok := v.([]any)
+		return ok
+	case "null":
+		return v == nil
+	}
+	return false
+}
+
+func jsonEqual(a, b any) bool {
+	ab, err1 := json.Marshal(a)
+	bb, err2 := json.Marshal(b)
+	return err1 == nil && err2 == nil && string(ab) == string(bb)
+}
@@ -0,0 +1,97 @@
+package keygen
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+const testMetadataSchema = `{
+	"type": "object",
+	"required": ["subscriptionId", "customerEmail"],
+	"properties": {
+		"subscriptionId": {"type": "string", "pattern": "^sub_", "minLength": 5},
+		"customerEmail": {"type": "string", "format": "email"},
+		"seats": {"type": "integer", "minimum": 1}
+	},
+	"additionalProperties": false
+}`
+
+func TestJSONSchemaValidator(t *testing.T) {
+	validate, err := JSONSchemaValidator([]byte(testMetadataSchema))
+	if err != nil {
+		t.Fatalf("JSONSchemaValidator: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		meta    map[string]any
+		wantErr string
+	}{
+		{"valid", map[string]any{"subscriptionId": "sub_123", "customerEmail": "a@b.io", "seats": 2.0}, ""},
+		{"missing subscription", map[string]any{"customerEmail": "a@b.io"}, "metadata.subscriptionId: is required"},
+		{"bad email", map[string]any{"subscriptionId": "sub_123", "customerEmail": "nope"}, "valid email"},
+		{"bad pattern", map[string]any{"subscriptionId": "cus_123", "customerEmail": "a@b.io"}, "must match"},
+		{"fractional seats", map[string]any{"subscriptionId": "sub_123", "customerEmail": "a@b.io", "seats": 1.5}, "type integer"},
+		{"unknown key", map[string]any{"subscriptionId": "sub_123", "customerEmail": "a@b.io", "x": "y"}, "metadata.x: is not allowed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validate(tt.meta)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCreateLicense_RejectsInvalidMetadataLocally(t *testing.T) {
+	validate, err := JSONSchemaValidator([]byte(testMetadataSchema))
+	if err != nil {
+		t.Fatalf("JSONSchemaValidator: %v", err)
+	}
+	// The base URL is unroutable: the call must fail before any request is sent.
+	c := New("acct", "token", WithBaseURL("http://127.0.0.1:0"), WithMetadataValidator(validate))
+	_, err = c.CreateLicense(context.Background(), "policy", LicenseMetadata{SubscriptionID: "sub_123", CustomerEmail: "bad"})
+	if err == nil || !strings.Contains(err.Error(), "invalid metadata") {
+		t.Fatalf("expected invalid metadata error, got %v", err)
+	}
+}
+
+func TestJSONSchemaValidator_RejectsUnsupportedKeywords(t *testing.T) {
+	tests := []struct {
+		schema  string
+		wantErr string
+	}{
+		{`{"type":"object","propertyNames":{"pattern":"^[a-z]+$"}}`, `unsupported keyword "propertyNames"`},
+		{`{"properties":{"seats":{"type":"integer","exclusiveMinimum":0}}}`, `seats: unsupported keyword "exclusiveMinimum"`},
+		{`{"items":{"oneOf":[{"type":"string"}]}}`, `items: unsupported keyword "oneOf"`},
+		{`{"properties":{"site":{"type":"string","format":"uri"}}}`, `site: unsupported format "uri"`},
+	}
+	for _, tt := range tests {
+		_, err := JSONSchemaValidator([]byte(tt.schema))
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("JSONSchemaValidator(%s) = %v, want error containing %q", tt.schema, err, tt.wantErr)
+		}
+	}
+
+	// Annotations are accepted.
+	if _, err := JSONSchemaValidator([]byte(`{"$schema":"https://json-schema.org/draft/2020-12/schema","title":"m","properties":{"a":{"description":"x"}},"additionalProperties":false}`)); err != nil {
+		t.Fatalf("annotated schema: %v", err)
+	}
+}
+
+func TestCreateLicense_RequiresSubscriptionID(t *testing.T) {
+	validate, err := JSONSchemaValidator([]byte(testMetadataSchema))
+	if err != nil {
+		t.Fatalf("JSONSchemaValidator: %v", err)
+	}
+	c := New("acct", "token", WithBaseURL("http://127.0.0.1:0"), WithMetadataValidator(validate))
+	_, err = c.CreateLicense(context.Background(), "policy", LicenseMetadata{CustomerEmail: "a@b.io"})
+	if err == nil || !strings.Contains(err.Error(), "metadata.subscriptionId: is required") {
+		t.Fatalf("expected missing subscriptionId error, got %v", err)
+	}
+}
@@ -0,0 +1,129 @@
+package keygen
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ErrCertificatePin is returned (wrapped) when the API host presents a
+// certificate chain matching none of the keys set with WithPinnedKeys.
+var ErrCertificatePin = errors.New("keygen: API certificate does not match any pinned key")
+
+// WithProxy routes API calls through the HTTP(S) proxy at proxyURL, e.g.
+// "http://proxy.corp:3128" (credentials may be given in the userinfo). By
+// default the HTTPS_PROXY/NO_PROXY environment variables are honoured.
+func WithProxy(proxyURL string) Option {
+	return func(c *Client) { c.proxyURL = proxyURL }
+}
+
+// WithTLSConfig replaces the TLS configuration of the transport, e.g. to
+// trust a corporate root CA or present a client certificate. cfg is cloned;
+// later changes to it have no effect.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(c *Client) { c.tlsConfig = cfg }
+}
+
+// WithPinnedKeys pins the API host (api.keygen.sh, or the WithBaseURL host)
+// to certificates whose chain contains one of the given public keys. Each
+// pin is the base64 SHA-256 of a certificate's SubjectPublicKeyInfo, as
+// printed by
+//
+//	openssl x509 -pubkey -noout | openssl pkey -pubin -outform der | openssl dgst -sha256 -binary | base64
+//
+// Pin a backup key too, or a certificate rotation will lock devices out.
+// Other hosts (e.g. artifact storage) are not affected.
+func WithPinnedKeys(pins ...string) Option {
+	return func(c *Client) { c.pins = append(c.pins, pins...) }
+}
+
+// configureTransport applies WithProxy, WithTLSConfig and WithPinnedKeys to
+// a private copy of the transport.
+func (c *Client) configureTransport() error {
+	if c.proxyURL == "" && c.tlsConfig == nil && len(c.pins) == 0 {
+		return nil
+	}
+	rt := c.http.Transport
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	if _, ok := rt.(interface{ browserManagedTLS() }); ok {
+		return fmt.Errorf("keygen: proxy and TLS settings are managed by the browser")
+	}
+	t, ok := rt.(*http.Transport)
+	if !ok {
+		return fmt.Errorf("keygen: cannot configure proxy or TLS on transport %T", rt)
+	}
+	t = t.Clone()
+
+	if c.proxyURL != "" {
+		u, err := url.Parse(c.proxyURL)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("keygen: invalid proxy URL %q", c.proxyURL)
+		}
+		t.Proxy = http.ProxyURL(u)
+	}
+	if c.tlsConfig != nil {
+		t.TLSClientConfig = c.tlsConfig.Clone()
+	}
+	if len(c.pins) > 0 {
+		verify, err := c.pinVerifier()
+		if err != nil {
+			return err
+		}
+		if t.TLSClientConfig == nil {
+			t.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+		next := t.TLSClientConfig.VerifyConnection
+		t.TLSClientConfig.VerifyConnection = func(cs tls.ConnectionState) error {
+			if err := verify(cs); err != nil {
+				return err
+			}
+			if next != nil {
+				return next(cs)
+			}
+			return nil
+		}
+	}
+
+	h := *c.http
+	h.Transport = t
+	c.http = &h
+	return nil
+}
+
+// pinVerifier checks connections to the API host against the pinned keys.
+// A connection belongs to the API host when its leaf certificate is valid
+// for that host name, which also covers connections through a proxy.
+func (c *Client) pinVerifier() (func(tls.ConnectionState) error, error) {
+	u, err := url.Parse(c.baseURL)
+	if err != nil || u.Hostname() == "" {
+		return nil, fmt.Errorf("keygen: cannot pin keys for base URL %q", c.baseURL)
+	}
+	host := u.Hostname()
+
+	pins := make(map[[sha256.Size]byte]bool, len(c.pins))
+	for _, p := range c.pins {
+		b, err := base64.StdEncoding.DecodeString(p)
+		if err != nil || len(b) != sha256.Size {
+			return nil, fmt.Errorf("keygen: malformed key pin %q", p)
+		}
+		pins[[sha256.Size]byte(b)] = true
+	}
+
+	return func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 || cs.PeerCertificates[0].VerifyHostname(host) != nil {
+			return nil // not the API host
+		}
+		for _, cert := range cs.PeerCertificates {
+			if pins[sha256.Sum256(cert.RawSubjectPublicKeyInfo)] {
+				return nil
+			}
+		}
+		return fmt.Errorf("%w (%s)", ErrCertificatePin, host)
+	}, nil
+}
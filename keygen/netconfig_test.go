@@ -0,0 +1,67 @@
+package keygen
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWithProxy(t *testing.T) {
+	var target string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		target = r.URL.String() // absolute-form request URI
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer proxy.Close()
+
+	c := New("acct", "token", WithBaseURL("http://keygen.invalid/v1"), WithProxy(proxy.URL))
+	if err := c.DeleteLicense(context.Background(), "lic"); err != nil {
+		t.Fatalf("DeleteLicense error: %v", err)
+	}
+	if target != "http://keygen.invalid/v1/accounts/acct/licenses/lic" {
+		t.Fatalf("proxy saw %q", target)
+	}
+
+	c = New("acct", "token", WithProxy("not a url"))
+	if err := c.DeleteLicense(context.Background(), "lic"); err == nil {
+		t.Fatalf("expected an error for a malformed proxy URL")
+	}
+}
+
+func TestWithPinnedKeys(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(srv.Certificate())
+	cfg := &tls.Config{RootCAs: roots}
+	sum := sha256.Sum256(srv.Certificate().RawSubjectPublicKeyInfo)
+	good := base64.StdEncoding.EncodeToString(sum[:])
+	bad := base64.StdEncoding.EncodeToString(make([]byte, sha256.Size))
+
+	c := New("acct", "token", WithBaseURL(srv.URL), WithTLSConfig(cfg), WithPinnedKeys(bad, good))
+	if err := c.DeleteLicense(context.Background(), "lic"); err != nil {
+		t.Fatalf("pinned key: unexpected error %v", err)
+	}
+	if cfg.VerifyConnection != nil {
+		t.Fatalf("caller's tls.Config was mutated")
+	}
+
+	c = New("acct", "token", WithBaseURL(srv.URL), WithTLSConfig(cfg), WithPinnedKeys(bad))
+	if err := c.DeleteLicense(context.Background(), "lic"); !errors.Is(err, ErrCertificatePin) {
+		t.Fatalf("wrong pin: expected ErrCertificatePin, got %v", err)
+	}
+
+	c = New("acct", "token", WithPinnedKeys("short"))
+	if err := c.DeleteLicense(context.Background(), "lic"); err == nil {
+		t.Fatalf("expected an error for a malformed pin")
+	}
+}
@@ -0,0 +1,135 @@
+package keygen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// listResponse is the JSON:API envelope shared by list endpoints.
+type listResponse[R any] struct {
+	Data  []R `json:"data"`
+	Links struct {
+		Next *string `json:"next"`
+	} `json:"links"`
+}
+
+// listAll fetches every page of the list endpoint at path (relative to the
+// account) and converts each resource with conv. q may be nil. Like the
+// other list calls, an interrupted pagination returns the items fetched so
+// far with a *PartialResultError.
+func listAll[R, T any](ctx context.Context, c *Client, path string, q url.Values, conv func(R) T) ([]T, error) {
+	var out []T
+	err := forEachPage(ctx, c, path, q, func(page []R) error {
+		for _, r := range page {
+			out = append(out, conv(r))
+		}
+		return nil
+	})
+	var partial *PartialResultError
+	if err != nil && !errors.As(err, &partial) {
+		return nil, err
+	}
+	return out, err
+}
+
+// forEachPage fetches the list endpoint at path one page at a time and hands
+// each page to fn before requesting the next, so only one page is held in
+// memory. An error from fn stops the iteration and is returned as is; a
+// context cancelled after the first page yields a *PartialResultError.
+func forEachPage[R any](ctx context.Context, c *Client, path string, q url.Values, fn func([]R) error) error {
+	if q == nil {
+		q = url.Values{}
+	}
+	page := 1
+
+	for {
+		q.Set("page[number]", strconv.Itoa(page))
+		q.Set("page[size]", "100")
+		full := fmt.Sprintf("/accounts/%s%s?%s", c.accountID, path, q.Encode())
+
+		var resp listResponse[R]
+		if err := c.do(ctx, http.MethodGet, full, nil, &resp); err != nil {
+			if page > 1 && ctx.Err() != nil {
+				return &PartialResultError{Pages: page - 1, Err: err}
+			}
+			return err
+		}
+		if err := fn(resp.Data); err != nil {
+			return err
+		}
+		if resp.Links.Next == nil {
+			return nil
+		}
+		page++
+	}
+}
+
+// searchRequest is the body of POST /search.
+type searchRequest struct {
+	Meta struct {
+		Type  string            `json:"type"`
+		Query map[string]string `json:"query"`
+	} `json:"meta"`
+}
+
+// searchAll runs a Keygen search over resources of type typ, where string
+// attributes in query match partially, and follows pagination like listAll.
+func searchAll[R, T any](ctx context.Context, c *Client, typ string, query map[string]string, conv func(R) T) ([]T, error) {
+	var req searchRequest
+	req.Meta.Type = typ
+	req.Meta.Query = query
+
+	var out []T
+	for page := 1; ; page++ {
+		q := url.Values{}
+		q.Set("page[number]", strconv.Itoa(page))
+		q.Set("page[size]", "100")
+		full := fmt.Sprintf("/accounts/%s/search?%s", c.accountID, q.Encode())
+
+		var resp listResponse[R]
+		if err := c.do(ctx, http.MethodPost, full, req, &resp); err != nil {
+			if page > 1 && ctx.Err() != nil {
+				return out, &PartialResultError{Pages: page - 1, Err: err}
+			}
+			return nil, err
+		}
+		for _, r := range resp.Data {
+			out = append(out, conv(r))
+		}
+		if resp.Links.Next == nil {
+			return out, nil
+		}
+	}
+}
+
+// countResponse reads the pagination meta of a list endpoint.
+type countResponse struct {
+	Meta struct {
+		Pages int `json:"pages"`
+		Count int `json:"count"`
+	} `json:"meta"`
+}
+
+// count returns the total number of resources at path matching q by
+// requesting a single one-item page and reading the pagination meta.
+func (c *Client) count(ctx context.Context, path string, q url.Values) (int, error) {
+	if q == nil {
+		q = url.Values{}
+	}
+	q.Set("page[number]", "1")
+	q.Set("page[size]", "1")
+	full := fmt.Sprintf("/accounts/%s%s?%s", c.accountID, path, q.Encode())
+
+	var resp countResponse
+	if err := c.do(ctx, http.MethodGet, full, nil, &resp); err != nil {
+		return 0, err
+	}
+	if resp.Meta.Count > 0 {
+		return resp.Meta.Count, nil
+	}
+	return resp.Meta.Pages, nil // one item per page
+}
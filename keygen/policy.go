@@ -0,0 +1,123 @@
+package keygen
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// CreatePolicy creates a policy under p.ProductID, e.g. when billing adds a
+// new product tier. Zero-valued fields of p are left to Keygen's defaults.
+func (c *Client) CreatePolicy(ctx context.Context, p Policy) (Policy, error) {
+	var req policyRequest
+	req.Data.Type = "policies"
+	req.Data.Attributes = policyAttributes{
+		Name:                          &p.Name,
+		Scheme:                        p.Scheme,
+		MaxMachines:                   p.MaxMachines,
+		MaxUses:                       p.MaxUses,
+		MaxCores:                      p.MaxCores,
+		MaxProcesses:                  p.MaxProcesses,
+		Floating:                      trueOrNil(p.Floating),
+		Strict:                        trueOrNil(p.Strict),
+		Protected:                     trueOrNil(p.Protected),
+		RequireHeartbeat:              trueOrNil(p.RequireHeartbeat),
+		HeartbeatCullStrategy:         p.HeartbeatCullStrategy,
+		HeartbeatResurrectionStrategy: p.HeartbeatResurrectionStrategy,
+		ExpirationStrategy:            p.ExpirationStrategy,
+		AuthenticationStrategy:        p.AuthenticationStrategy,
+	}
+	a := &req.Data.Attributes
+	if p.Duration > 0 {
+		d := nullableInt(p.Duration / time.Second)
+		a.Duration = &d
+	}
+	if p.HeartbeatDuration > 0 {
+		d := int(p.HeartbeatDuration / time.Second)
+		a.HeartbeatDuration = &d
+	}
+	if p.Metadata != nil {
+		a.Metadata = &p.Metadata
+	}
+	req.Data.Relationships = &policyRelationships{
+		Product: licenseRelationship{Data: relationshipData{Type: "products", ID: p.ProductID}},
+	}
+
+	var resp policyResponse
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("/accounts/%s/policies", c.accountID), req, &resp); err != nil {
+		return Policy{}, err
+	}
+	return resp.Data.toPolicy(), nil
+}
+
+// trueOrNil returns a pointer to true, or nil so false is left to Keygen's
+// default.
+func trueOrNil(b bool) *bool {
+	if !b {
+		return nil
+	}
+	return &b
+}
+
+// GetPolicy returns a policy by ID.
+func (c *Client) GetPolicy(ctx context.Context, policyID string) (Policy, error) {
+	path := fmt.Sprintf("/accounts/%s/policies/%s", c.accountID, policyID)
+
+	var resp policyResponse
+	if err := c.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return Policy{}, err
+	}
+	return resp.Data.toPolicy(), nil
+}
+
+// UpdatePolicy PATCHes the attributes set in u and returns the updated
+// policy. Changes apply to the policy's existing licenses too.
+func (c *Client) UpdatePolicy(ctx context.Context, policyID string, u PolicyUpdate) (Policy, error) {
+	var req policyRequest
+	req.Data.Type = "policies"
+	req.Data.Attributes = policyAttributes{
+		Name:             u.Name,
+		Floating:         u.Floating,
+		Strict:           u.Strict,
+		Protected:        u.Protected,
+		MaxMachines:      u.MaxMachines,
+		MaxUses:          u.MaxUses,
+		MaxCores:         u.MaxCores,
+		MaxProcesses:     u.MaxProcesses,
+		RequireHeartbeat: u.RequireHeartbeat,
+	}
+	if u.Duration != nil {
+		d := nullableInt(*u.Duration / time.Second)
+		req.Data.Attributes.Duration = &d
+	}
+	if u.HeartbeatDuration != nil {
+		d := int(*u.HeartbeatDuration / time.Second)
+		req.Data.Attributes.HeartbeatDuration = &d
+	}
+	if u.Metadata != nil {
+		req.Data.Attributes.Metadata = &u.Metadata
+	}
+	path := fmt.Sprintf("/accounts/%s/policies/%s", c.accountID, policyID)
+
+	var resp policyResponse
+	if err := c.do(ctx, http.MethodPatch, path, req, &resp); err != nil {
+		return Policy{}, err
+	}
+	return resp.Data.toPolicy(), nil
+}
+
+// DeletePolicy deletes a policy. Keygen also deletes its licenses.
+func (c *Client) DeletePolicy(ctx context.Context, policyID string) error {
+	path := fmt.Sprintf("/accounts/%s/policies/%s", c.accountID, policyID)
+	return c.do(ctx, http.MethodDelete, path, nil, nil)
+}
+
+// ListPolicies lists the account's policies, restricted to productID when
+// it is not empty.
+func (c *Client) ListPolicies(ctx context.Context, productID string) ([]Policy, error) {
+	q := url.Values{}
+	setIfNotEmpty(q, "product", productID)
+	return listAll(ctx, c, "/policies", q, policyResource.toPolicy)
+}
@@ -0,0 +1,60 @@
+package keygen
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestPolicies(t *testing.T) {
+	const resource = `{"id":"pol-1","attributes":{"name":"Pro","duration":2592000,"floating":true,"maxMachines":3},
+		"relationships":{"product":{"data":{"type":"products","id":"prod-1"}}}}`
+	api := newFakeAPI(t, map[string]http.HandlerFunc{
+		"POST /policies":        jsonData(resource),
+		"GET /policies/pol-1":   jsonData(resource),
+		"PATCH /policies/pol-1": jsonData(resource),
+		"GET /policies": func(w http.ResponseWriter, r *http.Request) {
+			if got := r.URL.Query().Get("product"); got != "prod-1" {
+				t.Errorf("product filter = %q", got)
+			}
+			jsonList(resource)(w, r)
+		},
+		"DELETE /policies/pol-1": noContent,
+	})
+
+	ctx := context.Background()
+	c := api.client()
+	maxMachines := 3
+	p, err := c.CreatePolicy(ctx, Policy{Name: "Pro", ProductID: "prod-1", Duration: 30 * 24 * time.Hour, Floating: true, MaxMachines: &maxMachines})
+	if err != nil {
+		t.Fatalf("CreatePolicy: %v", err)
+	}
+	if p.ID != "pol-1" || p.ProductID != "prod-1" || p.Duration != 30*24*time.Hour || !p.Floating {
+		t.Fatalf("CreatePolicy = %+v", p)
+	}
+	if got := api.attributes("POST /policies"); got["duration"] != float64(2592000) || got["floating"] != true || got["strict"] != nil {
+		t.Fatalf("create attributes = %v", got)
+	}
+
+	if p, err := c.GetPolicy(ctx, "pol-1"); err != nil || p.Name != "Pro" || p.ProductID != "prod-1" {
+		t.Fatalf("GetPolicy = %+v, %v", p, err)
+	}
+
+	never := time.Duration(0)
+	if _, err := c.UpdatePolicy(ctx, "pol-1", PolicyUpdate{Duration: &never}); err != nil {
+		t.Fatalf("UpdatePolicy: %v", err)
+	}
+	got := api.attributes("PATCH /policies/pol-1")
+	if d, ok := got["duration"]; !ok || d != nil || len(got) != 1 {
+		t.Fatalf("update attributes = %v, want only duration: null", got)
+	}
+
+	list, err := c.ListPolicies(ctx, "prod-1")
+	if err != nil || len(list) != 1 || list[0].MaxMachines == nil || *list[0].MaxMachines != 3 {
+		t.Fatalf("ListPolicies = %+v, %v", list, err)
+	}
+	if err := c.DeletePolicy(ctx, "pol-1"); err != nil {
+		t.Fatalf("DeletePolicy: %v", err)
+	}
+}
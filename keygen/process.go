@@ -0,0 +1,58 @@
+package keygen
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// SpawnProcess registers a running process (pid is any caller-chosen
+// identifier) on machineID. Keygen rejects it once the policy's
+// maxProcesses is reached.
+func (c *Client) SpawnProcess(ctx context.Context, machineID, pid string, meta map[string]any) (Process, error) {
+	if err := c.checkDeviceScope(ctx, "SpawnProcess"); err != nil {
+		return Process{}, err
+	}
+	var req processCreateRequest
+	req.Data.Type = "processes"
+	req.Data.Attributes.PID = pid
+	req.Data.Attributes.Metadata = meta
+	req.Data.Relationships.Machine.Data = relationshipData{Type: "machines", ID: machineID}
+
+	var resp processResponse
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("/accounts/%s/processes", c.accountID), req, &resp); err != nil {
+		return Process{}, err
+	}
+	return resp.Data.toProcess(), nil
+}
+
+// PingProcess sends a heartbeat for a process so it is not culled as dead.
+func (c *Client) PingProcess(ctx context.Context, processID string) (Process, error) {
+	if err := c.checkDeviceScope(ctx, "PingProcess"); err != nil {
+		return Process{}, err
+	}
+	path := fmt.Sprintf("/accounts/%s/processes/%s/actions/ping", c.accountID, processID)
+
+	var resp processResponse
+	if err := c.do(ctx, http.MethodPost, path, nil, &resp); err != nil {
+		return Process{}, err
+	}
+	return resp.Data.toProcess(), nil
+}
+
+// KillProcess deletes a process, freeing its slot on the machine.
+func (c *Client) KillProcess(ctx context.Context, processID string) error {
+	if err := c.checkDeviceScope(ctx, "KillProcess"); err != nil {
+		return err
+	}
+	path := fmt.Sprintf("/accounts/%s/processes/%s", c.accountID, processID)
+	return c.do(ctx, http.MethodDelete, path, nil, nil)
+}
+
+// ListProcesses lists the processes running on machineID.
+func (c *Client) ListProcesses(ctx context.Context, machineID string) ([]Process, error) {
+	q := url.Values{}
+	q.Set("machine", machineID)
+	return listAll(ctx, c, "/processes", q, processResource.toProcess)
+}
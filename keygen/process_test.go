@@ -0,0 +1,73 @@
+package keygen
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestProcesses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "POST /accounts/acct/processes":
+			var req processCreateRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Errorf("decode: %v", err)
+			}
+			if req.Data.Attributes.PID != "1234" || req.Data.Relationships.Machine.Data.ID != "m1" {
+				t.Errorf("unexpected spawn body: %+v", req)
+			}
+			w.Write([]byte(`{"data":{"id":"p1","attributes":{"pid":"1234","status":"ALIVE"},
+				"relationships":{"machine":{"data":{"type":"machines","id":"m1"}}}}}`))
+		case "GET /accounts/acct/processes":
+			if got := r.URL.Query().Get("machine"); got != "m1" {
+				t.Errorf("machine filter = %q", got)
+			}
+			w.Write([]byte(`{"data":[{"id":"p1","attributes":{"pid":"1234","status":"ALIVE"}}],"links":{}}`))
+		case "DELETE /accounts/acct/processes/p1":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	c := New("acct", "token", WithBaseURL(srv.URL))
+	p, err := c.SpawnProcess(ctx, "m1", "1234", nil)
+	if err != nil {
+		t.Fatalf("SpawnProcess: %v", err)
+	}
+	if p.ID != "p1" || p.MachineID != "m1" || p.Status != "ALIVE" {
+		t.Fatalf("SpawnProcess = %+v", p)
+	}
+	list, err := c.ListProcesses(ctx, "m1")
+	if err != nil || len(list) != 1 || list[0].PID != "1234" {
+		t.Fatalf("ListProcesses = %+v, %v", list, err)
+	}
+	if err := c.KillProcess(ctx, "p1"); err != nil {
+		t.Fatalf("KillProcess: %v", err)
+	}
+}
+
+func TestKillProcess_ChecksDeviceScope(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /accounts/acct/me":
+			w.Write([]byte(`{"data":{"id":"u1","type":"users","attributes":{"role":"admin"}}}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	c := New("acct", "token", WithBaseURL(srv.URL), WithLeastPrivilegeEnforced())
+	if err := c.KillProcess(context.Background(), "p1"); !errors.Is(err, ErrOverprivilegedToken) {
+		t.Fatalf("KillProcess with an admin token: got %v, want ErrOverprivilegedToken", err)
+	}
+}
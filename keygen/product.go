@@ -0,0 +1,85 @@
+package keygen
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// CreateProduct creates a product. Empty fields of p are left to Keygen's
+// defaults (e.g. a LICENSED distribution strategy).
+func (c *Client) CreateProduct(ctx context.Context, p Product) (Product, error) {
+	var req productRequest
+	req.Data.Type = "products"
+	req.Data.Attributes = productAttributes{
+		Name:        &p.Name,
+		Platforms:   nonEmptyList(p.Platforms),
+		Permissions: nonEmptyList(p.Permissions),
+	}
+	a := &req.Data.Attributes
+	if p.Code != "" {
+		a.Code = &p.Code
+	}
+	if p.URL != "" {
+		a.URL = &p.URL
+	}
+	if p.DistributionStrategy != "" {
+		a.DistributionStrategy = &p.DistributionStrategy
+	}
+	if p.Metadata != nil {
+		a.Metadata = &p.Metadata
+	}
+
+	var resp productResponse
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("/accounts/%s/products", c.accountID), req, &resp); err != nil {
+		return Product{}, err
+	}
+	return resp.Data.toProduct(), nil
+}
+
+// GetProduct returns a product by ID.
+func (c *Client) GetProduct(ctx context.Context, productID string) (Product, error) {
+	path := fmt.Sprintf("/accounts/%s/products/%s", c.accountID, productID)
+
+	var resp productResponse
+	if err := c.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return Product{}, err
+	}
+	return resp.Data.toProduct(), nil
+}
+
+// UpdateProduct PATCHes the attributes set in u and returns the updated
+// product.
+func (c *Client) UpdateProduct(ctx context.Context, productID string, u ProductUpdate) (Product, error) {
+	var req productRequest
+	req.Data.Type = "products"
+	req.Data.Attributes = productAttributes{
+		Name:                 u.Name,
+		Code:                 u.Code,
+		URL:                  u.URL,
+		DistributionStrategy: u.DistributionStrategy,
+		Platforms:            listOrNil(u.Platforms),
+		Permissions:          listOrNil(u.Permissions),
+	}
+	if u.Metadata != nil {
+		req.Data.Attributes.Metadata = &u.Metadata
+	}
+	path := fmt.Sprintf("/accounts/%s/products/%s", c.accountID, productID)
+
+	var resp productResponse
+	if err := c.do(ctx, http.MethodPatch, path, req, &resp); err != nil {
+		return Product{}, err
+	}
+	return resp.Data.toProduct(), nil
+}
+
+// DeleteProduct deletes a product together with its policies and licenses.
+func (c *Client) DeleteProduct(ctx context.Context, productID string) error {
+	path := fmt.Sprintf("/accounts/%s/products/%s", c.accountID, productID)
+	return c.do(ctx, http.MethodDelete, path, nil, nil)
+}
+
+// ListProducts lists the account's products.
+func (c *Client) ListProducts(ctx context.Context) ([]Product, error) {
+	return listAll(ctx, c, "/products", nil, productResource.toProduct)
+}
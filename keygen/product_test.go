@@ -0,0 +1,76 @@
+package keygen
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestProducts(t *testing.T) {
+	const resource = `{"id":"prod-1","attributes":{"name":"Dappnode Pro","distributionStrategy":"LICENSED"}}`
+	api := newFakeAPI(t, map[string]http.HandlerFunc{
+		"POST /products":          jsonData(resource),
+		"GET /products/prod-1":    jsonData(resource),
+		"GET /products":           jsonList(resource),
+		"DELETE /products/prod-1": noContent,
+	})
+
+	ctx := context.Background()
+	c := api.client()
+	p, err := c.CreateProduct(ctx, Product{Name: "Dappnode Pro", DistributionStrategy: "LICENSED"})
+	if err != nil || p.ID != "prod-1" || p.DistributionStrategy != "LICENSED" {
+		t.Fatalf("CreateProduct = %+v, %v", p, err)
+	}
+	var req productRequest
+	api.decodeBody("POST /products", &req)
+	if a := req.Data.Attributes; a.Name == nil || *a.Name != "Dappnode Pro" || a.DistributionStrategy == nil || *a.DistributionStrategy != "LICENSED" || a.Code != nil {
+		t.Fatalf("create attributes = %+v", a)
+	}
+
+	if p, err := c.GetProduct(ctx, "prod-1"); err != nil || p.Name != "Dappnode Pro" {
+		t.Fatalf("GetProduct = %+v, %v", p, err)
+	}
+	list, err := c.ListProducts(ctx)
+	if err != nil || len(list) != 1 || list[0].Name != "Dappnode Pro" {
+		t.Fatalf("ListProducts = %+v, %v", list, err)
+	}
+	if err := c.DeleteProduct(ctx, "prod-1"); err != nil {
+		t.Fatalf("DeleteProduct: %v", err)
+	}
+}
+
+func TestUpdateClearsLists(t *testing.T) {
+	const resource = `{"id":"x","attributes":{}}`
+	api := newFakeAPI(t, map[string]http.HandlerFunc{
+		"PATCH /products/prod-1": jsonData(resource),
+		"PATCH /licenses/lic-1":  jsonData(resource),
+		"PATCH /licenses/lic-2":  jsonData(resource),
+	})
+
+	ctx := context.Background()
+	c := api.client()
+	if _, err := c.UpdateProduct(ctx, "prod-1", ProductUpdate{Platforms: []string{}}); err != nil {
+		t.Fatalf("UpdateProduct: %v", err)
+	}
+	if _, err := c.UpdateLicense(ctx, "lic-1", LicenseUpdate{Permissions: []string{}}); err != nil {
+		t.Fatalf("UpdateLicense: %v", err)
+	}
+	if _, err := c.UpdateLicense(ctx, "lic-2", LicenseUpdate{}); err != nil {
+		t.Fatalf("UpdateLicense: %v", err)
+	}
+
+	if a := api.attributes("PATCH /products/prod-1"); !isEmptyList(a["platforms"]) || a["permissions"] != nil {
+		t.Errorf("product update attributes = %v", a)
+	}
+	if a := api.attributes("PATCH /licenses/lic-1"); !isEmptyList(a["permissions"]) {
+		t.Errorf("license update attributes = %v", a)
+	}
+	if a := api.attributes("PATCH /licenses/lic-2"); a["permissions"] != nil {
+		t.Errorf("nil permissions sent: %v", a)
+	}
+}
+
+func isEmptyList(v any) bool {
+	l, ok := v.([]any)
+	return ok && len(l) == 0
+}
@@ -0,0 +1,79 @@
+package keygen
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidProof is returned by VerifyMachineProof when a proof is
+// malformed or its signature does not match the account's public key.
+var ErrInvalidProof = errors.New("keygen: invalid machine proof")
+
+// GenerateMachineProof asks Keygen to sign an offline proof that machineID is
+// activated. A non-empty nonce is embedded in the signed dataset so a
+// verifier can reject replayed proofs.
+func (c *Client) GenerateMachineProof(ctx context.Context, machineID, nonce string) (string, error) {
+	var req machineProofRequest
+	if nonce != "" {
+		req.Meta.Dataset = map[string]any{"nonce": nonce}
+	}
+
+	var resp machineProofResponse
+	if err := c.machineAction(ctx, machineID, "generate-offline-proof", req, &resp); err != nil {
+		return "", err
+	}
+	if resp.Meta.Proof == "" {
+		return "", fmt.Errorf("keygen: machine %s: empty proof", machineID)
+	}
+	return resp.Meta.Proof, nil
+}
+
+// VerifyMachineProof checks an Ed25519-signed proof ("proof/<payload>.<sig>")
+// against the account's hex-encoded public key and returns the decoded JSON
+// payload. It needs no network access.
+func VerifyMachineProof(proof, publicKeyHex string) ([]byte, error) {
+	key, err := hex.DecodeString(publicKeyHex)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("keygen: malformed Ed25519 public key")
+	}
+	// Only machine proofs are accepted: a signed license key ("key/...") is
+	// signed by the same account key but proves nothing about a machine.
+	signed, sig, ok := cutLast(proof, ".")
+	if !ok || !strings.HasPrefix(signed, "proof/") {
+		return nil, fmt.Errorf("%w: expected proof/<payload>.<signature>", ErrInvalidProof)
+	}
+	sigBytes, err := decodeBase64(sig)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature", ErrInvalidProof)
+	}
+	if !ed25519.Verify(key, []byte(signed), sigBytes) {
+		return nil, fmt.Errorf("%w: signature mismatch", ErrInvalidProof)
+	}
+	payload, err := decodeBase64(strings.TrimPrefix(signed, "proof/"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed payload", ErrInvalidProof)
+	}
+	return payload, nil
+}
+
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
+
+// decodeBase64 accepts both the standard and URL-safe alphabets, padded or
+// not, since Keygen's signing schemes differ.
+func decodeBase64(s string) ([]byte, error) {
+	s = strings.TrimRight(s, "=")
+	if strings.ContainsAny(s, "-_") {
+		return base64.RawURLEncoding.DecodeString(s)
+	}
+	return base64.RawStdEncoding.DecodeString(s)
+}
@@ -0,0 +1,43 @@
+package keygen
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestVerifyMachineProof(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := `{"machine":{"id":"m1"},"dataset":{"nonce":"n1"}}`
+	signed := "proof/" + base64.URLEncoding.EncodeToString([]byte(payload))
+	proof := signed + "." + base64.URLEncoding.EncodeToString(ed25519.Sign(priv, []byte(signed)))
+
+	got, err := VerifyMachineProof(proof, hex.EncodeToString(pub))
+	if err != nil {
+		t.Fatalf("valid proof: %v", err)
+	}
+	if string(got) != payload {
+		t.Fatalf("payload = %s, want %s", got, payload)
+	}
+
+	otherPub, _, _ := ed25519.GenerateKey(rand.Reader)
+	if _, err := VerifyMachineProof(proof, hex.EncodeToString(otherPub)); !errors.Is(err, ErrInvalidProof) {
+		t.Fatalf("wrong key: expected ErrInvalidProof, got %v", err)
+	}
+	if _, err := VerifyMachineProof("proof/abc", hex.EncodeToString(pub)); !errors.Is(err, ErrInvalidProof) {
+		t.Fatalf("unsigned proof: expected ErrInvalidProof, got %v", err)
+	}
+
+	// A signed license key verifies under the same account key but is not a proof.
+	signedKey := "key/" + base64.URLEncoding.EncodeToString([]byte(payload))
+	signedKey += "." + base64.URLEncoding.EncodeToString(ed25519.Sign(priv, []byte(signedKey)))
+	if _, err := VerifyMachineProof(signedKey, hex.EncodeToString(pub)); !errors.Is(err, ErrInvalidProof) {
+		t.Fatalf("signed key: expected ErrInvalidProof, got %v", err)
+	}
+}
@@ -0,0 +1,251 @@
+//go:build !keygen_minimal
+
+package keygen
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ProxyServer exposes /validate (and, with WithProxyActivationSecret,
+// /activate) on the local network so that
+// auxiliary containers can check licensing without holding Keygen credentials
+// or internet access. Validations go through a ValidatorCache: while the API
+// is unreachable the last successful validation is served back within the
+// grace period, and definitive API answers (revoked, suspended or deleted
+// licenses) are passed through and clear the cached result.
+type ProxyServer struct {
+	client           *Client
+	gracePeriod      time.Duration
+	store            ValidationStore
+	maxBodySize      int64
+	origins          map[string]bool
+	activationSecret string
+
+	validator *ValidatorCache
+}
+
+// ProxyOption configures the ProxyServer.
+type ProxyOption func(*ProxyServer)
+
+// WithProxyGracePeriod sets how long a cached successful validation may be
+// served while the Keygen API is unreachable (default: 72h). Zero disables
+// the offline fallback.
+func WithProxyGracePeriod(d time.Duration) ProxyOption {
+	return func(p *ProxyServer) { p.gracePeriod = d }
+}
+
+// WithProxyValidationStore persists the offline cache in s (e.g. a
+// FileValidationStore) so it survives restarts. By default it is kept in
+// memory.
+func WithProxyValidationStore(s ValidationStore) ProxyOption {
+	return func(p *ProxyServer) { p.store = s }
+}
+
+// WithProxyActivationSecret enables /activate for callers sending
+// "Authorization: Bearer <secret>". Activations use the proxy's own
+// credentials and consume the license's machine slots, so without a secret
+// /activate is not served at all.
+func WithProxyActivationSecret(secret string) ProxyOption {
+	return func(p *ProxyServer) { p.activationSecret = secret }
+}
+
+// WithProxyAllowedOrigins enables CORS on /validate for the given browser
+// origins (e.g. http://my.dappnode), so web UIs can call the proxy directly.
+// "*" allows any origin. /activate is never exposed to browsers.
+func WithProxyAllowedOrigins(origins ...string) ProxyOption {
+	return func(p *ProxyServer) {
+		if p.origins == nil {
+			p.origins = make(map[string]bool, len(origins))
+		}
+		for _, o := range origins {
+			p.origins[o] = true
+		}
+	}
+}
+
+// NewProxyServer creates a ProxyServer backed by c.
+func NewProxyServer(c *Client, opts ...ProxyOption) *ProxyServer {
+	p := &ProxyServer{
+		client:      c,
+		gracePeriod: 72 * time.Hour,
+		maxBodySize: 64 << 10,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.store == nil {
+		p.store = newMemoryValidationStore()
+	}
+	p.validator = NewValidatorCache(c, p.store, p.gracePeriod)
+	return p
+}
+
+// Handler returns the HTTP handler serving /validate, and /activate when an
+// activation secret is set.
+func (p *ProxyServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/validate", p.handleValidate)
+	if p.activationSecret != "" {
+		mux.HandleFunc("/activate", p.handleActivate)
+	}
+	return mux
+}
+
+// ListenAndServe serves the proxy on addr until ctx is cancelled.
+func (p *ProxyServer) ListenAndServe(ctx context.Context, addr string) error {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           p.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      60 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- srv.ListenAndServe() }()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			return err
+		}
+		if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	}
+}
+
+func (p *ProxyServer) handleValidate(w http.ResponseWriter, r *http.Request) {
+	if p.cors(w, r) {
+		return
+	}
+	if r.Method != http.MethodPost {
+		writeProxyError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	var in proxyValidateRequest
+	if err := p.decode(w, r, &in); err != nil {
+		writeProxyError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if in.Key == "" || in.Fingerprint == "" {
+		writeProxyError(w, http.StatusBadRequest, "key and fingerprint are required")
+		return
+	}
+
+	res, err := p.validator.ValidateCached(r.Context(), in.Key, in.Fingerprint)
+	if err != nil {
+		writeProxyError(w, proxyErrorStatus(err), proxyErrorMessage(err))
+		return
+	}
+	writeProxyJSON(w, http.StatusOK, proxyValidateResponse{LicenseValidation: res.LicenseValidation, Cached: res.Offline})
+}
+
+func (p *ProxyServer) handleActivate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeProxyError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if !p.authorizedActivation(r) {
+		writeProxyError(w, http.StatusUnauthorized, "activation secret required")
+		return
+	}
+	var in proxyActivateRequest
+	if err := p.decode(w, r, &in); err != nil {
+		writeProxyError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if in.Key == "" || in.Fingerprint == "" {
+		writeProxyError(w, http.StatusBadRequest, "key and fingerprint are required")
+		return
+	}
+
+	if err := p.client.ActivateMachine(r.Context(), in.Key, in.Fingerprint, in.Name, in.Platform); err != nil {
+		writeProxyError(w, proxyErrorStatus(err), proxyErrorMessage(err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// cors sets CORS headers for allowed origins and reports whether the request
+// was a preflight that has been fully answered.
+func (p *ProxyServer) cors(w http.ResponseWriter, r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" || !(p.origins[origin] || p.origins["*"]) {
+		return false
+	}
+	w.Header().Set("Access-Control-Allow-Origin", origin)
+	w.Header().Add("Vary", "Origin")
+	if r.Method != http.MethodOptions {
+		return false
+	}
+	w.Header().Set("Access-Control-Allow-Methods", "POST")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+	w.Header().Set("Access-Control-Max-Age", "600")
+	w.WriteHeader(http.StatusNoContent)
+	return true
+}
+
+func (p *ProxyServer) decode(w http.ResponseWriter, r *http.Request, v any) error {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, p.maxBodySize))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
+func (p *ProxyServer) authorizedActivation(r *http.Request) bool {
+	if p.activationSecret == "" {
+		return false
+	}
+	got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(got), []byte(p.activationSecret)) == 1
+}
+
+// proxyErrorStatus passes definitive API answers (e.g. 403, 404, 422)
+// through and reports everything else as a gateway failure.
+func proxyErrorStatus(err error) int {
+	var herr *HTTPError
+	if errors.As(err, &herr) && !isTransient(err) {
+		return herr.StatusCode
+	}
+	return http.StatusBadGateway
+}
+
+// proxyErrorMessage describes err without the upstream response body.
+func proxyErrorMessage(err error) string {
+	var herr *HTTPError
+	switch {
+	case errors.As(err, &herr):
+		msg := fmt.Sprintf("keygen: HTTP %d", herr.StatusCode)
+		if codes := herr.Codes(); len(codes) > 0 {
+			msg += " " + strings.Join(codes, ", ")
+		}
+		return msg
+	case errors.Is(err, ErrClockTampered):
+		return ErrClockTampered.Error()
+	case errors.Is(err, ErrGraceExpired):
+		return "keygen: API unreachable and " + strings.TrimPrefix(ErrGraceExpired.Error(), "keygen: ")
+	}
+	return "keygen: API unreachable"
+}
+
+func writeProxyJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeProxyError(w http.ResponseWriter, status int, msg string) {
+	writeProxyJSON(w, status, proxyErrorResponse{Error: msg})
+}
@@ -0,0 +1,64 @@
+package keygen
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ProxyClient performs read-only validation checks against a ProxyServer.
+// It holds no Keygen credentials, which makes it suitable for browser (wasm)
+// builds and auxiliary containers.
+type ProxyClient struct {
+	baseURL string
+	http    *http.Client
+}
+
+// NewProxyClient creates a ProxyClient for the proxy at baseURL
+// (e.g. http://dappmanager.dappnode:8080). A nil h uses the default client.
+func NewProxyClient(baseURL string, h *http.Client) *ProxyClient {
+	if h == nil {
+		h = defaultHTTPClient()
+	}
+	return &ProxyClient{baseURL: baseURL, http: h}
+}
+
+// Validate checks a key within a fingerprint scope through the proxy.
+// cached reports whether the proxy answered from its offline cache.
+func (p *ProxyClient) Validate(ctx context.Context, licenseKey, fingerprint string) (val LicenseValidation, cached bool, err error) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(proxyValidateRequest{Key: licenseKey, Fingerprint: fingerprint}); err != nil {
+		return LicenseValidation{}, false, fmt.Errorf("keygen: encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/validate", &buf)
+	if err != nil {
+		return LicenseValidation{}, false, fmt.Errorf("keygen: new request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.http.Do(req)
+	if err != nil {
+		return LicenseValidation{}, false, fmt.Errorf("keygen: do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var e proxyErrorResponse
+		b, _ := io.ReadAll(resp.Body)
+		if json.Unmarshal(b, &e) == nil && e.Error != "" {
+			return LicenseValidation{}, false, fmt.Errorf("keygen: proxy /validate -> HTTP %d: %s", resp.StatusCode, e.Error)
+		}
+		return LicenseValidation{}, false, fmt.Errorf("keygen: proxy /validate -> HTTP %d", resp.StatusCode)
+	}
+
+	var out proxyValidateResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return LicenseValidation{}, false, fmt.Errorf("keygen: decode response: %w", err)
+	}
+	return out.LicenseValidation, out.Cached, nil
+}
@@ -0,0 +1,127 @@
+//go:build !keygen_minimal
+
+package keygen
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestProxyServer_ValidateFallsBackToCache(t *testing.T) {
+	var down, deleted atomic.Bool
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if deleted.Load() {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":[{"title":"Not found","code":"NOT_FOUND","detail":"secret upstream detail"}]}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		_, _ = w.Write([]byte(`{"meta":{"valid":true,"code":"VALID"},"data":{"id":"lic-1","attributes":{"key":"KEY","status":"ACTIVE"}}}`))
+	}))
+	defer upstream.Close()
+
+	p := NewProxyServer(New("acct", "token", WithBaseURL(upstream.URL)))
+	srv := httptest.NewServer(p.Handler())
+	defer srv.Close()
+
+	validate := func(wantStatus int) proxyValidateResponse {
+		t.Helper()
+		body, _ := json.Marshal(proxyValidateRequest{Key: "KEY", Fingerprint: "fp"})
+		resp, err := http.Post(srv.URL+"/validate", "application/json", bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("POST /validate: %v", err)
+		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode != wantStatus {
+			t.Fatalf("POST /validate: HTTP %d, want %d", resp.StatusCode, wantStatus)
+		}
+		if strings.Contains(string(b), "secret upstream detail") {
+			t.Fatalf("upstream body leaked: %s", b)
+		}
+		var out proxyValidateResponse
+		if err := json.Unmarshal(b, &out); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return out
+	}
+
+	if got := validate(http.StatusOK); !got.Valid || got.Cached {
+		t.Fatalf("online validate: got %+v", got)
+	}
+	down.Store(true)
+	if got := validate(http.StatusOK); !got.Valid || !got.Cached {
+		t.Fatalf("offline validate: got %+v", got)
+	}
+
+	// A deleted license is reported as such and no longer served offline.
+	down.Store(false)
+	deleted.Store(true)
+	validate(http.StatusNotFound)
+	down.Store(true)
+	validate(http.StatusBadGateway)
+}
+
+func TestProxyServer_ActivationSecret(t *testing.T) {
+	var activations atomic.Int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "POST /accounts/acct/licenses/actions/validate-key":
+			_, _ = w.Write([]byte(`{"data":{"id":"lic-1"},"meta":{"valid":true,"code":"VALID"}}`))
+		case "POST /accounts/acct/machines":
+			activations.Add(1)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"data":{"id":"m1","attributes":{"fingerprint":"fp"}}}`))
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer upstream.Close()
+
+	// Without a secret /activate is not served.
+	noSecret := httptest.NewServer(NewProxyServer(New("acct", "token", WithBaseURL(upstream.URL))).Handler())
+	defer noSecret.Close()
+
+	p := NewProxyServer(New("acct", "token", WithBaseURL(upstream.URL)), WithProxyActivationSecret("s3cret"))
+	srv := httptest.NewServer(p.Handler())
+	defer srv.Close()
+
+	activateAt := func(base, secret string) int {
+		t.Helper()
+		body, _ := json.Marshal(proxyActivateRequest{Key: "KEY", Fingerprint: "fp"})
+		req, _ := http.NewRequest(http.MethodPost, base+"/activate", bytes.NewReader(body))
+		if secret != "" {
+			req.Header.Set("Authorization", "Bearer "+secret)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("POST /activate: %v", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	activate := func(secret string) int { return activateAt(srv.URL, secret) }
+
+	if got := activateAt(noSecret.URL, ""); got != http.StatusNotFound || activations.Load() != 0 {
+		t.Fatalf("no secret configured: HTTP %d, %d activations", got, activations.Load())
+	}
+	if got := activate(""); got != http.StatusUnauthorized {
+		t.Fatalf("no secret: HTTP %d", got)
+	}
+	if got := activate("wrong"); got != http.StatusUnauthorized {
+		t.Fatalf("wrong secret: HTTP %d", got)
+	}
+	if got := activate("s3cret"); got != http.StatusNoContent || activations.Load() != 1 {
+		t.Fatalf("right secret: HTTP %d, %d activations", got, activations.Load())
+	}
+}
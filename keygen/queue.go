@@ -0,0 +1,338 @@
+package keygen
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// OpKind identifies a mutation that can be queued while offline.
+type OpKind string
+
+const (
+	OpActivate       OpKind = "activate"        // ActivateMachine
+	OpDeactivate     OpKind = "deactivate"      // DeactivateMachine
+	OpHeartbeat      OpKind = "heartbeat"       // machine heartbeat ping
+	OpIncrementUsage OpKind = "increment-usage" // IncrementLicenseUsage
+)
+
+// QueuedOp is a mutation recorded for later replay.
+type QueuedOp struct {
+	// ID is the idempotency key: an operation whose ID is already pending is
+	// not queued twice. When empty, Enqueue derives one from Kind and its
+	// arguments (usage increments get a random ID, since each one counts).
+	ID   string `json:"id"`
+	Kind OpKind `json:"kind"`
+
+	LicenseKey  string `json:"licenseKey,omitempty"`  // activate, deactivate
+	LicenseID   string `json:"licenseId,omitempty"`   // increment-usage
+	MachineID   string `json:"machineId,omitempty"`   // heartbeat
+	Fingerprint string `json:"fingerprint,omitempty"` // activate, deactivate
+	Name        string `json:"name,omitempty"`        // activate
+	Platform    string `json:"platform,omitempty"`    // activate
+	Increment   int    `json:"increment,omitempty"`   // increment-usage
+
+	// IdempotencyKey is sent with every attempt of this operation, so a send
+	// that landed before a crash is not applied twice on replay. It is set by
+	// the queue: the caller's ID when given, otherwise a NewIdempotencyKey
+	// (a later activation of the same machine is a new operation).
+	IdempotencyKey string `json:"idempotencyKey,omitempty"`
+
+	EnqueuedAt time.Time `json:"enqueuedAt"`
+	Attempts   int       `json:"attempts"` // transient failures answered by the API
+}
+
+// QueueStore persists the pending operations of an OperationQueue.
+// Calls are serialized by the queue.
+type QueueStore interface {
+	Load() ([]QueuedOp, error)
+	Save(ops []QueuedOp) error
+}
+
+// FileQueueStore keeps the queue as a JSON file, replaced atomically on save.
+type FileQueueStore struct {
+	Path string
+}
+
+// NewFileQueueStore creates a FileQueueStore writing to path.
+func NewFileQueueStore(path string) *FileQueueStore {
+	return &FileQueueStore{Path: path}
+}
+
+// Load returns the stored operations; a missing file is an empty queue.
+func (s *FileQueueStore) Load() ([]QueuedOp, error) {
+	b, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("keygen: read queue: %w", err)
+	}
+	var ops []QueuedOp
+	if err := json.Unmarshal(b, &ops); err != nil {
+		return nil, fmt.Errorf("keygen: decode queue: %w", err)
+	}
+	return ops, nil
+}
+
+// Save writes ops to a temporary file and renames it over Path.
+func (s *FileQueueStore) Save(ops []QueuedOp) error {
+	b, err := json.Marshal(ops)
+	if err != nil {
+		return fmt.Errorf("keygen: encode queue: %w", err)
+	}
+	if err := writeFileAtomic(s.Path, b); err != nil {
+		return fmt.Errorf("keygen: write queue: %w", err)
+	}
+	return nil
+}
+
+// writeFileAtomic writes b to a temporary file next to path, syncs it and
+// renames it over path, so readers never see a partial file.
+func writeFileAtomic(path string, b []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// defaultMaxAttempts bounds the replays of an operation the API keeps
+// failing (e.g. with a 500), so it cannot block the queue forever.
+const defaultMaxAttempts = 20
+
+// OperationQueue records mutations that could not be sent (typically because
+// the node booted without internet) and replays them in order once
+// connectivity returns.
+type OperationQueue struct {
+	client      *Client
+	store       QueueStore
+	now         func() time.Time
+	maxAttempts int
+
+	mu sync.Mutex
+}
+
+// QueueOption configures the OperationQueue.
+type QueueOption func(*OperationQueue)
+
+// WithQueueMaxAttempts drops an operation after n failed replays in which the
+// API answered with 429 or 5xx (default: 20). Network errors while offline
+// do not count.
+func WithQueueMaxAttempts(n int) QueueOption {
+	return func(q *OperationQueue) {
+		if n > 0 {
+			q.maxAttempts = n
+		}
+	}
+}
+
+// NewOperationQueue creates a queue replaying through c and persisting to store.
+func NewOperationQueue(c *Client, store QueueStore, opts ...QueueOption) *OperationQueue {
+	q := &OperationQueue{client: c, store: store, now: time.Now, maxAttempts: defaultMaxAttempts}
+	for _, opt := range opts {
+		opt(q)
+	}
+	return q
+}
+
+// Do sends op immediately and queues it if the failure is transient
+// (network error, timeout, 429 or 5xx). queued reports whether op was queued;
+// in that case err is nil. While other operations are pending, op is queued
+// behind them without being attempted, so ordering is preserved; call Replay
+// to flush the queue.
+func (q *OperationQueue) Do(ctx context.Context, op QueuedOp) (queued bool, err error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	ops, err := q.store.Load()
+	if err != nil {
+		return false, err
+	}
+	op = q.prepare(op)
+	if len(ops) == 0 {
+		err := q.send(ctx, op)
+		if err == nil || !isTransient(err) {
+			return false, err
+		}
+	}
+	ops, added := q.add(ops, op)
+	if !added {
+		return true, nil
+	}
+	return true, q.store.Save(ops)
+}
+
+// Enqueue records op for later replay without attempting it. It reports
+// false when an operation with the same ID is already pending.
+func (q *OperationQueue) Enqueue(op QueuedOp) (bool, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	ops, err := q.store.Load()
+	if err != nil {
+		return false, err
+	}
+	ops, added := q.add(ops, q.prepare(op))
+	if !added {
+		return false, nil
+	}
+	return true, q.store.Save(ops)
+}
+
+// Pending returns the queued operations in replay order.
+func (q *OperationQueue) Pending() ([]QueuedOp, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.store.Load()
+}
+
+// Replay sends queued operations in order and returns how many completed.
+// It stops at the first transient failure, leaving that operation and the
+// rest queued, and when ctx is done. Operations the API rejects outright, or
+// keeps failing after the maximum number of attempts, are dropped and
+// reported in the returned error.
+func (q *OperationQueue) Replay(ctx context.Context) (int, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	ops, err := q.store.Load()
+	if err != nil {
+		return 0, err
+	}
+
+	done := 0
+	var rejected []error
+	for len(ops) > 0 {
+		if err := ctx.Err(); err != nil {
+			return done, errors.Join(append(rejected, err)...)
+		}
+		op := ops[0]
+		err := q.send(ctx, op)
+		if err != nil && ctx.Err() != nil {
+			return done, errors.Join(append(rejected, ctx.Err())...)
+		}
+		if err != nil && isTransient(err) {
+			var herr *HTTPError
+			if errors.As(err, &herr) {
+				ops[0].Attempts++
+			}
+			if ops[0].Attempts < q.maxAttempts {
+				if serr := q.store.Save(ops); serr != nil {
+					return done, serr
+				}
+				return done, errors.Join(append(rejected, fmt.Errorf("keygen: replay %s %s: %w", op.Kind, op.ID, err))...)
+			}
+			err = fmt.Errorf("giving up after %d attempts: %w", ops[0].Attempts, err)
+		}
+		if err != nil {
+			rejected = append(rejected, fmt.Errorf("keygen: dropped %s %s: %w", op.Kind, op.ID, err))
+		} else {
+			done++
+		}
+		ops = ops[1:]
+		if serr := q.store.Save(ops); serr != nil {
+			return done, serr
+		}
+	}
+	return done, errors.Join(rejected...)
+}
+
+// prepare fills in the ID, idempotency key and enqueue time of a new op.
+func (q *OperationQueue) prepare(op QueuedOp) QueuedOp {
+	if op.ID == "" {
+		op.ID = opID(op)
+		if op.IdempotencyKey == "" {
+			op.IdempotencyKey = NewIdempotencyKey()
+		}
+	}
+	if op.IdempotencyKey == "" {
+		op.IdempotencyKey = op.ID
+	}
+	if op.EnqueuedAt.IsZero() {
+		op.EnqueuedAt = q.now().UTC()
+	}
+	return op
+}
+
+// add appends op unless its ID is already pending. An activation or
+// deactivation only counts as a duplicate if no later operation touched the
+// same machine, so activate/deactivate/activate sequences are preserved.
+func (q *OperationQueue) add(ops []QueuedOp, op QueuedOp) ([]QueuedOp, bool) {
+	for i := len(ops) - 1; i >= 0; i-- {
+		if ops[i].ID == op.ID {
+			return ops, false
+		}
+		if sameMachineOp(ops[i], op) {
+			break
+		}
+	}
+	return append(ops, op), true
+}
+
+// send performs op under its idempotency key, treating "already in the
+// desired state" as success so that replays are idempotent.
+func (q *OperationQueue) send(ctx context.Context, op QueuedOp) error {
+	c := q.client
+	if op.IdempotencyKey != "" {
+		ctx = ContextWithIdempotencyKey(ctx, op.IdempotencyKey)
+	}
+	switch op.Kind {
+	case OpActivate:
+		err := c.ActivateMachine(ctx, op.LicenseKey, op.Fingerprint, op.Name, op.Platform)
+		if hasErrorCode(err, "FINGERPRINT_TAKEN") {
+			return nil // already activated
+		}
+		return err
+	case OpDeactivate:
+		_, err := c.DeactivateMachine(ctx, op.LicenseKey, op.Fingerprint)
+		return err // not found => already deactivated
+	case OpHeartbeat:
+		return c.pingMachineHeartbeat(ctx, op.MachineID)
+	case OpIncrementUsage:
+		return c.IncrementLicenseUsage(ctx, op.LicenseID, op.Increment)
+	default:
+		return fmt.Errorf("keygen: unknown queued operation kind %q", op.Kind)
+	}
+}
+
+func sameMachineOp(a, b QueuedOp) bool {
+	isMachineOp := func(k OpKind) bool { return k == OpActivate || k == OpDeactivate }
+	return isMachineOp(a.Kind) && isMachineOp(b.Kind) &&
+		a.LicenseKey == b.LicenseKey && a.Fingerprint == b.Fingerprint
+}
+
+// opID derives the default ID of op. Machine operations are identified by a
+// hash of the license key and fingerprint, as in validationID, so the key
+// never reaches idempotency headers or replay errors.
+func opID(op QueuedOp) string {
+	switch op.Kind {
+	case OpActivate, OpDeactivate:
+		return string(op.Kind) + ":" + validationID(op.LicenseKey, op.Fingerprint)
+	case OpHeartbeat:
+		return string(op.Kind) + ":" + op.MachineID
+	default:
+		b := make([]byte, 8)
+		_, _ = rand.Read(b)
+		return string(op.Kind) + ":" + hex.EncodeToString(b)
+	}
+}
@@ -0,0 +1,155 @@
+package keygen
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestOperationQueue_QueuesOfflineAndReplaysInOrder(t *testing.T) {
+	var online atomic.Bool
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !online.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		paths = append(paths, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	store := NewFileQueueStore(filepath.Join(t.TempDir(), "queue.json"))
+	q := NewOperationQueue(New("acct", "token", WithBaseURL(srv.URL)), store)
+
+	queued, err := q.Do(ctx, QueuedOp{Kind: OpHeartbeat, MachineID: "m1"})
+	if err != nil || !queued {
+		t.Fatalf("Do heartbeat offline: queued=%v err=%v", queued, err)
+	}
+	if _, err := q.Do(ctx, QueuedOp{Kind: OpIncrementUsage, LicenseID: "l1", Increment: 2}); err != nil {
+		t.Fatalf("Do increment offline: %v", err)
+	}
+	if added, err := q.Enqueue(QueuedOp{Kind: OpHeartbeat, MachineID: "m1"}); err != nil || added {
+		t.Fatalf("duplicate heartbeat: added=%v err=%v", added, err)
+	}
+
+	// A fresh queue over the same store sees the persisted operations.
+	q = NewOperationQueue(New("acct", "token", WithBaseURL(srv.URL)), store)
+	if pending, _ := q.Pending(); len(pending) != 2 {
+		t.Fatalf("expected 2 pending ops, got %+v", pending)
+	}
+
+	online.Store(true)
+	n, err := q.Replay(ctx)
+	if err != nil || n != 2 {
+		t.Fatalf("Replay: n=%d err=%v", n, err)
+	}
+	want := []string{"/accounts/acct/machines/m1/actions/ping", "/accounts/acct/licenses/l1/actions/increment-usage"}
+	if len(paths) != 2 || paths[0] != want[0] || paths[1] != want[1] {
+		t.Fatalf("unexpected replay order: %v", paths)
+	}
+	if pending, _ := q.Pending(); len(pending) != 0 {
+		t.Fatalf("expected empty queue, got %+v", pending)
+	}
+}
+
+func TestOperationQueue_KeepsOppositeMachineOps(t *testing.T) {
+	q := NewOperationQueue(New("acct", "token"), &memQueueStore{})
+	for _, k := range []OpKind{OpActivate, OpDeactivate, OpActivate, OpActivate} {
+		if _, err := q.Enqueue(QueuedOp{Kind: k, LicenseKey: "KEY", Fingerprint: "fp"}); err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+	}
+	pending, _ := q.Pending()
+	if len(pending) != 3 {
+		t.Fatalf("expected activate/deactivate/activate, got %+v", pending)
+	}
+}
+
+func TestOperationQueue_IdempotencyAndAttempts(t *testing.T) {
+	var keys []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	store := &memQueueStore{}
+	q := NewOperationQueue(New("acct", "token", WithBaseURL(srv.URL)), store, WithQueueMaxAttempts(2))
+	if queued, err := q.Do(ctx, QueuedOp{Kind: OpIncrementUsage, LicenseID: "l1", Increment: 1}); err != nil || !queued {
+		t.Fatalf("Do: queued=%v err=%v", queued, err)
+	}
+
+	// A cancelled replay leaves the queue untouched.
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := q.Replay(cctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("cancelled Replay: got %v", err)
+	}
+	if pending, _ := q.Pending(); len(pending) != 1 || pending[0].Attempts != 0 {
+		t.Fatalf("cancelled Replay changed the queue: %+v", pending)
+	}
+
+	if _, err := q.Replay(ctx); err == nil {
+		t.Fatalf("first Replay: expected an error")
+	}
+	if pending, _ := q.Pending(); len(pending) != 1 || pending[0].Attempts != 1 {
+		t.Fatalf("after first Replay: %+v", pending)
+	}
+	if n, err := q.Replay(ctx); n != 0 || err == nil {
+		t.Fatalf("second Replay: n=%d err=%v", n, err)
+	}
+	if pending, _ := q.Pending(); len(pending) != 0 {
+		t.Fatalf("op not dropped after max attempts: %+v", pending)
+	}
+
+	if len(keys) != 3 || keys[0] == "" || keys[1] != keys[0] || keys[2] != keys[0] {
+		t.Fatalf("Idempotency-Key not stable across attempts: %q", keys)
+	}
+}
+
+func TestOperationQueue_KeepsLicenseKeyOutOfIDs(t *testing.T) {
+	const licenseKey = "SECRET-LICENSE-KEY"
+	var keys []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	q := NewOperationQueue(New("acct", "token", WithBaseURL(srv.URL)), &memQueueStore{}, WithQueueMaxAttempts(1))
+	if _, err := q.Do(ctx, QueuedOp{Kind: OpDeactivate, LicenseKey: licenseKey, Fingerprint: "fp"}); err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	pending, _ := q.Pending()
+	if len(pending) != 1 || pending[0].IdempotencyKey == "" {
+		t.Fatalf("pending = %+v", pending)
+	}
+	if op := pending[0]; strings.Contains(op.ID, licenseKey) || strings.Contains(op.IdempotencyKey, licenseKey) {
+		t.Fatalf("pending op carries the license key: %+v", op)
+	}
+	if _, err := q.Replay(ctx); err == nil || strings.Contains(err.Error(), licenseKey) {
+		t.Fatalf("Replay error = %v", err)
+	}
+	for _, k := range keys {
+		if strings.Contains(k, licenseKey) {
+			t.Fatalf("Idempotency-Key = %q", k)
+		}
+	}
+}
+
+type memQueueStore struct{ ops []QueuedOp }
+
+func (m *memQueueStore) Load() ([]QueuedOp, error) { return append([]QueuedOp(nil), m.ops...), nil }
+func (m *memQueueStore) Save(ops []QueuedOp) error {
+	m.ops = append([]QueuedOp(nil), ops...)
+	return nil
+}
@@ -0,0 +1,56 @@
+package keygen
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RateLimit is the account's rate limit window as reported by the
+// X-RateLimit-* headers of the latest response.
+type RateLimit struct {
+	Window    string    // e.g. "30s"
+	Limit     int       // requests allowed per window
+	Remaining int       // requests left in the current window
+	Reset     time.Time // when the current window resets
+	Updated   time.Time // when these values were received; zero if never
+}
+
+// RateLimiter throttles outgoing requests. *rate.Limiter from
+// golang.org/x/time/rate satisfies it.
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+}
+
+// WithRateLimiter makes every request wait on l first, so batch jobs slow
+// down before Keygen starts answering 429 to the whole account (including
+// live device validations).
+func WithRateLimiter(l RateLimiter) Option {
+	return func(c *Client) { c.limiter = l }
+}
+
+// RateLimit returns the rate limit state seen on the latest response that
+// carried X-RateLimit-* headers.
+func (c *Client) RateLimit() RateLimit {
+	c.rateMu.Lock()
+	defer c.rateMu.Unlock()
+	return c.rateLimit
+}
+
+// recordRateLimit stores the X-RateLimit-* headers of h, if present.
+func (c *Client) recordRateLimit(h http.Header, now time.Time) {
+	limit, err := strconv.Atoi(h.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return
+	}
+	rl := RateLimit{Window: h.Get("X-RateLimit-Window"), Limit: limit, Updated: now}
+	rl.Remaining, _ = strconv.Atoi(h.Get("X-RateLimit-Remaining"))
+	if reset, err := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		rl.Reset = time.Unix(reset, 0)
+	}
+
+	c.rateMu.Lock()
+	c.rateLimit = rl
+	c.rateMu.Unlock()
+}
@@ -0,0 +1,42 @@
+package keygen
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type countingLimiter struct{ waits int }
+
+func (l *countingLimiter) Wait(context.Context) error {
+	l.waits++
+	return nil
+}
+
+func TestRateLimit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Window", "30s")
+		w.Header().Set("X-RateLimit-Limit", "60")
+		w.Header().Set("X-RateLimit-Remaining", "58")
+		w.Header().Set("X-RateLimit-Reset", "1791892800")
+		_, _ = w.Write([]byte(`{"data":{"id":"lic-1"}}`))
+	}))
+	defer srv.Close()
+
+	l := &countingLimiter{}
+	c := New("acct", "token", WithBaseURL(srv.URL), WithRateLimiter(l))
+	if !c.RateLimit().Updated.IsZero() {
+		t.Fatal("RateLimit set before any request")
+	}
+	if _, err := c.GetLicense(context.Background(), "lic-1"); err != nil {
+		t.Fatal(err)
+	}
+	rl := c.RateLimit()
+	if rl.Limit != 60 || rl.Remaining != 58 || rl.Window != "30s" || rl.Reset.Unix() != 1791892800 {
+		t.Fatalf("unexpected RateLimit %+v", rl)
+	}
+	if l.waits != 1 {
+		t.Fatalf("limiter waited %d times, want 1", l.waits)
+	}
+}
@@ -0,0 +1,60 @@
+package keygen
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// noRedirect asks Keygen to return artifact upload/download URLs in
+// links.redirect instead of answering with a 307.
+var noRedirect = http.Header{"Prefer": {"no-redirect"}}
+
+// GetRelease returns a release by ID, version or tag.
+func (c *Client) GetRelease(ctx context.Context, releaseID string) (Release, error) {
+	path := fmt.Sprintf("/accounts/%s/releases/%s", c.accountID, releaseID)
+
+	var resp releaseResponse
+	if err := c.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return Release{}, err
+	}
+	return resp.Data.toRelease(), nil
+}
+
+// ListReleases lists releases matching f, e.g. every stable release of a
+// product.
+func (c *Client) ListReleases(ctx context.Context, f ReleaseFilter) ([]Release, error) {
+	return listAll(ctx, c, "/releases", f.values(), releaseResource.toRelease)
+}
+
+// CheckForUpgrade asks Keygen for the newest release of product on channel
+// that upgrades currentVersion. Upgrade.Available is false when the device
+// is already up to date; otherwise Upgrade.URL downloads the new artifact.
+func (c *Client) CheckForUpgrade(ctx context.Context, product, currentVersion, channel string) (Upgrade, error) {
+	q := url.Values{}
+	q.Set("product", product)
+	q.Set("version", currentVersion)
+	setIfNotEmpty(q, "channel", channel)
+	path := fmt.Sprintf("/accounts/%s/releases/actions/upgrade?%s", c.accountID, q.Encode())
+
+	var resp upgradeResponse
+	if err := c.doHeader(ctx, http.MethodGet, path, noRedirect, nil, &resp); err != nil {
+		return Upgrade{}, err
+	}
+	up := Upgrade{Current: currentVersion}
+	if resp.Data.ID == "" {
+		return up, nil // 204: no upgrade available
+	}
+	up.Available = true
+	up.Next = resp.Meta.Next
+	up.Artifact = resp.Data.toArtifact()
+	up.URL = resp.Links.Redirect
+	return up, nil
+}
+
+// ListArtifacts lists the artifacts of a release.
+func (c *Client) ListArtifacts(ctx context.Context, releaseID string) ([]Artifact, error) {
+	path := fmt.Sprintf("/releases/%s/artifacts", releaseID)
+	return listAll(ctx, c, path, nil, artifactResource.toArtifact)
+}
@@ -0,0 +1,188 @@
+//go:build !keygen_minimal
+
+package keygen
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// CreateRelease creates a NOT_PUBLISHED release for r.ProductID (and
+// r.PackageID, if set). Version and Channel are required.
+func (c *Client) CreateRelease(ctx context.Context, r Release) (Release, error) {
+	if r.ProductID == "" {
+		return Release{}, fmt.Errorf("keygen: release product is required")
+	}
+	var req releaseRequest
+	req.Data.Type = "releases"
+	req.Data.Attributes = releaseAttributes{
+		Version: r.Version,
+		Channel: r.Channel,
+	}
+	a := &req.Data.Attributes
+	if r.Name != "" {
+		a.Name = &r.Name
+	}
+	if r.Tag != "" {
+		a.Tag = &r.Tag
+	}
+	if r.Description != "" {
+		a.Description = &r.Description
+	}
+	if r.Metadata != nil {
+		a.Metadata = &r.Metadata
+	}
+	rel := &releaseRelationships{
+		Product: licenseRelationship{Data: relationshipData{Type: "products", ID: r.ProductID}},
+	}
+	if r.PackageID != "" {
+		rel.Package = &optionalRelationship{Data: &relationshipData{Type: "packages", ID: r.PackageID}}
+	}
+	req.Data.Relationships = rel
+
+	var resp releaseResponse
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("/accounts/%s/releases", c.accountID), req, &resp); err != nil {
+		return Release{}, err
+	}
+	return resp.Data.toRelease(), nil
+}
+
+// UpdateRelease PATCHes the attributes set in u and returns the updated
+// release. Version and channel cannot be changed.
+func (c *Client) UpdateRelease(ctx context.Context, releaseID string, u ReleaseUpdate) (Release, error) {
+	var req releaseRequest
+	req.Data.Type = "releases"
+	req.Data.Attributes = releaseAttributes{
+		Name:        u.Name,
+		Tag:         u.Tag,
+		Description: u.Description,
+	}
+	if u.Metadata != nil {
+		req.Data.Attributes.Metadata = &u.Metadata
+	}
+	path := fmt.Sprintf("/accounts/%s/releases/%s", c.accountID, releaseID)
+
+	var resp releaseResponse
+	if err := c.do(ctx, http.MethodPatch, path, req, &resp); err != nil {
+		return Release{}, err
+	}
+	return resp.Data.toRelease(), nil
+}
+
+// PublishRelease makes a release visible to licensees.
+func (c *Client) PublishRelease(ctx context.Context, releaseID string) (Release, error) {
+	return c.releaseAction(ctx, releaseID, "publish")
+}
+
+// YankRelease withdraws a published release; its artifacts can no longer be
+// downloaded.
+func (c *Client) YankRelease(ctx context.Context, releaseID string) (Release, error) {
+	return c.releaseAction(ctx, releaseID, "yank")
+}
+
+func (c *Client) releaseAction(ctx context.Context, releaseID, action string) (Release, error) {
+	path := fmt.Sprintf("/accounts/%s/releases/%s/actions/%s", c.accountID, releaseID, action)
+
+	var resp releaseResponse
+	if err := c.do(ctx, http.MethodPost, path, nil, &resp); err != nil {
+		return Release{}, err
+	}
+	return resp.Data.toRelease(), nil
+}
+
+// DeleteRelease deletes a release and its artifacts.
+func (c *Client) DeleteRelease(ctx context.Context, releaseID string) error {
+	path := fmt.Sprintf("/accounts/%s/releases/%s", c.accountID, releaseID)
+	return c.do(ctx, http.MethodDelete, path, nil, nil)
+}
+
+// UploadArtifact creates artifact a for a.ReleaseID and uploads size bytes
+// of body to the storage URL Keygen hands back. The returned artifact is in
+// the state reported before the upload (usually WAITING).
+func (c *Client) UploadArtifact(ctx context.Context, a Artifact, body io.Reader, size int64) (Artifact, error) {
+	if a.ReleaseID == "" {
+		return Artifact{}, fmt.Errorf("keygen: artifact release is required")
+	}
+	var req artifactCreateRequest
+	req.Data.Type = "artifacts"
+	attrs := &req.Data.Attributes
+	attrs.Filename = a.Filename
+	attrs.Filetype = a.Filetype
+	attrs.Filesize = size
+	attrs.Platform = a.Platform
+	attrs.Arch = a.Arch
+	attrs.Checksum = a.Checksum
+	attrs.Signature = a.Signature
+	attrs.Metadata = a.Metadata
+	req.Data.Relationships.Release.Data = relationshipData{Type: "releases", ID: a.ReleaseID}
+
+	var resp artifactResponse
+	path := fmt.Sprintf("/accounts/%s/artifacts", c.accountID)
+	if err := c.doHeader(ctx, http.MethodPost, path, noRedirect, req, &resp); err != nil {
+		return Artifact{}, err
+	}
+	if resp.Links.Redirect == "" {
+		return Artifact{}, fmt.Errorf("keygen: artifact %s: no upload URL in response", resp.Data.ID)
+	}
+
+	// The URL is pre-signed: it must not carry our bearer token.
+	put, err := http.NewRequestWithContext(ctx, http.MethodPut, resp.Links.Redirect, body)
+	if err != nil {
+		return Artifact{}, fmt.Errorf("keygen: new upload request: %w", err)
+	}
+	put.ContentLength = size
+	up, err := c.http.Do(put)
+	if err != nil {
+		return Artifact{}, fmt.Errorf("keygen: upload artifact: %w", err)
+	}
+	defer up.Body.Close()
+	if up.StatusCode < 200 || up.StatusCode >= 300 {
+		b, _ := io.ReadAll(up.Body)
+		return Artifact{}, &HTTPError{
+			Method:     http.MethodPut,
+			Path:       put.URL.Path,
+			StatusCode: up.StatusCode,
+			Body:       string(b),
+		}
+	}
+	return resp.Data.toArtifact(), nil
+}
+
+// YankArtifact yanks an artifact so it can no longer be downloaded.
+func (c *Client) YankArtifact(ctx context.Context, artifactID string) error {
+	path := fmt.Sprintf("/accounts/%s/artifacts/%s", c.accountID, artifactID)
+	return c.do(ctx, http.MethodDelete, path, nil, nil)
+}
+
+// CreatePackage creates a package under p.ProductID.
+func (c *Client) CreatePackage(ctx context.Context, p Package) (Package, error) {
+	var req packageCreateRequest
+	req.Data.Type = "packages"
+	req.Data.Attributes.Name = p.Name
+	req.Data.Attributes.Key = p.Key
+	req.Data.Attributes.Engine = p.Engine
+	req.Data.Attributes.Metadata = p.Metadata
+	req.Data.Relationships.Product.Data = relationshipData{Type: "products", ID: p.ProductID}
+
+	var resp packageResponse
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("/accounts/%s/packages", c.accountID), req, &resp); err != nil {
+		return Package{}, err
+	}
+	return resp.Data.toPackage(), nil
+}
+
+// ListPackages lists packages, restricted to productID when non-empty.
+func (c *Client) ListPackages(ctx context.Context, productID string) ([]Package, error) {
+	q := url.Values{}
+	setIfNotEmpty(q, "product", productID)
+	return listAll(ctx, c, "/packages", q, packageResource.toPackage)
+}
+
+// DeletePackage deletes a package.
+func (c *Client) DeletePackage(ctx context.Context, packageID string) error {
+	path := fmt.Sprintf("/accounts/%s/packages/%s", c.accountID, packageID)
+	return c.do(ctx, http.MethodDelete, path, nil, nil)
+}
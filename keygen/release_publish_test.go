@@ -0,0 +1,92 @@
+//go:build !keygen_minimal
+
+package keygen
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestReleases(t *testing.T) {
+	const resource = `{"id":"rel-1","attributes":{"version":"1.2.0","channel":"stable","status":"NOT_PUBLISHED"},` +
+		`"relationships":{"product":{"data":{"type":"products","id":"prod-1"}},"package":{"data":null}}}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "POST /accounts/acct/releases":
+			var req releaseRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Errorf("decode: %v", err)
+			}
+			if req.Data.Attributes.Version != "1.2.0" || req.Data.Relationships == nil || req.Data.Relationships.Product.Data.ID != "prod-1" {
+				t.Errorf("unexpected create request: %+v", req.Data)
+			}
+			_, _ = w.Write([]byte(`{"data":` + resource + `}`))
+		case "POST /accounts/acct/releases/rel-1/actions/publish":
+			_, _ = w.Write([]byte(`{"data":` + strings.Replace(resource, "NOT_PUBLISHED", "PUBLISHED", 1) + `}`))
+		case "GET /accounts/acct/releases":
+			q := r.URL.Query()
+			if q.Get("product") != "prod-1" || q.Get("channel") != "stable" || q["entitlements[]"][0] != "PRO" {
+				t.Errorf("unexpected list query: %v", q)
+			}
+			_, _ = w.Write([]byte(`{"data":[` + resource + `],"links":{}}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	c := New("acct", "token", WithBaseURL(srv.URL))
+	rel, err := c.CreateRelease(ctx, Release{Version: "1.2.0", Channel: "stable", ProductID: "prod-1"})
+	if err != nil || rel.ID != "rel-1" || rel.ProductID != "prod-1" || rel.PackageID != "" {
+		t.Fatalf("CreateRelease = %+v, %v", rel, err)
+	}
+	if rel, err = c.PublishRelease(ctx, "rel-1"); err != nil || rel.Status != "PUBLISHED" {
+		t.Fatalf("PublishRelease = %+v, %v", rel, err)
+	}
+	list, err := c.ListReleases(ctx, ReleaseFilter{Product: "prod-1", Channel: "stable", Entitlements: []string{"PRO"}})
+	if err != nil || len(list) != 1 {
+		t.Fatalf("ListReleases = %+v, %v", list, err)
+	}
+}
+
+func TestUploadArtifact(t *testing.T) {
+	var uploaded string
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "POST /accounts/acct/artifacts":
+			if r.Header.Get("Prefer") != "no-redirect" {
+				t.Errorf("missing Prefer: no-redirect header")
+			}
+			_, _ = w.Write([]byte(`{"data":{"id":"art-1","attributes":{"filename":"pkg.tar.xz","status":"WAITING"},` +
+				`"relationships":{"release":{"data":{"type":"releases","id":"rel-1"}}}},` +
+				`"links":{"redirect":"` + srv.URL + `/storage/art-1"}}`))
+		case "PUT /storage/art-1":
+			if r.Header.Get("Authorization") != "" {
+				t.Errorf("upload must not carry the API token")
+			}
+			b, _ := io.ReadAll(r.Body)
+			uploaded = string(b)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	c := New("acct", "token", WithBaseURL(srv.URL))
+	a, err := c.UploadArtifact(context.Background(), Artifact{Filename: "pkg.tar.xz", ReleaseID: "rel-1"}, strings.NewReader("payload"), 7)
+	if err != nil || a.ID != "art-1" || a.ReleaseID != "rel-1" {
+		t.Fatalf("UploadArtifact = %+v, %v", a, err)
+	}
+	if uploaded != "payload" {
+		t.Fatalf("uploaded %q, want %q", uploaded, "payload")
+	}
+}
@@ -0,0 +1,40 @@
+package keygen
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCheckForUpgrade(t *testing.T) {
+	latest := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/accounts/acct/releases/actions/upgrade" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		q := r.URL.Query()
+		if q.Get("product") != "prod-1" || q.Get("version") != "1.0.0" || q.Get("channel") != "stable" {
+			t.Errorf("unexpected query: %v", q)
+		}
+		if latest {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"id":"art-2","attributes":{"filename":"pkg-1.1.0.tar.xz"}},` +
+			`"meta":{"current":"1.0.0","next":"1.1.0"},"links":{"redirect":"https://s3.example/art-2"}}`))
+	}))
+	defer srv.Close()
+
+	c := New("acct", "token", WithBaseURL(srv.URL))
+	up, err := c.CheckForUpgrade(context.Background(), "prod-1", "1.0.0", "stable")
+	if err != nil || !up.Available || up.Next != "1.1.0" || up.URL != "https://s3.example/art-2" || up.Artifact.ID != "art-2" {
+		t.Fatalf("CheckForUpgrade = %+v, %v", up, err)
+	}
+
+	latest = true
+	up, err = c.CheckForUpgrade(context.Background(), "prod-1", "1.0.0", "stable")
+	if err != nil || up.Available || up.Current != "1.0.0" {
+		t.Fatalf("CheckForUpgrade (up to date) = %+v, %v", up, err)
+	}
+}
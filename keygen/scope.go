@@ -0,0 +1,181 @@
+package keygen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// TokenKind classifies the bearer behind the client's API token.
+type TokenKind string
+
+const (
+	TokenKindAdmin       TokenKind = "admin"       // admin/developer/agent user tokens
+	TokenKindProduct     TokenKind = "product"     // product tokens
+	TokenKindEnvironment TokenKind = "environment" // environment tokens
+	TokenKindLicense     TokenKind = "license"     // license/activation tokens
+	TokenKindUser        TokenKind = "user"        // end-user tokens
+	TokenKindUnknown     TokenKind = "unknown"
+)
+
+// Broad reports whether tokens of this kind can act on the whole account
+// (or product/environment) rather than a single license or user.
+func (k TokenKind) Broad() bool {
+	return k == TokenKindAdmin || k == TokenKindProduct || k == TokenKindEnvironment
+}
+
+// Identity is the bearer returned by WhoAmI.
+type Identity struct {
+	ID   string    `json:"id"`
+	Type string    `json:"type"`           // JSON:API type, e.g. "users", "products", "licenses"
+	Role string    `json:"role,omitempty"` // user role, when the bearer is a user
+	Kind TokenKind `json:"kind"`
+}
+
+// ErrOverprivilegedToken is returned by device-side calls when
+// WithLeastPrivilegeEnforced is set and the token is broader than needed.
+var ErrOverprivilegedToken = errors.New("keygen: broad token used for a device-side operation")
+
+// WithLeastPrivilegeWarning calls warn (once per operation name) when a broad
+// token is used for device-side operations (validation, machine activation,
+// deactivation, heartbeats and process tracking) that an activation/license
+// token could perform.
+func WithLeastPrivilegeWarning(warn func(op string, id Identity)) Option {
+	return func(c *Client) { c.scope.warn = warn }
+}
+
+// WithLeastPrivilegeEnforced makes device-side operations fail with
+// ErrOverprivilegedToken when the token is broader than a license token.
+func WithLeastPrivilegeEnforced() Option {
+	return func(c *Client) { c.scope.enforce = true }
+}
+
+// scopeGuard lazily resolves the token identity for least-privilege checks.
+type scopeGuard struct {
+	warn    func(op string, id Identity)
+	enforce bool
+
+	mu      sync.Mutex
+	id      *Identity
+	pending *identityLookup // WhoAmI in flight, shared by concurrent checks
+	warned  map[string]bool
+}
+
+// identityLookup is one WhoAmI call whose result is shared by every check
+// waiting on it.
+type identityLookup struct {
+	done chan struct{}
+	id   Identity
+	err  error
+}
+
+// WhoAmI returns the identity of the token's bearer.
+func (c *Client) WhoAmI(ctx context.Context) (Identity, error) {
+	var resp meResponse
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/accounts/%s/me", c.accountID), nil, &resp); err != nil {
+		return Identity{}, err
+	}
+	id := Identity{
+		ID:   resp.Data.ID,
+		Type: resp.Data.Type,
+		Role: resp.Data.Attributes.Role,
+	}
+	switch id.Type {
+	case "users":
+		if id.Role == "user" {
+			id.Kind = TokenKindUser
+		} else {
+			id.Kind = TokenKindAdmin
+		}
+	case "products":
+		id.Kind = TokenKindProduct
+	case "environments":
+		id.Kind = TokenKindEnvironment
+	case "licenses":
+		id.Kind = TokenKindLicense
+	default:
+		id.Kind = TokenKindUnknown
+	}
+	return id, nil
+}
+
+// checkDeviceScope applies the least-privilege policy before a device-side
+// operation. If the identity cannot be resolved, WithLeastPrivilegeEnforced
+// fails the call (wrapping ErrOverprivilegedToken and the cause) while
+// warning-only mode lets it proceed; the lookup is retried on the next
+// operation either way.
+func (c *Client) checkDeviceScope(ctx context.Context, op string) error {
+	g := &c.scope
+	if g.warn == nil && !g.enforce {
+		return nil
+	}
+	if c.tokenKind != "" && !c.tokenKind.Broad() {
+		return nil
+	}
+
+	id, err := c.scopeIdentity(ctx)
+	if err != nil {
+		if g.enforce {
+			return fmt.Errorf("%w: %s: cannot verify token scope: %w", ErrOverprivilegedToken, op, err)
+		}
+		return nil
+	}
+	if !id.Kind.Broad() {
+		return nil
+	}
+
+	g.mu.Lock()
+	first := !g.warned[op]
+	if first {
+		if g.warned == nil {
+			g.warned = make(map[string]bool)
+		}
+		g.warned[op] = true
+	}
+	g.mu.Unlock()
+	if g.warn != nil && first {
+		g.warn(op, id)
+	}
+	if g.enforce {
+		return fmt.Errorf("%w: %s with %s token", ErrOverprivilegedToken, op, id.Kind)
+	}
+	return nil
+}
+
+// scopeIdentity returns the cached token identity, resolving it with a
+// single WhoAmI shared by concurrent callers. Failures are not cached.
+func (c *Client) scopeIdentity(ctx context.Context) (Identity, error) {
+	g := &c.scope
+	g.mu.Lock()
+	if g.id != nil {
+		id := *g.id
+		g.mu.Unlock()
+		return id, nil
+	}
+	l := g.pending
+	if l == nil {
+		l = &identityLookup{done: make(chan struct{})}
+		g.pending = l
+		g.mu.Unlock()
+
+		l.id, l.err = c.WhoAmI(ctx)
+		g.mu.Lock()
+		if l.err == nil {
+			g.id = &l.id
+		}
+		g.pending = nil
+		g.mu.Unlock()
+		close(l.done)
+		return l.id, l.err
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-l.done:
+		return l.id, l.err
+	case <-ctx.Done():
+		return Identity{}, ctx.Err()
+	}
+}
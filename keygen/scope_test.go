@@ -0,0 +1,62 @@
+package keygen
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCheckDeviceScope_FailsClosed(t *testing.T) {
+	var down atomic.Bool
+	var lookups atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lookups.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		if down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"id":"u1","type":"users","attributes":{"role":"admin"}}}`))
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+
+	down.Store(true)
+	c := New("acct", "token", WithBaseURL(srv.URL), WithLeastPrivilegeEnforced())
+	err := c.checkDeviceScope(ctx, "Validate")
+	if !errors.Is(err, ErrOverprivilegedToken) || !isTransient(err) {
+		t.Fatalf("unresolved identity under enforce: got %v", err)
+	}
+
+	warned := 0
+	w := New("acct", "token", WithBaseURL(srv.URL), WithLeastPrivilegeWarning(func(string, Identity) { warned++ }))
+	if err := w.checkDeviceScope(ctx, "Validate"); err != nil {
+		t.Fatalf("unresolved identity in warning mode: got %v", err)
+	}
+
+	// Concurrent checks share one lookup; the failed lookup above is retried.
+	down.Store(false)
+	lookups.Store(0)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := c.checkDeviceScope(ctx, "Validate"); !errors.Is(err, ErrOverprivilegedToken) {
+				t.Errorf("admin token under enforce: got %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := lookups.Load(); n != 1 {
+		t.Fatalf("WhoAmI called %d times, want 1", n)
+	}
+	if err := w.checkDeviceScope(ctx, "Validate"); err != nil || warned != 1 {
+		t.Fatalf("warning mode: err %v, %d warnings", err, warned)
+	}
+}
@@ -0,0 +1,112 @@
+package keygen
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ResponseSignatureError is returned when WithResponseVerification is enabled
+// and a response's Keygen-Signature or Digest header is missing or does not
+// verify, i.e. the response may not come from Keygen.
+type ResponseSignatureError struct {
+	Method    string
+	Path      string
+	RequestID string
+	Reason    string
+}
+
+func (e *ResponseSignatureError) Error() string {
+	return fmt.Sprintf("keygen: %s %s: response signature invalid: %s", e.Method, e.Path, e.Reason)
+}
+
+// defaultResponseTolerance is how far a signed response's Date may be from
+// the local clock under WithResponseVerification.
+const defaultResponseTolerance = 5 * time.Minute
+
+// WithResponseVerification verifies the Ed25519 Keygen-Signature header (and
+// the Digest it covers) of every successful response against the account's
+// public key, so a spoofing proxy cannot forge activations or validations.
+// The signed Date must be within 5 minutes of the local clock (see
+// WithResponseDateTolerance), so older genuine responses cannot be replayed.
+// Error responses are not verified.
+func WithResponseVerification(pubKey ed25519.PublicKey) Option {
+	return func(c *Client) { c.responseKey = pubKey }
+}
+
+// WithResponseDateTolerance sets how far the signed Date of a response may
+// be from the local clock under WithResponseVerification, e.g. for devices
+// with a poor clock. Larger values widen the replay window.
+func WithResponseDateTolerance(d time.Duration) Option {
+	return func(c *Client) {
+		if d > 0 {
+			c.responseTolerance = d
+		}
+	}
+}
+
+// verifyResponseSignature checks resp's Keygen-Signature header, e.g.
+//
+//	keyid="<account>", algorithm="ed25519", signature="<base64>",
+//	headers="(request-target) host date digest"
+//
+// The digest and date must be among the signed headers; the digest must
+// match body and the date be within tolerance of now.
+func verifyResponseSignature(key ed25519.PublicKey, req *http.Request, resp *http.Response, body []byte, now time.Time, tolerance time.Duration) string {
+	header := resp.Header.Get("Keygen-Signature")
+	if header == "" {
+		return "missing Keygen-Signature header"
+	}
+	params := map[string]string{}
+	for _, part := range strings.Split(header, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if ok {
+			params[strings.ToLower(k)] = strings.Trim(v, `"`)
+		}
+	}
+	if alg := params["algorithm"]; alg != "ed25519" {
+		return fmt.Sprintf("unsupported algorithm %q", alg)
+	}
+	sig, err := base64.StdEncoding.DecodeString(params["signature"])
+	if err != nil {
+		return "malformed signature"
+	}
+	names := strings.Fields(strings.ToLower(params["headers"]))
+	for _, required := range []string{"(request-target)", "date", "digest"} {
+		if !slices.Contains(names, required) {
+			return "signature must cover (request-target), date and digest"
+		}
+	}
+
+	lines := make([]string, len(names))
+	for i, name := range names {
+		var v string
+		switch name {
+		case "(request-target)":
+			v = strings.ToLower(req.Method) + " " + req.URL.RequestURI()
+		case "host":
+			v = req.URL.Host
+		default:
+			v = resp.Header.Get(name)
+		}
+		lines[i] = name + ": " + v
+	}
+	if !ed25519.Verify(key, []byte(strings.Join(lines, "\n")), sig) {
+		return "signature mismatch"
+	}
+	if err := verifyDigest(resp.Header.Get("Digest"), body); err != nil {
+		return err.Error()
+	}
+	date, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return "malformed Date header"
+	}
+	if skew := now.Sub(date).Abs(); skew > tolerance {
+		return fmt.Sprintf("response dated %s is outside the %s tolerance (possible replay)", date.UTC().Format(time.RFC3339), tolerance)
+	}
+	return ""
+}
@@ -0,0 +1,71 @@
+package keygen
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResponseVerification(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	body := `{"data":{"id":"lic-1","attributes":{"key":"ABC"}}}`
+	tamper := false
+	date := time.Now().UTC().Format(http.TimeFormat)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sum := sha256.Sum256([]byte(body))
+		digest := "sha-256=" + base64.StdEncoding.EncodeToString(sum[:])
+		signing := strings.Join([]string{
+			"(request-target): " + strings.ToLower(r.Method) + " " + r.URL.RequestURI(),
+			"host: " + r.Host,
+			"date: " + date,
+			"digest: " + digest,
+		}, "\n")
+		sig := base64.StdEncoding.EncodeToString(ed25519.Sign(priv, []byte(signing)))
+		w.Header().Set("Date", date)
+		w.Header().Set("Digest", digest)
+		w.Header().Set("Keygen-Signature", `keyid="acct", algorithm="ed25519", signature="`+sig+`", headers="(request-target) host date digest"`)
+		if tamper {
+			_, _ = w.Write([]byte(strings.Replace(body, "ABC", "XYZ", 1)))
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	c := New("acct", "token", WithBaseURL(srv.URL), WithResponseVerification(pub))
+	if _, err := c.GetLicense(context.Background(), "lic-1"); err != nil {
+		t.Fatalf("signed response: %v", err)
+	}
+
+	tamper = true
+	var sigErr *ResponseSignatureError
+	if _, err := c.GetLicense(context.Background(), "lic-1"); !errors.As(err, &sigErr) {
+		t.Fatalf("tampered body: got %v, want *ResponseSignatureError", err)
+	}
+
+	// A genuine but old response (e.g. a past "valid" answer) is a replay.
+	tamper = false
+	date = time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)
+	if _, err := c.GetLicense(context.Background(), "lic-1"); !errors.As(err, &sigErr) || !strings.Contains(sigErr.Reason, "replay") {
+		t.Fatalf("replayed response: got %v", err)
+	}
+	c = New("acct", "token", WithBaseURL(srv.URL), WithResponseVerification(pub), WithResponseDateTolerance(2*time.Hour))
+	if _, err := c.GetLicense(context.Background(), "lic-1"); err != nil {
+		t.Fatalf("within a wider tolerance: %v", err)
+	}
+	date = time.Now().UTC().Format(http.TimeFormat)
+
+	other, _, _ := ed25519.GenerateKey(nil)
+	tamper = false
+	c = New("acct", "token", WithBaseURL(srv.URL), WithResponseVerification(other))
+	if _, err := c.GetLicense(context.Background(), "lic-1"); !errors.As(err, &sigErr) || sigErr.Reason != "signature mismatch" {
+		t.Fatalf("wrong key: got %v", err)
+	}
+}
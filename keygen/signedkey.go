@@ -0,0 +1,184 @@
+package keygen
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// ErrInvalidSignedKey is returned by VerifySignedKey when a license key is
+// malformed or was not produced by the account's private key.
+var ErrInvalidSignedKey = errors.New("keygen: invalid signed license key")
+
+// Cryptographic license key schemes (Policy.Scheme) supported by
+// KeyVerifier.
+const (
+	SchemeEd25519Sign         = "ED25519_SIGN"
+	SchemeRSA2048PKCS1SignV2  = "RSA_2048_PKCS1_SIGN_V2"
+	SchemeRSA2048PSSSignV2    = "RSA_2048_PKCS1_PSS_SIGN_V2"
+	SchemeRSA2048PKCS1Encrypt = "RSA_2048_PKCS1_ENCRYPT"
+	SchemeRSA2048JWTRS256     = "RSA_2048_JWT_RS256"
+)
+
+// KeyVerifier verifies cryptographic license keys offline, as a first line
+// of defence before (or instead of) calling Validate.
+type KeyVerifier struct {
+	scheme  string
+	ed25519 ed25519.PublicKey
+	rsa     *rsa.PublicKey
+}
+
+// NewKeyVerifier returns a KeyVerifier for a policy scheme. publicKey is the
+// account's hex-encoded Ed25519 key for ED25519_SIGN and its PEM-encoded RSA
+// key for the RSA_2048_* schemes (see Account).
+func NewKeyVerifier(scheme, publicKey string) (*KeyVerifier, error) {
+	v := &KeyVerifier{scheme: scheme}
+	switch scheme {
+	case SchemeEd25519Sign:
+		key, err := hex.DecodeString(publicKey)
+		if err != nil || len(key) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("keygen: malformed Ed25519 public key")
+		}
+		v.ed25519 = key
+	case SchemeRSA2048PKCS1SignV2, SchemeRSA2048PSSSignV2, SchemeRSA2048PKCS1Encrypt, SchemeRSA2048JWTRS256:
+		key, err := parseRSAPublicKey(publicKey)
+		if err != nil {
+			return nil, err
+		}
+		v.rsa = key
+	default:
+		return nil, fmt.Errorf("keygen: unsupported license key scheme %q", scheme)
+	}
+	return v, nil
+}
+
+// VerifySignedKey checks key against the configured scheme and returns its
+// embedded payload: the signed dataset, the decrypted dataset for
+// RSA_2048_PKCS1_ENCRYPT, or the JSON claims for RSA_2048_JWT_RS256.
+func (v *KeyVerifier) VerifySignedKey(key string) ([]byte, error) {
+	switch v.scheme {
+	case SchemeRSA2048PKCS1Encrypt:
+		return v.verifyEncrypted(key)
+	case SchemeRSA2048JWTRS256:
+		return v.verifyJWT(key)
+	}
+
+	// "key/<base64url payload>.<base64url signature>", signed over everything
+	// before the dot.
+	signed, sig, ok := cutLast(key, ".")
+	if !ok || !strings.HasPrefix(signed, "key/") {
+		return nil, fmt.Errorf("%w: expected key/<payload>.<signature>", ErrInvalidSignedKey)
+	}
+	sigBytes, err := decodeBase64(sig)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature", ErrInvalidSignedKey)
+	}
+	switch v.scheme {
+	case SchemeEd25519Sign:
+		if !ed25519.Verify(v.ed25519, []byte(signed), sigBytes) {
+			err = errors.New("signature mismatch")
+		}
+	case SchemeRSA2048PKCS1SignV2:
+		h := sha256.Sum256([]byte(signed))
+		err = rsa.VerifyPKCS1v15(v.rsa, crypto.SHA256, h[:], sigBytes)
+	case SchemeRSA2048PSSSignV2:
+		h := sha256.Sum256([]byte(signed))
+		err = rsa.VerifyPSS(v.rsa, crypto.SHA256, h[:], sigBytes, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthAuto})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignedKey, err)
+	}
+	payload, err := decodeBase64(strings.TrimPrefix(signed, "key/"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed payload", ErrInvalidSignedKey)
+	}
+	return payload, nil
+}
+
+// verifyEncrypted undoes Keygen's RSA "private encrypt" (PKCS#1 v1.5 type 1
+// padding) with the public key. crypto/rsa has no API for this direction.
+func (v *KeyVerifier) verifyEncrypted(key string) ([]byte, error) {
+	ct, err := decodeBase64(key)
+	if err != nil || len(ct) != v.rsa.Size() {
+		return nil, fmt.Errorf("%w: malformed encrypted key", ErrInvalidSignedKey)
+	}
+	c := new(big.Int).SetBytes(ct)
+	if c.Cmp(v.rsa.N) >= 0 {
+		return nil, fmt.Errorf("%w: malformed encrypted key", ErrInvalidSignedKey)
+	}
+	em := c.Exp(c, big.NewInt(int64(v.rsa.E)), v.rsa.N).FillBytes(make([]byte, v.rsa.Size()))
+
+	// 0x00 0x01 0xff...0xff 0x00 <payload>, with at least 8 bytes of 0xff.
+	if em[0] != 0 || em[1] != 1 {
+		return nil, fmt.Errorf("%w: bad padding", ErrInvalidSignedKey)
+	}
+	i := 2
+	for i < len(em) && em[i] == 0xff {
+		i++
+	}
+	if i-2 < 8 || i == len(em) || em[i] != 0 {
+		return nil, fmt.Errorf("%w: bad padding", ErrInvalidSignedKey)
+	}
+	return em[i+1:], nil
+}
+
+// verifyJWT checks an RS256 JWT and returns its claims.
+func (v *KeyVerifier) verifyJWT(key string) ([]byte, error) {
+	parts := strings.Split(key, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: expected a JWT", ErrInvalidSignedKey)
+	}
+	header, err := decodeBase64(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed JWT header", ErrInvalidSignedKey)
+	}
+	var h struct {
+		Alg string `json:"alg"`
+	}
+	if err := json.Unmarshal(header, &h); err != nil || h.Alg != "RS256" {
+		return nil, fmt.Errorf("%w: JWT alg must be RS256", ErrInvalidSignedKey)
+	}
+	sig, err := decodeBase64(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature", ErrInvalidSignedKey)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(v.rsa, crypto.SHA256, digest[:], sig); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignedKey, err)
+	}
+	claims, err := decodeBase64(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed JWT claims", ErrInvalidSignedKey)
+	}
+	return claims, nil
+}
+
+// parseRSAPublicKey accepts PKIX ("PUBLIC KEY") and PKCS#1 ("RSA PUBLIC
+// KEY") PEM blocks.
+func parseRSAPublicKey(s string) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode([]byte(s))
+	if block == nil {
+		return nil, fmt.Errorf("keygen: malformed RSA public key: no PEM block")
+	}
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("keygen: malformed RSA public key: %w", err)
+	}
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("keygen: public key is %T, not RSA", key)
+	}
+	return rsaKey, nil
+}
@@ -0,0 +1,83 @@
+package keygen
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestVerifySignedKey_Ed25519(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	signed := "key/" + base64.RawURLEncoding.EncodeToString([]byte(`{"user":"dappnode"}`))
+	key := signed + "." + base64.RawURLEncoding.EncodeToString(ed25519.Sign(priv, []byte(signed)))
+
+	v, err := NewKeyVerifier(SchemeEd25519Sign, hex.EncodeToString(pub))
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, err := v.VerifySignedKey(key)
+	if err != nil || string(payload) != `{"user":"dappnode"}` {
+		t.Fatalf("VerifySignedKey = %q, %v", payload, err)
+	}
+	if _, err := v.VerifySignedKey(key[:len(key)-4] + "AAAA"); !errors.Is(err, ErrInvalidSignedKey) {
+		t.Fatalf("tampered key: got %v, want ErrInvalidSignedKey", err)
+	}
+}
+
+func TestVerifySignedKey_RSA(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, _ := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	pubPEM := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	b64 := base64.RawURLEncoding.EncodeToString
+	payload := `{"entitlements":["PRO"]}`
+
+	signed := "key/" + b64([]byte(payload))
+	digest := sha256.Sum256([]byte(signed))
+	pssSig, _ := rsa.SignPSS(rand.Reader, priv, crypto.SHA256, digest[:], nil)
+
+	jwtSigned := b64([]byte(`{"alg":"RS256","typ":"JWT"}`)) + "." + b64([]byte(payload))
+	jwtDigest := sha256.Sum256([]byte(jwtSigned))
+	jwtSig, _ := rsa.SignPKCS1v15(rand.Reader, priv, crypto.SHA256, jwtDigest[:])
+
+	// Private "encrypt": PKCS#1 v1.5 type 1 padding, then m^d mod n.
+	em := make([]byte, priv.Size())
+	em[1] = 1
+	for i := 2; i < len(em)-len(payload)-1; i++ {
+		em[i] = 0xff
+	}
+	copy(em[len(em)-len(payload):], payload)
+	m := new(big.Int).SetBytes(em)
+	encrypted := b64(m.Exp(m, priv.D, priv.N).FillBytes(make([]byte, priv.Size())))
+
+	for _, tc := range []struct{ scheme, key string }{
+		{SchemeRSA2048PSSSignV2, signed + "." + b64(pssSig)},
+		{SchemeRSA2048JWTRS256, jwtSigned + "." + b64(jwtSig)},
+		{SchemeRSA2048PKCS1Encrypt, encrypted},
+	} {
+		v, err := NewKeyVerifier(tc.scheme, pubPEM)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.scheme, err)
+		}
+		got, err := v.VerifySignedKey(tc.key)
+		if err != nil || string(got) != payload {
+			t.Errorf("%s: VerifySignedKey = %q, %v", tc.scheme, got, err)
+		}
+	}
+
+	v, _ := NewKeyVerifier(SchemeRSA2048PKCS1SignV2, pubPEM)
+	if _, err := v.VerifySignedKey(signed + "." + b64(pssSig)); !errors.Is(err, ErrInvalidSignedKey) {
+		t.Errorf("PSS signature under PKCS1 scheme: got %v, want ErrInvalidSignedKey", err)
+	}
+}
@@ -0,0 +1,37 @@
+package keygen
+
+import (
+	"context"
+	"time"
+)
+
+// defaultTimeout bounds each API request whose context has no deadline.
+const defaultTimeout = 30 * time.Second
+
+// WithTimeout sets the per-request timeout applied when the caller's context
+// has no deadline (default: 30s; 0 disables it). Paginated calls apply it to
+// each page.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) { c.timeout = d }
+}
+
+type timeoutKey struct{}
+
+// ContextWithRequestTimeout overrides the client's timeout for the requests
+// made with ctx, e.g. to allow a slow bulk export or to fail fast on a boot
+// check. It applies even when ctx already has a later deadline.
+func ContextWithRequestTimeout(ctx context.Context, d time.Duration) context.Context {
+	return context.WithValue(ctx, timeoutKey{}, d)
+}
+
+// requestContext derives the context for one request from the caller's,
+// applying the per-call or client timeout.
+func (c *Client) requestContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if d, ok := ctx.Value(timeoutKey{}).(time.Duration); ok && d > 0 {
+		return context.WithTimeout(ctx, d)
+	}
+	if _, ok := ctx.Deadline(); !ok && c.timeout > 0 {
+		return context.WithTimeout(ctx, c.timeout)
+	}
+	return ctx, func() {}
+}
@@ -0,0 +1,38 @@
+package keygen
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(200 * time.Millisecond):
+		case <-r.Context().Done():
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"id":"lic-1"}}`))
+	}))
+	defer srv.Close()
+
+	c := New("acct", "token", WithBaseURL(srv.URL), WithTimeout(20*time.Millisecond))
+	if _, err := c.GetLicense(context.Background(), "lic-1"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("client timeout: got %v, want DeadlineExceeded", err)
+	}
+
+	ctx := ContextWithRequestTimeout(context.Background(), 5*time.Second)
+	if _, err := c.GetLicense(ctx, "lic-1"); err != nil {
+		t.Fatalf("per-call override: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := c.GetLicense(ctx, "lic-1"); err != nil {
+		t.Fatalf("caller deadline should win over the client timeout: %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package keygen
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrInsecureTLS is returned by every call on a client created with
+// WithStrictTLS whose transport is configured below the policy.
+var ErrInsecureTLS = errors.New("keygen: transport does not satisfy the TLS policy")
+
+// modernCipherSuites are the TLS 1.2 suites allowed under WithStrictTLS
+// (ECDHE key exchange with AEAD ciphers). TLS 1.3 suites are not configurable
+// and always acceptable.
+var modernCipherSuites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
+}
+
+// WithStrictTLS enforces TLS 1.2+ and modern AEAD cipher suites on the
+// built-in transport. If the configured transport explicitly allows weaker
+// settings (older versions, other suites, InsecureSkipVerify) or cannot be
+// inspected, the client refuses to send any request and returns ErrInsecureTLS.
+func WithStrictTLS() Option {
+	return func(c *Client) { c.strictTLS = true }
+}
+
+// applyTLSPolicy hardens (a private copy of) the transport or reports why it
+// cannot be hardened.
+func (c *Client) applyTLSPolicy() error {
+	rt := c.http.Transport
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	if _, ok := rt.(interface{ browserManagedTLS() }); ok {
+		return nil // TLS is negotiated by the browser's fetch API
+	}
+
+	t, ok := rt.(*http.Transport)
+	if !ok {
+		return fmt.Errorf("%w: cannot inspect transport %T", ErrInsecureTLS, rt)
+	}
+	if err := checkTLSConfig(t.TLSClientConfig); err != nil {
+		return err
+	}
+
+	// Never mutate shared transports/clients: work on copies.
+	t = t.Clone()
+	if t.TLSClientConfig == nil {
+		t.TLSClientConfig = &tls.Config{}
+	}
+	if t.TLSClientConfig.MinVersion == 0 {
+		t.TLSClientConfig.MinVersion = tls.VersionTLS12
+	}
+	if len(t.TLSClientConfig.CipherSuites) == 0 {
+		t.TLSClientConfig.CipherSuites = modernCipherSuites
+	}
+	h := *c.http
+	h.Transport = t
+	c.http = &h
+	return nil
+}
+
+func checkTLSConfig(cfg *tls.Config) error {
+	if cfg == nil {
+		return nil
+	}
+	if cfg.InsecureSkipVerify {
+		return fmt.Errorf("%w: InsecureSkipVerify is set", ErrInsecureTLS)
+	}
+	if cfg.MinVersion != 0 && cfg.MinVersion < tls.VersionTLS12 {
+		return fmt.Errorf("%w: minimum version %s", ErrInsecureTLS, tls.VersionName(cfg.MinVersion))
+	}
+	if cfg.MaxVersion != 0 && cfg.MaxVersion < tls.VersionTLS12 {
+		return fmt.Errorf("%w: maximum version %s", ErrInsecureTLS, tls.VersionName(cfg.MaxVersion))
+	}
+	for _, cs := range cfg.CipherSuites {
+		if !isModernCipherSuite(cs) {
+			return fmt.Errorf("%w: cipher suite %s", ErrInsecureTLS, tls.CipherSuiteName(cs))
+		}
+	}
+	return nil
+}
+
+func isModernCipherSuite(id uint16) bool {
+	for _, m := range modernCipherSuites {
+		if m == id {
+			return true
+		}
+	}
+	return false
+}
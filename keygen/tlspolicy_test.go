@@ -0,0 +1,29 @@
+package keygen
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestWithStrictTLS(t *testing.T) {
+	c := New("acct", "token", WithStrictTLS())
+	if c.initErr != nil {
+		t.Fatalf("default transport: unexpected error %v", c.initErr)
+	}
+	cfg := c.http.Transport.(*http.Transport).TLSClientConfig
+	if cfg.MinVersion != tls.VersionTLS12 || len(cfg.CipherSuites) == 0 {
+		t.Fatalf("default transport not hardened: %+v", cfg)
+	}
+	if def := http.DefaultTransport.(*http.Transport).TLSClientConfig; def != nil && len(def.CipherSuites) > 0 {
+		t.Fatalf("shared http.DefaultTransport was mutated")
+	}
+
+	weak := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{MinVersion: tls.VersionTLS10}}}
+	c = New("acct", "token", WithHTTPClient(weak), WithStrictTLS())
+	if err := c.DeleteLicense(context.Background(), "lic"); !errors.Is(err, ErrInsecureTLS) {
+		t.Fatalf("weak transport: expected ErrInsecureTLS, got %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+package keygen
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// CreateProductToken mints a product token, which can manage every policy,
+// license and machine of the product.
+func (c *Client) CreateProductToken(ctx context.Context, productID string, opts TokenOptions) (Token, error) {
+	return c.createToken(ctx, "/products/"+productID+"/tokens", opts)
+}
+
+// CreateUserToken mints a token for a user. For admin users this is an admin
+// token with full account access.
+func (c *Client) CreateUserToken(ctx context.Context, userID string, opts TokenOptions) (Token, error) {
+	return c.createToken(ctx, "/users/"+userID+"/tokens", opts)
+}
+
+// GetToken returns a token by ID. The secret is not included.
+func (c *Client) GetToken(ctx context.Context, tokenID string) (Token, error) {
+	path := fmt.Sprintf("/accounts/%s/tokens/%s", c.accountID, tokenID)
+
+	var resp tokenResponse
+	if err := c.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return Token{}, err
+	}
+	return resp.Data.toToken(), nil
+}
+
+// ListTokens lists the tokens visible to the client's token.
+func (c *Client) ListTokens(ctx context.Context) ([]Token, error) {
+	return listAll(ctx, c, "/tokens", nil, tokenResource.toToken)
+}
+
+// RegenerateToken rotates a token's secret, invalidating the old one, and
+// returns the token with its new secret.
+func (c *Client) RegenerateToken(ctx context.Context, tokenID string) (Token, error) {
+	path := fmt.Sprintf("/accounts/%s/tokens/%s", c.accountID, tokenID)
+
+	var resp tokenResponse
+	if err := c.do(ctx, http.MethodPut, path, nil, &resp); err != nil {
+		return Token{}, err
+	}
+	return resp.Data.toToken(), nil
+}
+
+// RevokeToken permanently revokes a token.
+func (c *Client) RevokeToken(ctx context.Context, tokenID string) error {
+	path := fmt.Sprintf("/accounts/%s/tokens/%s", c.accountID, tokenID)
+	return c.do(ctx, http.MethodDelete, path, nil, nil)
+}
+
+// createToken POSTs a token creation request to rel (relative to the
+// account), e.g. /licenses/{id}/tokens.
+func (c *Client) createToken(ctx context.Context, rel string, opts TokenOptions) (Token, error) {
+	var req tokenCreateRequest
+	req.Data.Type = "tokens"
+	req.Data.Attributes.Name = opts.Name
+	if !opts.Expiry.IsZero() {
+		s := opts.Expiry.UTC().Format(time.RFC3339)
+		req.Data.Attributes.Expiry = &s
+	}
+	if opts.MaxActivations > 0 {
+		req.Data.Attributes.MaxActivations = &opts.MaxActivations
+	}
+	if opts.MaxDeactivations > 0 {
+		req.Data.Attributes.MaxDeactivations = &opts.MaxDeactivations
+	}
+
+	var resp tokenResponse
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("/accounts/%s%s", c.accountID, rel), req, &resp); err != nil {
+		return Token{}, err
+	}
+	return resp.Data.toToken(), nil
+}
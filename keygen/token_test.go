@@ -0,0 +1,52 @@
+package keygen
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestTokens(t *testing.T) {
+	const (
+		productToken = `{"id":"t1","attributes":{"kind":"product-token","token":"prod-secret"},
+			"relationships":{"bearer":{"data":{"type":"products","id":"prod-1"}}}}`
+		userToken = `{"id":"t2","attributes":{"kind":"user-token","name":"cli","token":"user-secret"},
+			"relationships":{"bearer":{"data":{"type":"users","id":"u1"}}}}`
+	)
+	api := newFakeAPI(t, map[string]http.HandlerFunc{
+		"POST /products/prod-1/tokens": jsonData(productToken),
+		"POST /users/u1/tokens":        jsonData(userToken),
+		"GET /tokens/t2":               jsonData(userToken),
+		"GET /tokens":                  jsonList(productToken, userToken),
+		"PUT /tokens/t1":               jsonData(`{"id":"t1","attributes":{"kind":"product-token","token":"rotated"}}`),
+		"DELETE /tokens/t1":            noContent,
+	})
+
+	ctx := context.Background()
+	c := api.client()
+	tok, err := c.CreateProductToken(ctx, "prod-1", TokenOptions{Name: "ci"})
+	if err != nil || tok.Token != "prod-secret" || tok.BearerType != "products" || tok.BearerID != "prod-1" {
+		t.Fatalf("CreateProductToken = %+v, %v", tok, err)
+	}
+	if a := api.attributes("POST /products/prod-1/tokens"); a["name"] != "ci" {
+		t.Fatalf("create attributes = %v", a)
+	}
+	if tok, err := c.CreateUserToken(ctx, "u1", TokenOptions{Name: "cli"}); err != nil || tok.BearerType != "users" || tok.BearerID != "u1" {
+		t.Fatalf("CreateUserToken = %+v, %v", tok, err)
+	}
+
+	if tok, err := c.GetToken(ctx, "t2"); err != nil || tok.Kind != "user-token" || tok.Name != "cli" {
+		t.Fatalf("GetToken = %+v, %v", tok, err)
+	}
+	list, err := c.ListTokens(ctx)
+	if err != nil || len(list) != 2 || list[0].ID != "t1" || list[1].BearerID != "u1" {
+		t.Fatalf("ListTokens = %+v, %v", list, err)
+	}
+
+	if tok, err := c.RegenerateToken(ctx, "t1"); err != nil || tok.Token != "rotated" {
+		t.Fatalf("RegenerateToken = %+v, %v", tok, err)
+	}
+	if err := c.RevokeToken(ctx, "t1"); err != nil {
+		t.Fatalf("RevokeToken: %v", err)
+	}
+}
@@ -0,0 +1,28 @@
+//go:build js && wasm
+
+package keygen
+
+import "net/http"
+
+// defaultHTTPClient returns a client backed by the browser's fetch API.
+// Requests are sent in CORS mode without ambient browser credentials, since
+// authentication is carried explicitly in the Authorization header.
+func defaultHTTPClient() *http.Client {
+	return &http.Client{Transport: fetchTransport{base: http.DefaultTransport}}
+}
+
+// fetchTransport sets the js.fetch:* pseudo-headers understood by the wasm
+// net/http implementation.
+type fetchTransport struct {
+	base http.RoundTripper
+}
+
+func (t fetchTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r := req.Clone(req.Context())
+	r.Header.Set("js.fetch:mode", "cors")
+	r.Header.Set("js.fetch:credentials", "omit")
+	return t.base.RoundTrip(r)
+}
+
+// browserManagedTLS tells WithStrictTLS that the browser owns the TLS policy.
+func (fetchTransport) browserManagedTLS() {}
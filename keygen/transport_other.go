@@ -0,0 +1,39 @@
+//go:build !(js && wasm)
+
+package keygen
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"time"
+)
+
+// defaultHTTPClient returns the client used when WithHTTPClient is not set.
+// It owns its transport so that tweaks other libraries make to
+// http.DefaultTransport do not leak into licensing calls.
+func defaultHTTPClient() *http.Client {
+	return &http.Client{Transport: newDefaultTransport()}
+}
+
+// newDefaultTransport mirrors http.DefaultTransport with tighter limits
+// suited to a single API host.
+func newDefaultTransport() *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	return &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          16,
+		MaxIdleConnsPerHost:   8,
+		MaxConnsPerHost:       32,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 30 * time.Second,
+		ExpectContinueTimeout: time.Second,
+		TLSClientConfig:       &tls.Config{MinVersion: tls.VersionTLS12},
+	}
+}
@@ -0,0 +1,46 @@
+package keygen
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestDefaultHTTPClient(t *testing.T) {
+	c := New("acct", "token")
+	if c.http == http.DefaultClient || c.http.Transport == http.DefaultTransport {
+		t.Fatalf("client shares the global default client or transport")
+	}
+	if New("acct", "token").http.Transport == c.http.Transport {
+		t.Fatalf("clients share a transport")
+	}
+}
+
+func TestWithTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	var calls int
+	rt := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		calls++
+		return http.DefaultTransport.RoundTrip(r)
+	})
+	own := &http.Client{}
+	c := New("acct", "token", WithBaseURL(srv.URL), WithTransport(rt), WithHTTPClient(own))
+	if err := c.DeleteLicense(context.Background(), "lic"); err != nil {
+		t.Fatalf("DeleteLicense error: %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("custom transport used %d times, want 1", calls)
+	}
+	if own.Transport != nil {
+		t.Fatalf("caller's http.Client was mutated")
+	}
+}
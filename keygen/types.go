@@ -1,9 +1,90 @@
 package keygen
 
-// LicenseMetadata mirrors the structured metadata you already use.
+import "time"
+
+// LicenseMetadata mirrors the structured metadata you already use. Empty
+// fields are omitted, so a schema's "required" rejects them.
 type LicenseMetadata struct {
-	SubscriptionID string `json:"subscriptionId"`
-	CustomerEmail  string `json:"customerEmail"`
+	SubscriptionID string `json:"subscriptionId,omitempty"`
+	CustomerEmail  string `json:"customerEmail,omitempty"`
+}
+
+// License is the full license view returned by GetLicense.
+type License struct {
+	ID          string         `json:"id"`
+	Name        string         `json:"name,omitempty"`
+	Key         string         `json:"key"`
+	Expiry      *time.Time     `json:"expiry,omitempty"` // nil => never expires
+	Status      string         `json:"status"`
+	Permissions []string       `json:"permissions,omitempty"` // empty => policy defaults
+	Metadata    map[string]any `json:"metadata,omitempty"`
+	PolicyID    string         `json:"policyId"`
+	Created     time.Time      `json:"created"`
+	Updated     time.Time      `json:"updated"`
+	LicenseUsage
+}
+
+// LicenseUsage holds a license's limits and current usage, e.g. to render
+// "2 of 3 machines used". Nil limits mean unlimited.
+type LicenseUsage struct {
+	Uses          int  `json:"uses"`
+	MaxUses       *int `json:"maxUses,omitempty"`
+	MaxMachines   *int `json:"maxMachines,omitempty"`
+	MaxCores      *int `json:"maxCores,omitempty"`
+	MachinesCount int  `json:"machinesCount"`
+}
+
+// LicenseUpdate lists the attributes changed by UpdateLicense. Nil fields
+// are left untouched.
+type LicenseUpdate struct {
+	Name        *string
+	Expiry      *time.Time
+	Protected   *bool
+	MaxMachines *int
+	MaxUses     *int
+	MaxCores    *int
+	Permissions []string       // replaces the permissions when non-nil; []string{} clears them
+	Metadata    map[string]any // replaces the whole object when non-nil
+}
+
+// CreatedLicense is returned by CreateLicenseFull.
+type CreatedLicense struct {
+	ID     string     `json:"id"`
+	Key    string     `json:"key"`
+	Expiry *time.Time `json:"expiry,omitempty"`
+	Status string     `json:"status"`
+}
+
+// LicenseFilter selects licenses server-side in ListLicenses. Zero-valued
+// fields are not sent.
+type LicenseFilter struct {
+	Policy  string
+	Product string
+	User    string
+	Group   string
+	// Status is one of ACTIVE, INACTIVE, EXPIRING, EXPIRED, SUSPENDED, BANNED.
+	Status string
+	// Metadata matches metadata[key]=value (all pairs must match).
+	Metadata map[string]string
+	// ExpiresWithin matches licenses expiring within the given duration.
+	ExpiresWithin time.Duration
+	// ExpiresBefore/ExpiresAfter bound the expiry date.
+	ExpiresBefore time.Time
+	ExpiresAfter  time.Time
+}
+
+// MachineFilter selects machines server-side. Zero-valued fields are not sent.
+type MachineFilter struct {
+	License     string // license ID
+	Policy      string
+	Product     string
+	User        string
+	Fingerprint string
+	Platform    string
+	// HeartbeatStatus is one of NOT_STARTED, ALIVE, DEAD, RESURRECTED.
+	HeartbeatStatus string
+	// Metadata matches metadata[key]=value (all pairs must match).
+	Metadata map[string]string
 }
 
 // LicenseSummary is a normalized view for listing by policy.
@@ -12,25 +93,536 @@ type LicenseSummary struct {
 	Key      string         `json:"key,omitempty"`    // may be empty depending on API shape
 	Status   string         `json:"status,omitempty"` // may be empty depending on API shape
 	Metadata map[string]any `json:"metadata,omitempty"`
+	LicenseUsage
 }
 
 // Machine is a simplified machine representation.
 type Machine struct {
-	ID          string `json:"id"`
-	LicenseId   string `json:"licenseId"`
-	Fingerprint string `json:"fingerprint"`
-	Platform    string `json:"platform"`
-	Name        string `json:"name"`
+	ID          string         `json:"id"`
+	LicenseId   string         `json:"licenseId"`
+	OwnerID     string         `json:"ownerId,omitempty"`
+	Fingerprint string         `json:"fingerprint"`
+	Platform    string         `json:"platform"`
+	Name        string         `json:"name"`
+	Hostname    string         `json:"hostname,omitempty"`
+	IP          string         `json:"ip,omitempty"`
+	Cores       int            `json:"cores,omitempty"`
+	Metadata    map[string]any `json:"metadata,omitempty"`
+	Created     time.Time      `json:"created"`
+
+	RequireHeartbeat bool       `json:"requireHeartbeat"`
+	HeartbeatStatus  string     `json:"heartbeatStatus,omitempty"` // e.g. ALIVE, DEAD, NOT_STARTED
+	LastHeartbeat    *time.Time `json:"lastHeartbeat,omitempty"`   // nil => never pinged
+}
+
+// MachineUpdate lists the attributes changed by UpdateMachine. Nil fields
+// are left untouched.
+type MachineUpdate struct {
+	Name     *string
+	Platform *string
+	Hostname *string
+	IP       *string
+	Cores    *int
+	Metadata map[string]any // replaces the whole object when non-nil
+}
+
+// EnsureMachineOptions configures EnsureMachineActivated.
+type EnsureMachineOptions struct {
+	Name     string // defaults to the client default
+	Platform string // defaults to the client default
+	Options  []MachineOption
+	// RotateOldest deactivates the license's oldest machine and retries when
+	// activation fails with MACHINE_LIMIT_EXCEEDED.
+	RotateOldest bool
+}
+
+// EnsureMachineResult reports what EnsureMachineActivated did.
+type EnsureMachineResult struct {
+	Machine   Machine
+	Activated bool     // false when the fingerprint was already activated
+	Rotated   *Machine // machine deactivated to make room, if any
+}
+
+// ValidateOrActivateResult reports what ValidateOrActivate did.
+type ValidateOrActivateResult struct {
+	Validation LicenseValidation // final validation
+	Activated  bool              // a machine was activated before re-validating
+	Machine    *Machine          // machine activated, if any
+	Rotated    *Machine          // machine deactivated to make room, if any
+}
+
+// CheckoutOptions configures CheckoutLicense and CheckoutMachine. Zero values
+// are not sent.
+type CheckoutOptions struct {
+	TTL     time.Duration // 0 => Keygen default (one month)
+	Include []string      // e.g. "entitlements" for licenses, "license" for machines
+	Encrypt bool          // encrypt the payload with the license key
+}
+
+// MachineCheckoutOptions configures CheckoutMachine.
+type MachineCheckoutOptions = CheckoutOptions
+
+// MachineFile is a signed machine file certificate, an offline proof that a
+// machine is activated. Certificate is the PEM-like blob to store on the
+// device; its signature is verified with the account's public key.
+type MachineFile struct {
+	ID          string     `json:"id"`
+	Certificate string     `json:"certificate"`
+	TTL         int        `json:"ttl"` // seconds
+	Expiry      *time.Time `json:"expiry,omitempty"`
+	Issued      time.Time  `json:"issued"`
+	Encrypted   bool       `json:"encrypted"`
+	Algorithm   string     `json:"algorithm,omitempty"`
+}
+
+// LicenseFile is a signed (and optionally encrypted) license file
+// certificate, used to validate a license on air-gapped devices. Verify and
+// decode Certificate with the licensefile subpackage.
+type LicenseFile struct {
+	ID          string     `json:"id"`
+	Certificate string     `json:"certificate"`
+	TTL         int        `json:"ttl"` // seconds
+	Expiry      *time.Time `json:"expiry,omitempty"`
+	Issued      time.Time  `json:"issued"`
+	Encrypted   bool       `json:"encrypted"`
+	Algorithm   string     `json:"algorithm,omitempty"`
+}
+
+// Process is a running instance of the application on a machine, tracked
+// so policies can limit concurrent processes per machine.
+type Process struct {
+	ID            string         `json:"id"`
+	PID           string         `json:"pid"`
+	Status        string         `json:"status"` // e.g. ALIVE, DEAD, RESURRECTED
+	MachineID     string         `json:"machineId"`
+	Interval      int            `json:"interval,omitempty"` // heartbeat interval in seconds
+	LastHeartbeat *time.Time     `json:"lastHeartbeat,omitempty"`
+	Metadata      map[string]any `json:"metadata,omitempty"`
+	Created       time.Time      `json:"created"`
+}
+
+// Component is a hardware component (GPU, motherboard, disk...) registered
+// on a machine, usable for component-scoped validation.
+type Component struct {
+	ID          string         `json:"id"`
+	Fingerprint string         `json:"fingerprint"`
+	Name        string         `json:"name"`
+	MachineID   string         `json:"machineId"`
+	Metadata    map[string]any `json:"metadata,omitempty"`
+	Created     time.Time      `json:"created"`
+}
+
+// ComponentUpdate lists the attributes changed by UpdateComponent. Nil
+// fields are left untouched.
+type ComponentUpdate struct {
+	Name     *string
+	Metadata map[string]any // replaces the whole object when non-nil
+}
+
+// Product is a Keygen product, the top-level grouping of policies and
+// releases (e.g. one per dappnode offering).
+type Product struct {
+	ID                   string         `json:"id"`
+	Name                 string         `json:"name"`
+	Code                 string         `json:"code,omitempty"`
+	URL                  string         `json:"url,omitempty"`
+	DistributionStrategy string         `json:"distributionStrategy,omitempty"` // LICENSED, OPEN or CLOSED
+	Platforms            []string       `json:"platforms,omitempty"`
+	Permissions          []string       `json:"permissions,omitempty"`
+	Metadata             map[string]any `json:"metadata,omitempty"`
+	Created              time.Time      `json:"created"`
+	Updated              time.Time      `json:"updated"`
+}
+
+// ProductUpdate lists the attributes changed by UpdateProduct. Nil fields
+// are left untouched.
+type ProductUpdate struct {
+	Name                 *string
+	Code                 *string
+	URL                  *string
+	DistributionStrategy *string
+	Platforms            []string       // replaces the list when non-nil; []string{} clears it
+	Permissions          []string       // replaces the list when non-nil; []string{} clears it
+	Metadata             map[string]any // replaces the whole object when non-nil
+}
+
+// Policy is a Keygen policy: the rules (duration, limits, heartbeat...)
+// applied to the licenses created under it. When creating a policy,
+// zero-valued fields are not sent and Keygen's defaults apply.
+type Policy struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	ProductID string `json:"productId"`
+	// Duration is how long licenses last; 0 => they never expire.
+	Duration     time.Duration `json:"duration,omitempty"`
+	Scheme       string        `json:"scheme,omitempty"` // signing scheme, e.g. ED25519_SIGN
+	Floating     bool          `json:"floating"`
+	Strict       bool          `json:"strict"`
+	Protected    bool          `json:"protected"`
+	MaxMachines  *int          `json:"maxMachines,omitempty"` // nil => unlimited
+	MaxUses      *int          `json:"maxUses,omitempty"`
+	MaxCores     *int          `json:"maxCores,omitempty"`
+	MaxProcesses *int          `json:"maxProcesses,omitempty"`
+
+	RequireHeartbeat              bool          `json:"requireHeartbeat"`
+	HeartbeatDuration             time.Duration `json:"heartbeatDuration,omitempty"`
+	HeartbeatCullStrategy         string        `json:"heartbeatCullStrategy,omitempty"`
+	HeartbeatResurrectionStrategy string        `json:"heartbeatResurrectionStrategy,omitempty"`
+	ExpirationStrategy            string        `json:"expirationStrategy,omitempty"`
+	AuthenticationStrategy        string        `json:"authenticationStrategy,omitempty"`
+
+	Metadata map[string]any `json:"metadata,omitempty"`
+	Created  time.Time      `json:"created"`
+	Updated  time.Time      `json:"updated"`
+}
+
+// PolicyUpdate lists the attributes changed by UpdatePolicy. Nil fields are
+// left untouched.
+type PolicyUpdate struct {
+	Name              *string
+	Duration          *time.Duration // 0 => licenses never expire
+	Floating          *bool
+	Strict            *bool
+	Protected         *bool
+	MaxMachines       *int
+	MaxUses           *int
+	MaxCores          *int
+	MaxProcesses      *int
+	RequireHeartbeat  *bool
+	HeartbeatDuration *time.Duration
+	Metadata          map[string]any // replaces the whole object when non-nil
 }
 
 // LicenseValidation unifies the validate-key output
 type LicenseValidation struct {
-	Key         string `json:"key"`
-	Expiry      string `json:"expiry"`
-	Status      string `json:"status"`
-	Valid       bool   `json:"valid"`
-	Code        string `json:"code"`
-	Detail      string `json:"detail"`
-	Timestamp   string `json:"ts"`
-	Fingerprint string `json:"fingerprint"`
+	Key         string         `json:"key"`
+	Expiry      string         `json:"expiry"`
+	Status      string         `json:"status"`
+	Valid       bool           `json:"valid"`
+	Code        ValidationCode `json:"code"`
+	Detail      string         `json:"detail"`
+	Timestamp   string         `json:"ts"`
+	Fingerprint string         `json:"fingerprint"`
+	Nonce       int64          `json:"nonce,omitempty"`     // echoed from ValidationScope.Nonce
+	Signature   string         `json:"signature,omitempty"` // Keygen-Signature header of the response
+	// ExpiresAt and ServerTime are Expiry and Timestamp parsed; ExpiresAt is
+	// nil for licenses that never expire.
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	ServerTime time.Time  `json:"serverTime"`
+	LicenseUsage
+}
+
+// ValidationScope narrows a validation: every non-zero field must match for
+// the license to be valid (e.g. Entitlements requires all listed codes).
+type ValidationScope struct {
+	Fingerprint  string
+	Fingerprints []string // several fingerprints, e.g. for a cluster
+	Product      string   // product ID
+	Policy       string   // policy ID
+	Machine      string   // machine ID
+	Entitlements []string // entitlement codes
+	Checksum     string   // artifact checksum
+	Version      string   // release version
+
+	// Nonce is not a scope: it is sent in the request meta and must be
+	// echoed back by Keygen, tying the (signed) response to this request.
+	Nonce int64
+}
+
+// Entitlement is a feature flag that can be attached to policies and
+// licenses (e.g. to gate individual dappnode packages).
+type Entitlement struct {
+	ID       string         `json:"id"`
+	Name     string         `json:"name"`
+	Code     string         `json:"code"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+}
+
+// User is a Keygen user account.
+type User struct {
+	ID        string         `json:"id"`
+	Email     string         `json:"email"`
+	FirstName string         `json:"firstName,omitempty"`
+	LastName  string         `json:"lastName,omitempty"`
+	Role      string         `json:"role"`
+	Status    string         `json:"status,omitempty"`
+	Metadata  map[string]any `json:"metadata,omitempty"`
+	Created   time.Time      `json:"created"`
+}
+
+// SecondFactor is a user's TOTP second factor. URI (the otpauth:// URI to
+// render as a QR code) is only returned when the factor is created.
+type SecondFactor struct {
+	ID      string    `json:"id"`
+	Enabled bool      `json:"enabled"`
+	URI     string    `json:"uri,omitempty"`
+	Created time.Time `json:"created"`
+}
+
+// UserCreate holds the attributes of a new user. Empty fields are not sent.
+type UserCreate struct {
+	Email     string
+	Password  string // empty => a passwordless user (e.g. managed via SSO)
+	FirstName string
+	LastName  string
+	Role      string // e.g. "user" (default), "admin", "developer"
+	Metadata  map[string]any
+}
+
+// UserUpdate lists the attributes changed by UpdateUser. Nil fields are left
+// untouched.
+type UserUpdate struct {
+	Email     *string
+	Password  *string
+	FirstName *string
+	LastName  *string
+	Role      *string
+	Metadata  map[string]any // replaces the whole object when non-nil
+}
+
+// UserFilter selects users server-side in ListUsers. Zero-valued fields are
+// not sent.
+type UserFilter struct {
+	Status  string   // ACTIVE, INACTIVE or BANNED
+	Roles   []string // any of the given roles
+	Product string
+	Group   string
+	// Metadata matches metadata[key]=value (all pairs must match).
+	Metadata map[string]string
+}
+
+// Group groups users, licenses and machines (e.g. per reseller) under
+// shared limits. Nil limits mean unlimited.
+type Group struct {
+	ID          string         `json:"id"`
+	Name        string         `json:"name"`
+	MaxUsers    *int           `json:"maxUsers,omitempty"`
+	MaxLicenses *int           `json:"maxLicenses,omitempty"`
+	MaxMachines *int           `json:"maxMachines,omitempty"`
+	Metadata    map[string]any `json:"metadata,omitempty"`
+	Created     time.Time      `json:"created"`
+}
+
+// GroupUpdate lists the attributes changed by UpdateGroup. Nil fields are
+// left untouched.
+type GroupUpdate struct {
+	Name        *string
+	MaxUsers    *int
+	MaxLicenses *int
+	MaxMachines *int
+	Metadata    map[string]any // replaces the whole object when non-nil
+}
+
+// Account is the Keygen account the client talks to. Its public keys verify
+// signed license files, machine files and proofs offline.
+type Account struct {
+	ID               string    `json:"id"`
+	Name             string    `json:"name"`
+	Slug             string    `json:"slug"`
+	APIVersion       string    `json:"apiVersion,omitempty"`
+	Ed25519PublicKey string    `json:"ed25519PublicKey,omitempty"` // hex-encoded
+	RSAPublicKey     string    `json:"rsaPublicKey,omitempty"`     // PEM-encoded RSA-2048
+	Created          time.Time `json:"created"`
+}
+
+// Environment is a Keygen environment, e.g. a sandbox for staging devices.
+type Environment struct {
+	ID                string    `json:"id"`
+	Name              string    `json:"name"`
+	Code              string    `json:"code"`
+	IsolationStrategy string    `json:"isolationStrategy"` // ISOLATED or SHARED
+	Created           time.Time `json:"created"`
+}
+
+// WebhookEndpoint is a URL Keygen delivers webhook events to.
+type WebhookEndpoint struct {
+	ID                 string    `json:"id"`
+	URL                string    `json:"url"`
+	Subscriptions      []string  `json:"subscriptions,omitempty"`      // e.g. "license.created"; ["*"] => all
+	SignatureAlgorithm string    `json:"signatureAlgorithm,omitempty"` // e.g. "ed25519", "rsa-pss-sha256"
+	Created            time.Time `json:"created"`
+}
+
+// WebhookEndpointUpdate lists the attributes changed by
+// UpdateWebhookEndpoint. Nil fields are left untouched.
+type WebhookEndpointUpdate struct {
+	URL                *string
+	Subscriptions      []string // replaces the list when non-nil; []string{} clears it
+	SignatureAlgorithm *string
+}
+
+// Token is a Keygen API token. Token (the secret) is only returned when the
+// token is created or regenerated.
+type Token struct {
+	ID               string     `json:"id"`
+	Kind             string     `json:"kind"` // e.g. "activation-token", "admin-token"
+	Name             string     `json:"name,omitempty"`
+	Token            string     `json:"token,omitempty"`
+	Expiry           *time.Time `json:"expiry,omitempty"`
+	MaxActivations   *int       `json:"maxActivations,omitempty"`
+	MaxDeactivations *int       `json:"maxDeactivations,omitempty"`
+	BearerType       string     `json:"bearerType,omitempty"` // e.g. "licenses", "users", "products"
+	BearerID         string     `json:"bearerId,omitempty"`
+	Created          time.Time  `json:"created"`
+}
+
+// TokenOptions configures the token creation calls. Zero values are not
+// sent. Activation limits only apply to license tokens.
+type TokenOptions struct {
+	Name             string
+	Expiry           time.Time // zero => never expires
+	MaxActivations   int       // 0 => unlimited
+	MaxDeactivations int       // 0 => unlimited
+}
+
+// LicenseTokenOptions configures CreateLicenseToken.
+type LicenseTokenOptions = TokenOptions
+
+// RequestLog is one API request recorded by Keygen. Bodies are only present
+// when request log bodies are retained for the account.
+type RequestLog struct {
+	ID            string    `json:"id"`
+	Method        string    `json:"method"`
+	URL           string    `json:"url"`
+	Status        int       `json:"status"`
+	IP            string    `json:"ip,omitempty"`
+	UserAgent     string    `json:"userAgent,omitempty"`
+	RequestBody   string    `json:"requestBody,omitempty"`
+	ResponseBody  string    `json:"responseBody,omitempty"`
+	RequestorType string    `json:"requestorType,omitempty"`
+	RequestorID   string    `json:"requestorId,omitempty"`
+	ResourceType  string    `json:"resourceType,omitempty"`
+	ResourceID    string    `json:"resourceId,omitempty"`
+	Created       time.Time `json:"created"`
+}
+
+// RequestLogFilter selects request logs server-side. Zero-valued fields are
+// not sent; Start/End are truncated to days.
+type RequestLogFilter struct {
+	Start  time.Time
+	End    time.Time
+	Status int
+	Method string
+	URL    string
+	IP     string
+}
+
+// EventLog is one event (e.g. "license.suspended") from the account's event
+// log, with who triggered it and which resource it affected.
+type EventLog struct {
+	ID            string         `json:"id"`
+	Event         string         `json:"event"`
+	WhodunnitType string         `json:"whodunnitType,omitempty"` // e.g. "users", "products"
+	WhodunnitID   string         `json:"whodunnitId,omitempty"`
+	ResourceType  string         `json:"resourceType,omitempty"`
+	ResourceID    string         `json:"resourceId,omitempty"`
+	RequestLogID  string         `json:"requestLogId,omitempty"`
+	Metadata      map[string]any `json:"metadata,omitempty"`
+	Created       time.Time      `json:"created"`
+}
+
+// EventLogFilter selects event logs server-side. Zero-valued fields are not
+// sent; Start/End are truncated to days.
+type EventLogFilter struct {
+	Start         time.Time
+	End           time.Time
+	Event         string
+	ResourceType  string // e.g. "licenses"
+	ResourceID    string
+	WhodunnitType string
+	WhodunnitID   string
+}
+
+// AnalyticsCounts are the account-wide totals shown on the Keygen dashboard.
+type AnalyticsCounts struct {
+	ActiveLicensedUsers int `json:"activeLicensedUsers"`
+	ActiveLicenses      int `json:"activeLicenses"`
+	TotalLicenses       int `json:"totalLicenses"`
+	TotalMachines       int `json:"totalMachines"`
+	TotalUsers          int `json:"totalUsers"`
+}
+
+// TopEntry is one row of a "top N by volume" analytics report. Key is the
+// license ID, URL or IP address depending on the report; Method is only set
+// for URLs.
+type TopEntry struct {
+	Key    string `json:"key"`
+	Method string `json:"method,omitempty"`
+	Count  int    `json:"count"`
+}
+
+// Release is a versioned release of a product, distributed through Keygen.
+type Release struct {
+	ID          string         `json:"id"`
+	Name        string         `json:"name,omitempty"`
+	Version     string         `json:"version"`          // semver
+	Channel     string         `json:"channel"`          // stable, rc, beta, alpha or dev
+	Status      string         `json:"status,omitempty"` // NOT_PUBLISHED, PUBLISHED or YANKED
+	Tag         string         `json:"tag,omitempty"`    // optional unique alias
+	Description string         `json:"description,omitempty"`
+	ProductID   string         `json:"productId"`
+	PackageID   string         `json:"packageId,omitempty"`
+	Metadata    map[string]any `json:"metadata,omitempty"`
+	Created     time.Time      `json:"created"`
+	Updated     time.Time      `json:"updated"`
+}
+
+// ReleaseUpdate lists the attributes changed by UpdateRelease. Nil fields
+// are left untouched.
+type ReleaseUpdate struct {
+	Name        *string
+	Description *string
+	Tag         *string
+	Metadata    map[string]any // replaces the whole object when non-nil
+}
+
+// ReleaseFilter selects releases server-side in ListReleases. Zero-valued
+// fields are not sent.
+type ReleaseFilter struct {
+	Product  string
+	Package  string
+	Channel  string
+	Status   string
+	Platform string // releases with an artifact for this platform
+	// Entitlements matches releases constrained by all of these entitlement
+	// codes.
+	Entitlements []string
+}
+
+// Artifact is a file attached to a release.
+type Artifact struct {
+	ID        string         `json:"id"`
+	Filename  string         `json:"filename"`
+	Filetype  string         `json:"filetype,omitempty"`
+	Filesize  int64          `json:"filesize,omitempty"`
+	Platform  string         `json:"platform,omitempty"`
+	Arch      string         `json:"arch,omitempty"`
+	Checksum  string         `json:"checksum,omitempty"`  // e.g. base64 SHA-512
+	Signature string         `json:"signature,omitempty"` // e.g. base64 Ed25519 signature
+	Status    string         `json:"status,omitempty"`    // WAITING, UPLOADED, FAILED or YANKED
+	ReleaseID string         `json:"releaseId"`
+	Metadata  map[string]any `json:"metadata,omitempty"`
+	Created   time.Time      `json:"created"`
+}
+
+// Package groups a product's releases (e.g. one per dappnode package).
+type Package struct {
+	ID        string         `json:"id"`
+	Name      string         `json:"name"`
+	Key       string         `json:"key"`
+	Engine    string         `json:"engine,omitempty"` // e.g. "npm", "pypi"; empty => raw
+	ProductID string         `json:"productId"`
+	Metadata  map[string]any `json:"metadata,omitempty"`
+	Created   time.Time      `json:"created"`
+}
+
+// Upgrade is the result of CheckForUpgrade.
+type Upgrade struct {
+	Available bool   `json:"available"`
+	Current   string `json:"current"`        // version passed in
+	Next      string `json:"next,omitempty"` // next version, when Available
+	// Artifact is the next release's artifact and URL a short-lived signed
+	// link to download it.
+	Artifact Artifact `json:"artifact"`
+	URL      string   `json:"url,omitempty"`
 }
@@ -0,0 +1,156 @@
+package keygen
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// CreateUser creates a user, e.g. for a customer self-service portal.
+func (c *Client) CreateUser(ctx context.Context, u UserCreate) (User, error) {
+	var req userRequest
+	req.Data.Type = "users"
+	req.Data.Attributes = userAttributes{
+		Email:     &u.Email,
+		Password:  stringOrNil(u.Password),
+		FirstName: stringOrNil(u.FirstName),
+		LastName:  stringOrNil(u.LastName),
+		Role:      stringOrNil(u.Role),
+	}
+	if u.Metadata != nil {
+		req.Data.Attributes.Metadata = &u.Metadata
+	}
+
+	var resp userResponse
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("/accounts/%s/users", c.accountID), req, &resp); err != nil {
+		return User{}, err
+	}
+	return resp.Data.toUser(), nil
+}
+
+// GetUser returns a user by ID or email.
+func (c *Client) GetUser(ctx context.Context, userID string) (User, error) {
+	var resp userResponse
+	if err := c.do(ctx, http.MethodGet, c.userPath(userID), nil, &resp); err != nil {
+		return User{}, err
+	}
+	return resp.Data.toUser(), nil
+}
+
+// UpdateUser PATCHes the attributes set in u and returns the updated user.
+func (c *Client) UpdateUser(ctx context.Context, userID string, u UserUpdate) (User, error) {
+	var req userRequest
+	req.Data.Type = "users"
+	req.Data.Attributes = userAttributes{
+		Email:     u.Email,
+		Password:  u.Password,
+		FirstName: u.FirstName,
+		LastName:  u.LastName,
+		Role:      u.Role,
+	}
+	if u.Metadata != nil {
+		req.Data.Attributes.Metadata = &u.Metadata
+	}
+
+	var resp userResponse
+	if err := c.do(ctx, http.MethodPatch, c.userPath(userID), req, &resp); err != nil {
+		return User{}, err
+	}
+	return resp.Data.toUser(), nil
+}
+
+// BanUser bans a user, which also stops their licenses from validating.
+func (c *Client) BanUser(ctx context.Context, userID string) error {
+	return c.do(ctx, http.MethodPost, c.userPath(userID)+"/actions/ban", nil, nil)
+}
+
+// UnbanUser lifts a ban placed by BanUser.
+func (c *Client) UnbanUser(ctx context.Context, userID string) error {
+	return c.do(ctx, http.MethodPost, c.userPath(userID)+"/actions/unban", nil, nil)
+}
+
+// DeleteUser deletes a user.
+func (c *Client) DeleteUser(ctx context.Context, userID string) error {
+	return c.do(ctx, http.MethodDelete, c.userPath(userID), nil, nil)
+}
+
+// ListUsers lists the users matching f.
+func (c *Client) ListUsers(ctx context.Context, f UserFilter) ([]User, error) {
+	return listAll(ctx, c, "/users", f.values(), userResource.toUser)
+}
+
+// ListSecondFactors lists a user's second factors.
+func (c *Client) ListSecondFactors(ctx context.Context, userID string) ([]SecondFactor, error) {
+	return listAll(ctx, c, "/users/"+url.PathEscape(userID)+"/second-factors", nil, secondFactorResource.toSecondFactor)
+}
+
+// CreateSecondFactor starts 2FA enrollment for a user and returns the
+// (disabled) factor with its otpauth URI. password is required when the
+// client acts as the user itself and may be empty for admins.
+func (c *Client) CreateSecondFactor(ctx context.Context, userID, password string) (SecondFactor, error) {
+	var req secondFactorRequest
+	req.Meta.Password = password
+
+	var resp secondFactorResponse
+	if err := c.do(ctx, http.MethodPost, c.userPath(userID)+"/second-factors", req, &resp); err != nil {
+		return SecondFactor{}, err
+	}
+	return resp.Data.toSecondFactor(), nil
+}
+
+// EnableSecondFactor verifies otp against a factor created by
+// CreateSecondFactor and enables it.
+func (c *Client) EnableSecondFactor(ctx context.Context, userID, secondFactorID, otp string) (SecondFactor, error) {
+	var req secondFactorRequest
+	req.Data = &secondFactorData{Type: "second-factors"}
+	req.Data.Attributes.Enabled = true
+	req.Meta.OTP = otp
+
+	var resp secondFactorResponse
+	if err := c.do(ctx, http.MethodPatch, c.userPath(userID)+"/second-factors/"+secondFactorID, req, &resp); err != nil {
+		return SecondFactor{}, err
+	}
+	return resp.Data.toSecondFactor(), nil
+}
+
+// DeleteSecondFactor removes a user's second factor, e.g. to recover from a
+// lost authenticator. otp may be empty when the client is an admin.
+func (c *Client) DeleteSecondFactor(ctx context.Context, userID, secondFactorID, otp string) error {
+	var in any
+	if otp != "" {
+		var req secondFactorRequest
+		req.Meta.OTP = otp
+		in = req
+	}
+	return c.do(ctx, http.MethodDelete, c.userPath(userID)+"/second-factors/"+secondFactorID, in, nil)
+}
+
+func (c *Client) userPath(userID string) string {
+	return fmt.Sprintf("/accounts/%s/users/%s", c.accountID, url.PathEscape(userID))
+}
+
+func stringOrNil(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// nonEmptyList returns nil for an empty s so create requests leave the list
+// to Keygen's default.
+func nonEmptyList(s []string) *[]string {
+	if len(s) == 0 {
+		return nil
+	}
+	return &s
+}
+
+// listOrNil returns nil only for a nil s, so an update with an empty,
+// non-nil slice sends [] and clears the list.
+func listOrNil(s []string) *[]string {
+	if s == nil {
+		return nil
+	}
+	return &s
+}
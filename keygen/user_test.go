@@ -0,0 +1,83 @@
+package keygen
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestUsers(t *testing.T) {
+	const resource = `{"id":"u1","attributes":{"email":"ana@example.com","role":"user","status":"ACTIVE"}}`
+	var banned bool
+	api := newFakeAPI(t, map[string]http.HandlerFunc{
+		"POST /users":      jsonData(resource),
+		"GET /users/u1":    jsonData(resource),
+		"DELETE /users/u1": noContent,
+		"GET /users": func(w http.ResponseWriter, r *http.Request) {
+			if q := r.URL.Query(); q.Get("status") != "ACTIVE" || len(q["roles[]"]) != 2 {
+				t.Errorf("unexpected filter %v", q)
+			}
+			jsonList(resource)(w, r)
+		},
+		"POST /users/u1/actions/ban": func(w http.ResponseWriter, r *http.Request) {
+			banned = true
+			jsonData(resource)(w, r)
+		},
+	})
+
+	ctx := context.Background()
+	c := api.client()
+	u, err := c.CreateUser(ctx, UserCreate{Email: "ana@example.com", Role: "user"})
+	if err != nil || u.ID != "u1" {
+		t.Fatalf("CreateUser = %+v, %v", u, err)
+	}
+	var req userRequest
+	api.decodeBody("POST /users", &req)
+	if a := req.Data.Attributes; a.Email == nil || *a.Email != "ana@example.com" || a.Password != nil || a.Role == nil {
+		t.Fatalf("create attributes = %+v", a)
+	}
+
+	if u, err := c.GetUser(ctx, "u1"); err != nil || u.Email != "ana@example.com" || u.Status != "ACTIVE" {
+		t.Fatalf("GetUser = %+v, %v", u, err)
+	}
+	list, err := c.ListUsers(ctx, UserFilter{Status: "ACTIVE", Roles: []string{"user", "admin"}})
+	if err != nil || len(list) != 1 || list[0].Email != "ana@example.com" {
+		t.Fatalf("ListUsers = %+v, %v", list, err)
+	}
+	if err := c.BanUser(ctx, "u1"); err != nil || !banned {
+		t.Fatalf("BanUser: %v", err)
+	}
+	if err := c.DeleteUser(ctx, "u1"); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+}
+
+func TestSecondFactors(t *testing.T) {
+	api := newFakeAPI(t, map[string]http.HandlerFunc{
+		"POST /users/u1/second-factors":       jsonData(`{"id":"sf1","attributes":{"enabled":false,"uri":"otpauth://totp/x"}}`),
+		"GET /users/u1/second-factors":        jsonList(`{"id":"sf1","attributes":{"enabled":true}}`),
+		"PATCH /users/u1/second-factors/sf1":  jsonData(`{"id":"sf1","attributes":{"enabled":true}}`),
+		"DELETE /users/u1/second-factors/sf1": noContent,
+	})
+
+	ctx := context.Background()
+	c := api.client()
+	sf, err := c.CreateSecondFactor(ctx, "u1", "")
+	if err != nil || sf.URI == "" || sf.Enabled {
+		t.Fatalf("CreateSecondFactor = %+v, %v", sf, err)
+	}
+	if sf, err := c.EnableSecondFactor(ctx, "u1", "sf1", "123456"); err != nil || !sf.Enabled {
+		t.Fatalf("EnableSecondFactor = %+v, %v", sf, err)
+	}
+	var req secondFactorRequest
+	api.decodeBody("PATCH /users/u1/second-factors/sf1", &req)
+	if req.Data == nil || !req.Data.Attributes.Enabled || req.Meta.OTP != "123456" {
+		t.Fatalf("enable body = %+v", req)
+	}
+	if list, err := c.ListSecondFactors(ctx, "u1"); err != nil || len(list) != 1 || !list[0].Enabled {
+		t.Fatalf("ListSecondFactors = %+v, %v", list, err)
+	}
+	if err := c.DeleteSecondFactor(ctx, "u1", "sf1", ""); err != nil {
+		t.Fatalf("DeleteSecondFactor: %v", err)
+	}
+}
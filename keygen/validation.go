@@ -0,0 +1,92 @@
+package keygen
+
+import (
+	"strings"
+	"time"
+)
+
+// ValidationCode is the machine-readable outcome of a license validation
+// (LicenseValidation.Code).
+type ValidationCode string
+
+// Validation codes returned by Keygen.
+const (
+	CodeValid    ValidationCode = "VALID"
+	CodeNotFound ValidationCode = "NOT_FOUND"
+	CodeBanned   ValidationCode = "BANNED"
+
+	CodeSuspended ValidationCode = "SUSPENDED"
+	CodeExpired   ValidationCode = "EXPIRED"
+	CodeOverdue   ValidationCode = "OVERDUE" // check-in overdue
+
+	CodeNoMachine           ValidationCode = "NO_MACHINE"  // fingerprint scope, no machine activated
+	CodeNoMachines          ValidationCode = "NO_MACHINES" // fingerprints scope, no machine activated
+	CodeTooManyMachines     ValidationCode = "TOO_MANY_MACHINES"
+	CodeTooFewMachines      ValidationCode = "TOO_FEW_MACHINES"
+	CodeTooManyCores        ValidationCode = "TOO_MANY_CORES"
+	CodeTooFewCores         ValidationCode = "TOO_FEW_CORES"
+	CodeTooManyProcesses    ValidationCode = "TOO_MANY_PROCESSES"
+	CodeTooManyUsers        ValidationCode = "TOO_MANY_USERS"
+	CodeHeartbeatNotStarted ValidationCode = "HEARTBEAT_NOT_STARTED"
+	CodeHeartbeatDead       ValidationCode = "HEARTBEAT_DEAD"
+
+	CodeFingerprintScopeRequired ValidationCode = "FINGERPRINT_SCOPE_REQUIRED"
+	CodeFingerprintScopeMismatch ValidationCode = "FINGERPRINT_SCOPE_MISMATCH"
+	CodeFingerprintScopeEmpty    ValidationCode = "FINGERPRINT_SCOPE_EMPTY"
+	CodeProductScopeRequired     ValidationCode = "PRODUCT_SCOPE_REQUIRED"
+	CodeProductScopeMismatch     ValidationCode = "PRODUCT_SCOPE_MISMATCH"
+	CodePolicyScopeRequired      ValidationCode = "POLICY_SCOPE_REQUIRED"
+	CodePolicyScopeMismatch      ValidationCode = "POLICY_SCOPE_MISMATCH"
+	CodeMachineScopeRequired     ValidationCode = "MACHINE_SCOPE_REQUIRED"
+	CodeMachineScopeMismatch     ValidationCode = "MACHINE_SCOPE_MISMATCH"
+	CodeEntitlementsMissing      ValidationCode = "ENTITLEMENTS_MISSING"
+	CodeEntitlementsScopeEmpty   ValidationCode = "ENTITLEMENTS_SCOPE_EMPTY"
+	CodeChecksumScopeRequired    ValidationCode = "CHECKSUM_SCOPE_REQUIRED"
+	CodeChecksumScopeMismatch    ValidationCode = "CHECKSUM_SCOPE_MISMATCH"
+	CodeVersionScopeRequired     ValidationCode = "VERSION_SCOPE_REQUIRED"
+	CodeVersionScopeMismatch     ValidationCode = "VERSION_SCOPE_MISMATCH"
+)
+
+// IsExpired reports whether the license failed validation because it expired.
+func (v LicenseValidation) IsExpired() bool { return v.Code == CodeExpired }
+
+// IsSuspended reports whether the license is suspended (or its user banned).
+func (v LicenseValidation) IsSuspended() bool {
+	return v.Code == CodeSuspended || v.Code == CodeBanned
+}
+
+// NeedsActivation reports whether the license is fine but this machine is
+// not activated for it yet, i.e. activating and re-validating may succeed.
+func (v LicenseValidation) NeedsActivation() bool {
+	switch v.Code {
+	case CodeNoMachine, CodeNoMachines, CodeFingerprintScopeMismatch:
+		return true
+	}
+	return false
+}
+
+// TooManyMachines reports whether the license's machine limit is exceeded.
+func (v LicenseValidation) TooManyMachines() bool { return v.Code == CodeTooManyMachines }
+
+// IsScopeMismatch reports whether a validation scope (product, policy,
+// entitlements, ...) did not match or was missing.
+func (v LicenseValidation) IsScopeMismatch() bool {
+	c := string(v.Code)
+	return strings.HasSuffix(c, "_SCOPE_MISMATCH") || strings.HasSuffix(c, "_SCOPE_REQUIRED") ||
+		strings.HasSuffix(c, "_SCOPE_EMPTY") || v.Code == CodeEntitlementsMissing
+}
+
+// Expired reports whether the license's expiry is at or before now. Licenses
+// without an expiry never expire.
+func (v LicenseValidation) Expired(now time.Time) bool {
+	return v.ExpiresAt != nil && !now.Before(*v.ExpiresAt)
+}
+
+// ExpiresIn returns the time left until the license expires (negative once
+// expired). ok is false for licenses without an expiry.
+func (v LicenseValidation) ExpiresIn() (d time.Duration, ok bool) {
+	if v.ExpiresAt == nil {
+		return 0, false
+	}
+	return time.Until(*v.ExpiresAt), true
+}
@@ -0,0 +1,87 @@
+package keygen
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestValidationCodePredicates(t *testing.T) {
+	for _, tc := range []struct {
+		code                                        ValidationCode
+		expired, suspended, activation, scopeFailed bool
+	}{
+		{code: CodeValid},
+		{code: CodeExpired, expired: true},
+		{code: CodeBanned, suspended: true},
+		{code: CodeNoMachine, activation: true},
+		{code: CodeFingerprintScopeMismatch, activation: true, scopeFailed: true},
+		{code: CodeEntitlementsMissing, scopeFailed: true},
+		{code: CodeProductScopeRequired, scopeFailed: true},
+	} {
+		v := LicenseValidation{Code: tc.code}
+		if v.IsExpired() != tc.expired || v.IsSuspended() != tc.suspended ||
+			v.NeedsActivation() != tc.activation || v.IsScopeMismatch() != tc.scopeFailed {
+			t.Errorf("%s: IsExpired=%v IsSuspended=%v NeedsActivation=%v IsScopeMismatch=%v", tc.code,
+				v.IsExpired(), v.IsSuspended(), v.NeedsActivation(), v.IsScopeMismatch())
+		}
+	}
+}
+
+func TestValidationExpiry(t *testing.T) {
+	var resp licenseValidationResponse
+	resp.Data.Attributes.Expiry = "2026-11-01T00:00:00.000Z"
+	resp.Meta.Timestamp = "2026-10-01T00:00:00.000Z"
+	v := resp.toValidation()
+	if v.ExpiresAt == nil || v.ServerTime.IsZero() {
+		t.Fatalf("expiry/timestamp not parsed: %+v", v)
+	}
+	if v.Expired(v.ServerTime) || !v.Expired(v.ServerTime.AddDate(0, 2, 0)) {
+		t.Fatalf("Expired mismatch around %v", *v.ExpiresAt)
+	}
+
+	perpetual := LicenseValidation{}
+	if perpetual.Expired(time.Now()) {
+		t.Fatal("license without expiry reported as expired")
+	}
+	if _, ok := perpetual.ExpiresIn(); ok {
+		t.Fatal("ExpiresIn ok for license without expiry")
+	}
+}
+
+func TestValidateOrActivate(t *testing.T) {
+	activated := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "POST /accounts/acct/licenses/actions/validate-key":
+			code, valid := "NO_MACHINE", false
+			if activated {
+				code, valid = "VALID", true
+			}
+			fmt.Fprintf(w, `{"data":{"id":"lic-1"},"meta":{"valid":%t,"code":%q}}`, valid, code)
+		case "GET /accounts/acct/machines":
+			_, _ = w.Write([]byte(`{"data":[],"links":{}}`))
+		case "POST /accounts/acct/machines":
+			activated = true
+			_, _ = w.Write([]byte(`{"data":{"id":"m1","type":"machines","attributes":{"fingerprint":"fp1"}}}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	c := New("acct", "token", WithBaseURL(srv.URL))
+	res, err := c.ValidateOrActivate(context.Background(), "KEY", "fp1", EnsureMachineOptions{})
+	if err != nil || !res.Activated || res.Machine == nil || res.Machine.ID != "m1" || !res.Validation.Valid {
+		t.Fatalf("ValidateOrActivate = %+v, %v", res, err)
+	}
+
+	res, err = c.ValidateOrActivate(context.Background(), "KEY", "fp1", EnsureMachineOptions{})
+	if err != nil || res.Activated || res.Machine != nil || res.Validation.Code != CodeValid {
+		t.Fatalf("already valid: %+v, %v", res, err)
+	}
+}
@@ -0,0 +1,214 @@
+package keygen
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrGraceExpired is returned by ValidateCached when the API is
+	// unreachable and the last successful validation is older than the grace
+	// window (or there is none).
+	ErrGraceExpired = errors.New("keygen: offline grace period expired")
+	// ErrClockTampered is returned by ValidateCached when the system clock is
+	// behind the latest time it has already observed, e.g. after being
+	// rolled back to stretch the grace period.
+	ErrClockTampered = errors.New("keygen: system clock moved backwards")
+)
+
+// clockTolerance absorbs NTP corrections before a clock rollback is treated
+// as tampering.
+const clockTolerance = 5 * time.Minute
+
+// ValidationRecord is the state a ValidatorCache keeps per key and
+// fingerprint.
+type ValidationRecord struct {
+	Validation  LicenseValidation `json:"validation"`
+	ValidatedAt time.Time         `json:"validatedAt"` // server time of the last online validation
+	LastSeen    time.Time         `json:"lastSeen"`    // latest local time observed
+}
+
+// ValidationStore persists the records of a ValidatorCache. Calls are
+// serialized by the cache, but never held across an API request.
+type ValidationStore interface {
+	Load(id string) (ValidationRecord, bool, error)
+	Save(id string, r ValidationRecord) error
+}
+
+// FileValidationStore keeps all records in one JSON file, replaced atomically
+// on save.
+type FileValidationStore struct {
+	Path string
+}
+
+// NewFileValidationStore creates a FileValidationStore writing to path.
+func NewFileValidationStore(path string) *FileValidationStore {
+	return &FileValidationStore{Path: path}
+}
+
+// Load returns the record stored under id; a missing file holds no records.
+func (s *FileValidationStore) Load(id string) (ValidationRecord, bool, error) {
+	all, err := s.load()
+	if err != nil {
+		return ValidationRecord{}, false, err
+	}
+	r, ok := all[id]
+	return r, ok, nil
+}
+
+// Save stores r under id, keeping the other records.
+func (s *FileValidationStore) Save(id string, r ValidationRecord) error {
+	all, err := s.load()
+	if err != nil {
+		return err
+	}
+	all[id] = r
+	b, err := json.Marshal(all)
+	if err != nil {
+		return fmt.Errorf("keygen: encode validation cache: %w", err)
+	}
+	if err := writeFileAtomic(s.Path, b); err != nil {
+		return fmt.Errorf("keygen: write validation cache: %w", err)
+	}
+	return nil
+}
+
+func (s *FileValidationStore) load() (map[string]ValidationRecord, error) {
+	all := map[string]ValidationRecord{}
+	b, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return all, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("keygen: read validation cache: %w", err)
+	}
+	if err := json.Unmarshal(b, &all); err != nil {
+		return nil, fmt.Errorf("keygen: decode validation cache: %w", err)
+	}
+	return all, nil
+}
+
+// memoryValidationStore keeps records in memory; see WithProxyValidationStore.
+type memoryValidationStore struct {
+	records map[string]ValidationRecord
+}
+
+func newMemoryValidationStore() *memoryValidationStore {
+	return &memoryValidationStore{records: map[string]ValidationRecord{}}
+}
+
+func (s *memoryValidationStore) Load(id string) (ValidationRecord, bool, error) {
+	r, ok := s.records[id]
+	return r, ok, nil
+}
+
+func (s *memoryValidationStore) Save(id string, r ValidationRecord) error {
+	s.records[id] = r
+	return nil
+}
+
+// CachedValidation is the result of ValidateCached.
+type CachedValidation struct {
+	LicenseValidation
+	ValidatedAt time.Time // when the result was obtained from the API
+	Offline     bool      // served from the cache because the API was unreachable
+}
+
+// ValidatorCache remembers the last validation of each key and fingerprint
+// so that devices keep working through transient outages.
+type ValidatorCache struct {
+	client *Client
+	store  ValidationStore
+	grace  time.Duration
+	now    func() time.Time
+
+	mu sync.Mutex // guards store; never held across API requests
+}
+
+// NewValidatorCache creates a cache validating through c. A cached valid
+// result is honoured for grace after the last successful online validation.
+func NewValidatorCache(c *Client, store ValidationStore, grace time.Duration) *ValidatorCache {
+	return &ValidatorCache{client: c, store: store, grace: grace, now: time.Now}
+}
+
+// ValidateCached validates licenseKey for fingerprint online and records the
+// result. When the API is unreachable (network error, timeout, 429 or 5xx)
+// it falls back to the last recorded result, provided it is within the
+// grace window and the clock has not been rolled back; otherwise the
+// transient error is returned wrapped in ErrGraceExpired or ErrClockTampered.
+// Definitive answers from the API, valid or not, replace the cached result;
+// API errors such as 404 or 403 discard it.
+func (v *ValidatorCache) ValidateCached(ctx context.Context, licenseKey, fingerprint string) (CachedValidation, error) {
+	// Validate before taking the lock, so a slow or timing-out request does
+	// not stall every other caller; the record is loaded afterwards so that
+	// concurrent validations of the same key are merged, not overwritten.
+	val, verr := v.client.Validate(ctx, licenseKey, fingerprint)
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	id := validationID(licenseKey, fingerprint)
+	rec, found, err := v.store.Load(id)
+	if err != nil {
+		return CachedValidation{}, err
+	}
+	now := v.now()
+	if now.After(rec.LastSeen) {
+		rec.LastSeen = now
+	}
+
+	if verr == nil {
+		// Anchor the grace window to the server's clock when available.
+		rec.Validation = val
+		rec.ValidatedAt = now
+		if !val.ServerTime.IsZero() {
+			rec.ValidatedAt = val.ServerTime
+		}
+		return CachedValidation{LicenseValidation: val, ValidatedAt: rec.ValidatedAt}, v.store.Save(id, rec)
+	}
+	if !isTransient(verr) {
+		// A definitive rejection (e.g. the license was deleted) must not be
+		// served from the cache during a later outage.
+		if found && rec.Validation.Valid {
+			rec.Validation = LicenseValidation{}
+			if err := v.store.Save(id, rec); err != nil {
+				return CachedValidation{}, err
+			}
+		}
+		return CachedValidation{}, verr
+	}
+
+	// Offline: persist LastSeen first so a later rollback is detectable.
+	if err := v.store.Save(id, rec); err != nil {
+		return CachedValidation{}, err
+	}
+	switch {
+	case !found || !rec.Validation.Valid:
+		return CachedValidation{}, fmt.Errorf("%w: no valid cached result: %w", ErrGraceExpired, verr)
+	case now.Before(rec.LastSeen.Add(-clockTolerance)) || now.Before(rec.ValidatedAt.Add(-clockTolerance)):
+		return CachedValidation{}, fmt.Errorf("%w: %w", ErrClockTampered, verr)
+	case now.Sub(rec.ValidatedAt) > v.grace:
+		return CachedValidation{}, fmt.Errorf("%w: last validated %s: %w", ErrGraceExpired, rec.ValidatedAt.Format(time.RFC3339), verr)
+	}
+
+	out := CachedValidation{LicenseValidation: rec.Validation, ValidatedAt: rec.ValidatedAt, Offline: true}
+	if out.Expired(now) {
+		// The license lapsed while offline: report what the API would.
+		out.Valid, out.Status, out.Code = false, "EXPIRED", CodeExpired
+	}
+	return out, nil
+}
+
+// validationID keys records by a hash so license keys are not used as map
+// keys in the store.
+func validationID(licenseKey, fingerprint string) string {
+	sum := sha256.Sum256([]byte(licenseKey + "\x00" + fingerprint))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,91 @@
+package keygen
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestValidateCached(t *testing.T) {
+	online := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !online {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"attributes":{"key":"KEY","status":"ACTIVE"}},` +
+			`"meta":{"valid":true,"code":"VALID","ts":"2026-10-01T12:00:00Z"}}`))
+	}))
+	defer srv.Close()
+
+	start, _ := time.Parse(time.RFC3339, "2026-10-01T12:00:00Z")
+	now := start
+	c := New("acct", "token", WithBaseURL(srv.URL))
+	v := NewValidatorCache(c, NewFileValidationStore(filepath.Join(t.TempDir(), "validations.json")), 72*time.Hour)
+	v.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	online = false
+	if _, err := v.ValidateCached(ctx, "KEY", "fp"); !errors.Is(err, ErrGraceExpired) {
+		t.Fatalf("offline without cache: got %v, want ErrGraceExpired", err)
+	}
+
+	online = true
+	res, err := v.ValidateCached(ctx, "KEY", "fp")
+	if err != nil || !res.Valid || res.Offline || !res.ValidatedAt.Equal(start) {
+		t.Fatalf("online: %+v, %v", res, err)
+	}
+
+	online = false
+	now = start.Add(24 * time.Hour)
+	if res, err = v.ValidateCached(ctx, "KEY", "fp"); err != nil || !res.Valid || !res.Offline {
+		t.Fatalf("offline within grace: %+v, %v", res, err)
+	}
+
+	now = start.Add(time.Hour) // rolled back behind the last observed time
+	if _, err := v.ValidateCached(ctx, "KEY", "fp"); !errors.Is(err, ErrClockTampered) {
+		t.Fatalf("clock rollback: got %v, want ErrClockTampered", err)
+	}
+
+	now = start.Add(73 * time.Hour)
+	if _, err := v.ValidateCached(ctx, "KEY", "fp"); !errors.Is(err, ErrGraceExpired) {
+		t.Fatalf("after grace: got %v, want ErrGraceExpired", err)
+	}
+}
+
+func TestValidateCached_DoesNotSerializeRequests(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req validateLicenseRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req.Meta.Key == "SLOW" {
+			<-release
+		}
+		_, _ = w.Write([]byte(`{"data":{"attributes":{"status":"ACTIVE"}},"meta":{"valid":true,"code":"VALID"}}`))
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	v := NewValidatorCache(New("acct", "token", WithBaseURL(srv.URL)), newMemoryValidationStore(), time.Hour)
+	go func() { _, _ = v.ValidateCached(context.Background(), "SLOW", "fp") }()
+	time.Sleep(20 * time.Millisecond) // let the slow request reach the server
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := v.ValidateCached(context.Background(), "FAST", "fp")
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("ValidateCached: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("ValidateCached blocked behind another key's request")
+	}
+}
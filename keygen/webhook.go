@@ -0,0 +1,69 @@
+//go:build !keygen_minimal
+
+package keygen
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// CreateWebhookEndpoint registers a webhook endpoint. Empty Subscriptions
+// subscribe to every event; an empty SignatureAlgorithm uses the account
+// default.
+func (c *Client) CreateWebhookEndpoint(ctx context.Context, e WebhookEndpoint) (WebhookEndpoint, error) {
+	var req webhookEndpointRequest
+	req.Data.Type = "webhook-endpoints"
+	req.Data.Attributes = webhookEndpointAttributes{
+		URL:                &e.URL,
+		Subscriptions:      nonEmptyList(e.Subscriptions),
+		SignatureAlgorithm: stringOrNil(e.SignatureAlgorithm),
+	}
+
+	var resp webhookEndpointResponse
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("/accounts/%s/webhook-endpoints", c.accountID), req, &resp); err != nil {
+		return WebhookEndpoint{}, err
+	}
+	return resp.Data.toWebhookEndpoint(), nil
+}
+
+// GetWebhookEndpoint returns a webhook endpoint by ID.
+func (c *Client) GetWebhookEndpoint(ctx context.Context, endpointID string) (WebhookEndpoint, error) {
+	path := fmt.Sprintf("/accounts/%s/webhook-endpoints/%s", c.accountID, endpointID)
+
+	var resp webhookEndpointResponse
+	if err := c.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return WebhookEndpoint{}, err
+	}
+	return resp.Data.toWebhookEndpoint(), nil
+}
+
+// UpdateWebhookEndpoint PATCHes the attributes set in u and returns the
+// updated endpoint.
+func (c *Client) UpdateWebhookEndpoint(ctx context.Context, endpointID string, u WebhookEndpointUpdate) (WebhookEndpoint, error) {
+	var req webhookEndpointRequest
+	req.Data.Type = "webhook-endpoints"
+	req.Data.Attributes = webhookEndpointAttributes{
+		URL:                u.URL,
+		Subscriptions:      listOrNil(u.Subscriptions),
+		SignatureAlgorithm: u.SignatureAlgorithm,
+	}
+	path := fmt.Sprintf("/accounts/%s/webhook-endpoints/%s", c.accountID, endpointID)
+
+	var resp webhookEndpointResponse
+	if err := c.do(ctx, http.MethodPatch, path, req, &resp); err != nil {
+		return WebhookEndpoint{}, err
+	}
+	return resp.Data.toWebhookEndpoint(), nil
+}
+
+// DeleteWebhookEndpoint removes a webhook endpoint.
+func (c *Client) DeleteWebhookEndpoint(ctx context.Context, endpointID string) error {
+	path := fmt.Sprintf("/accounts/%s/webhook-endpoints/%s", c.accountID, endpointID)
+	return c.do(ctx, http.MethodDelete, path, nil, nil)
+}
+
+// ListWebhookEndpoints lists the account's webhook endpoints.
+func (c *Client) ListWebhookEndpoints(ctx context.Context) ([]WebhookEndpoint, error) {
+	return listAll(ctx, c, "/webhook-endpoints", nil, webhookEndpointResource.toWebhookEndpoint)
+}
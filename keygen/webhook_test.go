@@ -0,0 +1,45 @@
+//go:build !keygen_minimal
+
+package keygen
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestWebhookEndpoints(t *testing.T) {
+	const resource = `{"id":"we1","attributes":{"url":"https://hooks.example.com/keygen","subscriptions":["license.created"],"signatureAlgorithm":"ed25519"}}`
+	api := newFakeAPI(t, map[string]http.HandlerFunc{
+		"POST /webhook-endpoints":       jsonData(resource),
+		"GET /webhook-endpoints/we1":    jsonData(resource),
+		"GET /webhook-endpoints":        jsonList(resource),
+		"DELETE /webhook-endpoints/we1": noContent,
+	})
+
+	ctx := context.Background()
+	c := api.client()
+	e, err := c.CreateWebhookEndpoint(ctx, WebhookEndpoint{
+		URL:                "https://hooks.example.com/keygen",
+		Subscriptions:      []string{"license.created"},
+		SignatureAlgorithm: "ed25519",
+	})
+	if err != nil || e.ID != "we1" {
+		t.Fatalf("CreateWebhookEndpoint = %+v, %v", e, err)
+	}
+	var req webhookEndpointRequest
+	api.decodeBody("POST /webhook-endpoints", &req)
+	if a := req.Data.Attributes; a.URL == nil || a.Subscriptions == nil || len(*a.Subscriptions) != 1 || a.SignatureAlgorithm == nil || *a.SignatureAlgorithm != "ed25519" {
+		t.Fatalf("create attributes = %+v", a)
+	}
+
+	if e, err := c.GetWebhookEndpoint(ctx, "we1"); err != nil || e.URL != "https://hooks.example.com/keygen" || len(e.Subscriptions) != 1 {
+		t.Fatalf("GetWebhookEndpoint = %+v, %v", e, err)
+	}
+	if list, err := c.ListWebhookEndpoints(ctx); err != nil || len(list) != 1 || list[0].SignatureAlgorithm != "ed25519" {
+		t.Fatalf("ListWebhookEndpoints = %+v, %v", list, err)
+	}
+	if err := c.DeleteWebhookEndpoint(ctx, "we1"); err != nil {
+		t.Fatalf("DeleteWebhookEndpoint: %v", err)
+	}
+}
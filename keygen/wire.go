@@ -1,5 +1,11 @@
 package keygen
 
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
 // -------- license create
 
 type licenseCreateRequest struct {
@@ -13,8 +19,12 @@ type licenseCreateData struct {
 }
 
 type licenseCreateAttributes struct {
-	Expiry   *string         `json:"expiry,omitempty"`
-	Metadata LicenseMetadata `json:"metadata,omitempty"`
+	Key         string   `json:"key,omitempty"`
+	Name        string   `json:"name,omitempty"`
+	Protected   *bool    `json:"protected,omitempty"`
+	Expiry      *string  `json:"expiry,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+	Metadata    any      `json:"metadata,omitempty"` // LicenseMetadata or map[string]any
 }
 
 type licenseCreateRelationships struct {
@@ -30,12 +40,129 @@ type relationshipData struct {
 	ID   string `json:"id"`
 }
 
-type licenseCreateResponse struct {
-	Data struct {
-		Attributes struct {
-			Key string `json:"key"`
-		} `json:"attributes"`
-	} `json:"data"`
+// optionalRelationship is a to-one linkage that can be cleared (data: null).
+type optionalRelationship struct {
+	Data *relationshipData `json:"data"`
+}
+
+// -------- license (single resource)
+
+type licenseResponse struct {
+	Data licenseResource `json:"data"`
+}
+
+// etaggedLicenseResponse keeps the ETag of a license read for a
+// conditional update.
+type etaggedLicenseResponse struct {
+	licenseResponse
+	etag string
+}
+
+func (r *etaggedLicenseResponse) setHeader(h http.Header) {
+	r.etag = h.Get("ETag")
+}
+
+// licenseLimitAttributes are the usage/limit attributes shared by every
+// license payload.
+type licenseLimitAttributes struct {
+	Uses        int  `json:"uses"`
+	MaxMachines *int `json:"maxMachines"`
+	MaxUses     *int `json:"maxUses"`
+	MaxCores    *int `json:"maxCores"`
+}
+
+// machinesCountRelationship carries relationships.machines.meta.count.
+type machinesCountRelationship struct {
+	Meta struct {
+		Count int `json:"count"`
+	} `json:"meta"`
+}
+
+type licenseResource struct {
+	ID         string `json:"id"`
+	Type       string `json:"type"`
+	Attributes struct {
+		licenseLimitAttributes
+		Name        string         `json:"name"`
+		Key         string         `json:"key"`
+		Expiry      *time.Time     `json:"expiry"`
+		Status      string         `json:"status"`
+		Permissions []string       `json:"permissions"`
+		Metadata    map[string]any `json:"metadata"`
+		Created     time.Time      `json:"created"`
+		Updated     time.Time      `json:"updated"`
+	} `json:"attributes"`
+	Relationships struct {
+		Policy struct {
+			Data *relationshipData `json:"data"`
+		} `json:"policy"`
+		Machines machinesCountRelationship `json:"machines"`
+	} `json:"relationships"`
+}
+
+func (r licenseResource) toLicense() License {
+	l := License{
+		ID:           r.ID,
+		Name:         r.Attributes.Name,
+		Key:          r.Attributes.Key,
+		Expiry:       r.Attributes.Expiry,
+		Status:       r.Attributes.Status,
+		Permissions:  r.Attributes.Permissions,
+		Metadata:     r.Attributes.Metadata,
+		Created:      r.Attributes.Created,
+		Updated:      r.Attributes.Updated,
+		LicenseUsage: r.usage(),
+	}
+	if r.Relationships.Policy.Data != nil {
+		l.PolicyID = r.Relationships.Policy.Data.ID
+	}
+	return l
+}
+
+func (r licenseResource) toSummary() LicenseSummary {
+	return LicenseSummary{
+		ID:           r.ID,
+		Key:          r.Attributes.Key,
+		Status:       r.Attributes.Status,
+		Metadata:     r.Attributes.Metadata,
+		LicenseUsage: r.usage(),
+	}
+}
+
+func (r licenseResource) usage() LicenseUsage {
+	return r.Attributes.licenseLimitAttributes.toUsage(r.Relationships.Machines.Meta.Count)
+}
+
+func (a licenseLimitAttributes) toUsage(machinesCount int) LicenseUsage {
+	return LicenseUsage{
+		Uses:          a.Uses,
+		MaxUses:       a.MaxUses,
+		MaxMachines:   a.MaxMachines,
+		MaxCores:      a.MaxCores,
+		MachinesCount: machinesCount,
+	}
+}
+
+// -------- license update
+
+type licenseUpdateRequest struct {
+	Data licenseUpdateData `json:"data"`
+}
+
+type licenseUpdateData struct {
+	Type       string                  `json:"type"`
+	Attributes licenseUpdateAttributes `json:"attributes"`
+}
+
+type licenseUpdateAttributes struct {
+	Name        *string         `json:"name,omitempty"`
+	Expiry      *string         `json:"expiry,omitempty"`
+	Protected   *bool           `json:"protected,omitempty"`
+	MaxMachines *int            `json:"maxMachines,omitempty"`
+	MaxUses     *int            `json:"maxUses,omitempty"`
+	MaxCores    *int            `json:"maxCores,omitempty"`
+	Permissions *[]string       `json:"permissions,omitempty"`
+	Metadata    *map[string]any `json:"metadata,omitempty"`
 }
 
 // -------- get license by subscription
@@ -46,29 +173,327 @@ type getLicenseBySubscriptionResponse struct {
 	} `json:"data"`
 }
 
-// -------- list by policy (rich)
+// -------- license usage
 
-type listLicensesByPolicyResponse struct {
-	Data []struct {
-		ID         string `json:"id"`
+type licenseUsageRequest struct {
+	Meta struct {
+		Increment int `json:"increment,omitempty"`
+		Decrement int `json:"decrement,omitempty"`
+	} `json:"meta"`
+}
+
+// -------- entitlements
+
+type entitlementResource struct {
+	ID         string `json:"id"`
+	Attributes struct {
+		Name     string         `json:"name"`
+		Code     string         `json:"code"`
+		Metadata map[string]any `json:"metadata"`
+	} `json:"attributes"`
+}
+
+type entitlementCreateRequest struct {
+	Data struct {
+		Type       string `json:"type"`
 		Attributes struct {
-			Key      string         `json:"key,omitempty"`
-			Status   string         `json:"status,omitempty"`
+			Name     string         `json:"name"`
+			Code     string         `json:"code"`
 			Metadata map[string]any `json:"metadata,omitempty"`
 		} `json:"attributes"`
 	} `json:"data"`
-	Links struct {
-		Next *string `json:"next"`
-	} `json:"links"`
-	// (Optional) keep if your account returns this:
+}
+
+type entitlementResponse struct {
+	Data entitlementResource `json:"data"`
+}
+
+func (r entitlementResource) toEntitlement() Entitlement {
+	return Entitlement{
+		ID:       r.ID,
+		Name:     r.Attributes.Name,
+		Code:     r.Attributes.Code,
+		Metadata: r.Attributes.Metadata,
+	}
+}
+
+// -------- users
+
+type userResource struct {
+	ID         string `json:"id"`
+	Attributes struct {
+		Email     string         `json:"email"`
+		FirstName string         `json:"firstName"`
+		LastName  string         `json:"lastName"`
+		Role      string         `json:"role"`
+		Status    string         `json:"status"`
+		Metadata  map[string]any `json:"metadata"`
+		Created   time.Time      `json:"created"`
+	} `json:"attributes"`
+}
+
+type userRequest struct {
+	Data struct {
+		Type       string         `json:"type"`
+		Attributes userAttributes `json:"attributes"`
+	} `json:"data"`
+}
+
+// userAttributes is shared by create and update requests.
+type userAttributes struct {
+	Email     *string         `json:"email,omitempty"`
+	Password  *string         `json:"password,omitempty"`
+	FirstName *string         `json:"firstName,omitempty"`
+	LastName  *string         `json:"lastName,omitempty"`
+	Role      *string         `json:"role,omitempty"`
+	Metadata  *map[string]any `json:"metadata,omitempty"`
+}
+
+type userResponse struct {
+	Data userResource `json:"data"`
+}
+
+func (r userResource) toUser() User {
+	return User{
+		ID:        r.ID,
+		Email:     r.Attributes.Email,
+		FirstName: r.Attributes.FirstName,
+		LastName:  r.Attributes.LastName,
+		Role:      r.Attributes.Role,
+		Status:    r.Attributes.Status,
+		Metadata:  r.Attributes.Metadata,
+		Created:   r.Attributes.Created,
+	}
+}
+
+type secondFactorRequest struct {
+	Data *secondFactorData `json:"data,omitempty"`
 	Meta struct {
-		Page struct {
-			Current int `json:"current"`
-			Total   int `json:"total"`
-		} `json:"page"`
+		Password string `json:"password,omitempty"`
+		OTP      string `json:"otp,omitempty"`
 	} `json:"meta"`
 }
 
+type secondFactorData struct {
+	Type       string `json:"type"`
+	Attributes struct {
+		Enabled bool `json:"enabled"`
+	} `json:"attributes"`
+}
+
+type secondFactorResponse struct {
+	Data secondFactorResource `json:"data"`
+}
+
+type secondFactorResource struct {
+	ID         string `json:"id"`
+	Attributes struct {
+		Enabled bool      `json:"enabled"`
+		URI     string    `json:"uri"`
+		Created time.Time `json:"created"`
+	} `json:"attributes"`
+}
+
+func (r secondFactorResource) toSecondFactor() SecondFactor {
+	return SecondFactor{
+		ID:      r.ID,
+		Enabled: r.Attributes.Enabled,
+		URI:     r.Attributes.URI,
+		Created: r.Attributes.Created,
+	}
+}
+
+// relationshipList is a to-many relationship linkage body.
+type relationshipList struct {
+	Data []relationshipData `json:"data"`
+}
+
+func newRelationshipList(typ string, ids []string) relationshipList {
+	l := relationshipList{Data: make([]relationshipData, 0, len(ids))}
+	for _, id := range ids {
+		l.Data = append(l.Data, relationshipData{Type: typ, ID: id})
+	}
+	return l
+}
+
+// -------- groups
+
+type groupRequest struct {
+	Data struct {
+		Type       string          `json:"type"`
+		Attributes groupAttributes `json:"attributes"`
+	} `json:"data"`
+}
+
+// groupAttributes is shared by create and update requests.
+type groupAttributes struct {
+	Name        *string         `json:"name,omitempty"`
+	MaxUsers    *int            `json:"maxUsers,omitempty"`
+	MaxLicenses *int            `json:"maxLicenses,omitempty"`
+	MaxMachines *int            `json:"maxMachines,omitempty"`
+	Metadata    *map[string]any `json:"metadata,omitempty"`
+}
+
+type groupResponse struct {
+	Data groupResource `json:"data"`
+}
+
+type groupResource struct {
+	ID         string `json:"id"`
+	Attributes struct {
+		Name        string         `json:"name"`
+		MaxUsers    *int           `json:"maxUsers"`
+		MaxLicenses *int           `json:"maxLicenses"`
+		MaxMachines *int           `json:"maxMachines"`
+		Metadata    map[string]any `json:"metadata"`
+		Created     time.Time      `json:"created"`
+	} `json:"attributes"`
+}
+
+func (r groupResource) toGroup() Group {
+	return Group{
+		ID:          r.ID,
+		Name:        r.Attributes.Name,
+		MaxUsers:    r.Attributes.MaxUsers,
+		MaxLicenses: r.Attributes.MaxLicenses,
+		MaxMachines: r.Attributes.MaxMachines,
+		Metadata:    r.Attributes.Metadata,
+		Created:     r.Attributes.Created,
+	}
+}
+
+// -------- environments
+
+type environmentRequest struct {
+	Data struct {
+		Type       string `json:"type"`
+		Attributes struct {
+			Name              string `json:"name,omitempty"`
+			Code              string `json:"code,omitempty"`
+			IsolationStrategy string `json:"isolationStrategy,omitempty"`
+		} `json:"attributes"`
+	} `json:"data"`
+}
+
+type environmentResponse struct {
+	Data environmentResource `json:"data"`
+}
+
+type environmentResource struct {
+	ID         string `json:"id"`
+	Attributes struct {
+		Name              string    `json:"name"`
+		Code              string    `json:"code"`
+		IsolationStrategy string    `json:"isolationStrategy"`
+		Created           time.Time `json:"created"`
+	} `json:"attributes"`
+}
+
+func (r environmentResource) toEnvironment() Environment {
+	return Environment{
+		ID:                r.ID,
+		Name:              r.Attributes.Name,
+		Code:              r.Attributes.Code,
+		IsolationStrategy: r.Attributes.IsolationStrategy,
+		Created:           r.Attributes.Created,
+	}
+}
+
+// -------- webhook endpoints
+
+type webhookEndpointRequest struct {
+	Data struct {
+		Type       string                    `json:"type"`
+		Attributes webhookEndpointAttributes `json:"attributes"`
+	} `json:"data"`
+}
+
+// webhookEndpointAttributes is shared by create and update requests.
+type webhookEndpointAttributes struct {
+	URL                *string   `json:"url,omitempty"`
+	Subscriptions      *[]string `json:"subscriptions,omitempty"`
+	SignatureAlgorithm *string   `json:"signatureAlgorithm,omitempty"`
+}
+
+type webhookEndpointResponse struct {
+	Data webhookEndpointResource `json:"data"`
+}
+
+type webhookEndpointResource struct {
+	ID         string `json:"id"`
+	Attributes struct {
+		URL                string    `json:"url"`
+		Subscriptions      []string  `json:"subscriptions"`
+		SignatureAlgorithm string    `json:"signatureAlgorithm"`
+		Created            time.Time `json:"created"`
+	} `json:"attributes"`
+}
+
+func (r webhookEndpointResource) toWebhookEndpoint() WebhookEndpoint {
+	return WebhookEndpoint{
+		ID:                 r.ID,
+		URL:                r.Attributes.URL,
+		Subscriptions:      r.Attributes.Subscriptions,
+		SignatureAlgorithm: r.Attributes.SignatureAlgorithm,
+		Created:            r.Attributes.Created,
+	}
+}
+
+// -------- tokens
+
+type tokenCreateRequest struct {
+	Data struct {
+		Type       string                `json:"type"`
+		Attributes tokenCreateAttributes `json:"attributes"`
+	} `json:"data"`
+}
+
+type tokenCreateAttributes struct {
+	Name             string  `json:"name,omitempty"`
+	Expiry           *string `json:"expiry,omitempty"`
+	MaxActivations   *int    `json:"maxActivations,omitempty"`
+	MaxDeactivations *int    `json:"maxDeactivations,omitempty"`
+}
+
+type tokenResponse struct {
+	Data tokenResource `json:"data"`
+}
+
+type tokenResource struct {
+	ID         string `json:"id"`
+	Attributes struct {
+		Kind             string     `json:"kind"`
+		Name             string     `json:"name"`
+		Token            string     `json:"token"`
+		Expiry           *time.Time `json:"expiry"`
+		MaxActivations   *int       `json:"maxActivations"`
+		MaxDeactivations *int       `json:"maxDeactivations"`
+		Created          time.Time  `json:"created"`
+	} `json:"attributes"`
+	Relationships struct {
+		Bearer struct {
+			Data *relationshipData `json:"data"`
+		} `json:"bearer"`
+	} `json:"relationships"`
+}
+
+func (r tokenResource) toToken() Token {
+	t := Token{
+		ID:               r.ID,
+		Kind:             r.Attributes.Kind,
+		Name:             r.Attributes.Name,
+		Token:            r.Attributes.Token,
+		Expiry:           r.Attributes.Expiry,
+		MaxActivations:   r.Attributes.MaxActivations,
+		MaxDeactivations: r.Attributes.MaxDeactivations,
+		Created:          r.Attributes.Created,
+	}
+	if b := r.Relationships.Bearer.Data; b != nil {
+		t.BearerType, t.BearerID = b.Type, b.ID
+	}
+	return t
+}
+
 // -------- validate
 
 type validateLicenseRequest struct {
@@ -77,11 +502,46 @@ type validateLicenseRequest struct {
 
 type validateMeta struct {
 	Key   string           `json:"key"`
-	Scope fingerprintScope `json:"scope"`
+	Nonce int64            `json:"nonce,omitempty"`
+	Scope *validationScope `json:"scope,omitempty"`
 }
 
-type fingerprintScope struct {
-	Fingerprint string `json:"fingerprint"`
+type validateByIDRequest struct {
+	Meta struct {
+		Nonce int64            `json:"nonce,omitempty"`
+		Scope *validationScope `json:"scope,omitempty"`
+	} `json:"meta"`
+}
+
+type validationScope struct {
+	Fingerprint  string   `json:"fingerprint,omitempty"`
+	Fingerprints []string `json:"fingerprints,omitempty"`
+	Product      string   `json:"product,omitempty"`
+	Policy       string   `json:"policy,omitempty"`
+	Machine      string   `json:"machine,omitempty"`
+	Entitlements []string `json:"entitlements,omitempty"`
+	Checksum     string   `json:"checksum,omitempty"`
+	Version      string   `json:"version,omitempty"`
+}
+
+// newValidationScope returns nil for an empty scope so that no "scope"
+// object is sent.
+func newValidationScope(s ValidationScope) *validationScope {
+	ws := validationScope{
+		Fingerprint:  s.Fingerprint,
+		Fingerprints: s.Fingerprints,
+		Product:      s.Product,
+		Policy:       s.Policy,
+		Machine:      s.Machine,
+		Entitlements: s.Entitlements,
+		Checksum:     s.Checksum,
+		Version:      s.Version,
+	}
+	if ws.Fingerprint == "" && len(ws.Fingerprints) == 0 && ws.Product == "" && ws.Policy == "" &&
+		ws.Machine == "" && len(ws.Entitlements) == 0 && ws.Checksum == "" && ws.Version == "" {
+		return nil
+	}
+	return &ws
 }
 
 type resolveLicenseIDRequest struct {
@@ -96,6 +556,7 @@ type licenseValidationResponse struct {
 		Code      string `json:"code"`
 		Detail    string `json:"detail"`
 		Timestamp string `json:"ts"`
+		Nonce     int64  `json:"nonce"`
 		Scope     struct {
 			Fingerprint string `json:"fingerprint"`
 		} `json:"scope"`
@@ -104,13 +565,95 @@ type licenseValidationResponse struct {
 		ID         string `json:"id"`
 		Type       string `json:"type"`
 		Attributes struct {
+			licenseLimitAttributes
 			Key    string `json:"key"`
 			Expiry string `json:"expiry"`
 			Status string `json:"status"`
 		} `json:"attributes"`
+		Relationships struct {
+			Machines machinesCountRelationship `json:"machines"`
+		} `json:"relationships"`
+	} `json:"data"`
+
+	signature string // Keygen-Signature response header
+}
+
+func (r *licenseValidationResponse) setHeader(h http.Header) {
+	r.signature = h.Get("Keygen-Signature")
+}
+
+func (r licenseValidationResponse) toValidation() LicenseValidation {
+	v := LicenseValidation{
+		Key:         r.Data.Attributes.Key,
+		Expiry:      r.Data.Attributes.Expiry,
+		Status:      r.Data.Attributes.Status,
+		Valid:       r.Meta.Valid,
+		Code:        ValidationCode(r.Meta.Code),
+		Detail:      r.Meta.Detail,
+		Timestamp:   r.Meta.Timestamp,
+		Fingerprint: r.Meta.Scope.Fingerprint,
+		Nonce:       r.Meta.Nonce,
+		Signature:   r.signature,
+		LicenseUsage: r.Data.Attributes.licenseLimitAttributes.toUsage(
+			r.Data.Relationships.Machines.Meta.Count),
+	}
+	if t, err := time.Parse(time.RFC3339, v.Expiry); err == nil {
+		v.ExpiresAt = &t
+	}
+	if t, err := time.Parse(time.RFC3339, v.Timestamp); err == nil {
+		v.ServerTime = t
+	}
+	return v
+}
+
+// -------- me
+
+type meResponse struct {
+	Data struct {
+		ID         string `json:"id"`
+		Type       string `json:"type"`
+		Attributes struct {
+			Role string `json:"role,omitempty"`
+		} `json:"attributes"`
 	} `json:"data"`
 }
 
+// -------- account
+
+type accountResponse struct {
+	Data struct {
+		ID         string `json:"id"`
+		Attributes struct {
+			Name       string `json:"name"`
+			Slug       string `json:"slug"`
+			APIVersion string `json:"apiVersion"`
+			PublicKey  string `json:"publicKey"`
+			Keys       struct {
+				Ed25519 string `json:"ed25519"`
+				RSA2048 string `json:"rsa2048"`
+			} `json:"keys"`
+			Created time.Time `json:"created"`
+		} `json:"attributes"`
+	} `json:"data"`
+}
+
+func (r accountResponse) toAccount() Account {
+	a := r.Data.Attributes
+	acct := Account{
+		ID:               r.Data.ID,
+		Name:             a.Name,
+		Slug:             a.Slug,
+		APIVersion:       a.APIVersion,
+		Ed25519PublicKey: a.Keys.Ed25519,
+		RSAPublicKey:     a.Keys.RSA2048,
+		Created:          a.Created,
+	}
+	if acct.RSAPublicKey == "" {
+		acct.RSAPublicKey = a.PublicKey // older API versions
+	}
+	return acct
+}
+
 // -------- machines
 
 type createMachineRequest struct {
@@ -125,30 +668,724 @@ type machineData struct {
 }
 
 type machineAttributes struct {
-	Fingerprint string `json:"fingerprint"`
-	Platform    string `json:"platform"`
-	Name        string `json:"name"`
+	Fingerprint string         `json:"fingerprint"`
+	Platform    string         `json:"platform"`
+	Name        string         `json:"name"`
+	Hostname    string         `json:"hostname,omitempty"`
+	IP          string         `json:"ip,omitempty"`
+	Cores       int            `json:"cores,omitempty"`
+	Metadata    map[string]any `json:"metadata,omitempty"`
+
+	// response only
+	Created          *time.Time `json:"created,omitempty"`
+	RequireHeartbeat *bool      `json:"requireHeartbeat,omitempty"`
+	HeartbeatStatus  string     `json:"heartbeatStatus,omitempty"`
+	LastHeartbeat    *time.Time `json:"lastHeartbeat,omitempty"`
 }
 
 type machineRelationships struct {
-	License licenseRelationship `json:"license"`
+	License licenseRelationship   `json:"license"`
+	Owner   *optionalRelationship `json:"owner,omitempty"`
+}
+
+func (d machineData) toMachine() Machine {
+	m := Machine{
+		ID:              d.ID,
+		LicenseId:       d.Relationships.License.Data.ID,
+		Fingerprint:     d.Attributes.Fingerprint,
+		Platform:        d.Attributes.Platform,
+		Name:            d.Attributes.Name,
+		Hostname:        d.Attributes.Hostname,
+		IP:              d.Attributes.IP,
+		Cores:           d.Attributes.Cores,
+		Metadata:        d.Attributes.Metadata,
+		HeartbeatStatus: d.Attributes.HeartbeatStatus,
+		LastHeartbeat:   d.Attributes.LastHeartbeat,
+	}
+	if d.Attributes.Created != nil {
+		m.Created = *d.Attributes.Created
+	}
+	if o := d.Relationships.Owner; o != nil && o.Data != nil {
+		m.OwnerID = o.Data.ID
+	}
+	if d.Attributes.RequireHeartbeat != nil {
+		m.RequireHeartbeat = *d.Attributes.RequireHeartbeat
+	}
+	return m
 }
 
 type machineResponse struct {
 	Data machineData `json:"data"`
 }
 
-type machinesListResponse struct {
-	Data  []machineData `json:"data"`
+type machineUpdateRequest struct {
+	Data struct {
+		Type       string                  `json:"type"`
+		Attributes machineUpdateAttributes `json:"attributes"`
+	} `json:"data"`
+}
+
+type machineUpdateAttributes struct {
+	Name     *string         `json:"name,omitempty"`
+	Platform *string         `json:"platform,omitempty"`
+	Hostname *string         `json:"hostname,omitempty"`
+	IP       *string         `json:"ip,omitempty"`
+	Cores    *int            `json:"cores,omitempty"`
+	Metadata *map[string]any `json:"metadata,omitempty"`
+}
+
+// certificateFileResponse is returned by the license and machine check-out
+// actions.
+type certificateFileResponse struct {
+	Data struct {
+		ID         string `json:"id"`
+		Attributes struct {
+			Certificate string     `json:"certificate"`
+			TTL         int        `json:"ttl"`
+			Expiry      *time.Time `json:"expiry"`
+			Issued      time.Time  `json:"issued"`
+			Encrypted   bool       `json:"encrypted"`
+			Algorithm   string     `json:"algorithm"`
+		} `json:"attributes"`
+	} `json:"data"`
+}
+
+func (r certificateFileResponse) toMachineFile() MachineFile {
+	return MachineFile(r.toLicenseFile())
+}
+
+func (r certificateFileResponse) toLicenseFile() LicenseFile {
+	a := r.Data.Attributes
+	return LicenseFile{
+		ID:          r.Data.ID,
+		Certificate: a.Certificate,
+		TTL:         a.TTL,
+		Expiry:      a.Expiry,
+		Issued:      a.Issued,
+		Encrypted:   a.Encrypted,
+		Algorithm:   a.Algorithm,
+	}
+}
+
+type machineProofRequest struct {
+	Meta struct {
+		Dataset map[string]any `json:"dataset,omitempty"`
+	} `json:"meta"`
+}
+
+type machineProofResponse struct {
+	Meta struct {
+		Proof string `json:"proof"`
+	} `json:"meta"`
+}
+
+// -------- products
+
+type productRequest struct {
+	Data struct {
+		Type       string            `json:"type"`
+		Attributes productAttributes `json:"attributes"`
+	} `json:"data"`
+}
+
+// productAttributes is shared by create and update requests.
+type productAttributes struct {
+	Name                 *string         `json:"name,omitempty"`
+	Code                 *string         `json:"code,omitempty"`
+	URL                  *string         `json:"url,omitempty"`
+	DistributionStrategy *string         `json:"distributionStrategy,omitempty"`
+	Platforms            *[]string       `json:"platforms,omitempty"`
+	Permissions          *[]string       `json:"permissions,omitempty"`
+	Metadata             *map[string]any `json:"metadata,omitempty"`
+}
+
+type productResponse struct {
+	Data productResource `json:"data"`
+}
+
+type productResource struct {
+	ID         string `json:"id"`
+	Attributes struct {
+		Name                 string         `json:"name"`
+		Code                 string         `json:"code"`
+		URL                  string         `json:"url"`
+		DistributionStrategy string         `json:"distributionStrategy"`
+		Platforms            []string       `json:"platforms"`
+		Permissions          []string       `json:"permissions"`
+		Metadata             map[string]any `json:"metadata"`
+		Created              time.Time      `json:"created"`
+		Updated              time.Time      `json:"updated"`
+	} `json:"attributes"`
+}
+
+func (r productResource) toProduct() Product {
+	a := r.Attributes
+	return Product{
+		ID:                   r.ID,
+		Name:                 a.Name,
+		Code:                 a.Code,
+		URL:                  a.URL,
+		DistributionStrategy: a.DistributionStrategy,
+		Platforms:            a.Platforms,
+		Permissions:          a.Permissions,
+		Metadata:             a.Metadata,
+		Created:              a.Created,
+		Updated:              a.Updated,
+	}
+}
+
+// -------- policies
+
+type policyRequest struct {
+	Data struct {
+		Type          string               `json:"type"`
+		Attributes    policyAttributes     `json:"attributes"`
+		Relationships *policyRelationships `json:"relationships,omitempty"` // create only
+	} `json:"data"`
+}
+
+type policyRelationships struct {
+	Product licenseRelationship `json:"product"`
+}
+
+// policyAttributes is shared by create (zero values omitted) and update
+// (nil pointers omitted) requests. Durations are in seconds; a null
+// duration means licenses never expire.
+type policyAttributes struct {
+	Name                          *string         `json:"name,omitempty"`
+	Duration                      *nullableInt    `json:"duration,omitempty"`
+	Scheme                        string          `json:"scheme,omitempty"`
+	Floating                      *bool           `json:"floating,omitempty"`
+	Strict                        *bool           `json:"strict,omitempty"`
+	Protected                     *bool           `json:"protected,omitempty"`
+	MaxMachines                   *int            `json:"maxMachines,omitempty"`
+	MaxUses                       *int            `json:"maxUses,omitempty"`
+	MaxCores                      *int            `json:"maxCores,omitempty"`
+	MaxProcesses                  *int            `json:"maxProcesses,omitempty"`
+	RequireHeartbeat              *bool           `json:"requireHeartbeat,omitempty"`
+	HeartbeatDuration             *int            `json:"heartbeatDuration,omitempty"`
+	HeartbeatCullStrategy         string          `json:"heartbeatCullStrategy,omitempty"`
+	HeartbeatResurrectionStrategy string          `json:"heartbeatResurrectionStrategy,omitempty"`
+	ExpirationStrategy            string          `json:"expirationStrategy,omitempty"`
+	AuthenticationStrategy        string          `json:"authenticationStrategy,omitempty"`
+	Metadata                      *map[string]any `json:"metadata,omitempty"`
+}
+
+// nullableInt encodes 0 as JSON null.
+type nullableInt int
+
+func (n nullableInt) MarshalJSON() ([]byte, error) {
+	if n == 0 {
+		return []byte("null"), nil
+	}
+	return []byte(strconv.Itoa(int(n))), nil
+}
+
+type policyResponse struct {
+	Data policyResource `json:"data"`
+}
+
+type policyResource struct {
+	ID         string `json:"id"`
+	Attributes struct {
+		Name                          string         `json:"name"`
+		Duration                      *int           `json:"duration"`
+		Scheme                        string         `json:"scheme"`
+		Floating                      bool           `json:"floating"`
+		Strict                        bool           `json:"strict"`
+		Protected                     bool           `json:"protected"`
+		MaxMachines                   *int           `json:"maxMachines"`
+		MaxUses                       *int           `json:"maxUses"`
+		MaxCores                      *int           `json:"maxCores"`
+		MaxProcesses                  *int           `json:"maxProcesses"`
+		RequireHeartbeat              bool           `json:"requireHeartbeat"`
+		HeartbeatDuration             *int           `json:"heartbeatDuration"`
+		HeartbeatCullStrategy         string         `json:"heartbeatCullStrategy"`
+		HeartbeatResurrectionStrategy string         `json:"heartbeatResurrectionStrategy"`
+		ExpirationStrategy            string         `json:"expirationStrategy"`
+		AuthenticationStrategy        string         `json:"authenticationStrategy"`
+		Metadata                      map[string]any `json:"metadata"`
+		Created                       time.Time      `json:"created"`
+		Updated                       time.Time      `json:"updated"`
+	} `json:"attributes"`
+	Relationships struct {
+		Product struct {
+			Data *relationshipData `json:"data"`
+		} `json:"product"`
+	} `json:"relationships"`
+}
+
+func (r policyResource) toPolicy() Policy {
+	a := r.Attributes
+	p := Policy{
+		ID:                            r.ID,
+		Name:                          a.Name,
+		Scheme:                        a.Scheme,
+		Floating:                      a.Floating,
+		Strict:                        a.Strict,
+		Protected:                     a.Protected,
+		MaxMachines:                   a.MaxMachines,
+		MaxUses:                       a.MaxUses,
+		MaxCores:                      a.MaxCores,
+		MaxProcesses:                  a.MaxProcesses,
+		RequireHeartbeat:              a.RequireHeartbeat,
+		HeartbeatCullStrategy:         a.HeartbeatCullStrategy,
+		HeartbeatResurrectionStrategy: a.HeartbeatResurrectionStrategy,
+		ExpirationStrategy:            a.ExpirationStrategy,
+		AuthenticationStrategy:        a.AuthenticationStrategy,
+		Metadata:                      a.Metadata,
+		Created:                       a.Created,
+		Updated:                       a.Updated,
+	}
+	if a.Duration != nil {
+		p.Duration = time.Duration(*a.Duration) * time.Second
+	}
+	if a.HeartbeatDuration != nil {
+		p.HeartbeatDuration = time.Duration(*a.HeartbeatDuration) * time.Second
+	}
+	if r.Relationships.Product.Data != nil {
+		p.ProductID = r.Relationships.Product.Data.ID
+	}
+	return p
+}
+
+// -------- distribution
+
+type releaseRequest struct {
+	Data struct {
+		Type          string                `json:"type"`
+		Attributes    releaseAttributes     `json:"attributes"`
+		Relationships *releaseRelationships `json:"relationships,omitempty"` // create only
+	} `json:"data"`
+}
+
+// releaseAttributes is shared by create and update requests.
+type releaseAttributes struct {
+	Name        *string         `json:"name,omitempty"`
+	Version     string          `json:"version,omitempty"`
+	Channel     string          `json:"channel,omitempty"`
+	Tag         *string         `json:"tag,omitempty"`
+	Description *string         `json:"description,omitempty"`
+	Metadata    *map[string]any `json:"metadata,omitempty"`
+}
+
+type releaseRelationships struct {
+	Product licenseRelationship   `json:"product"`
+	Package *optionalRelationship `json:"package,omitempty"`
+}
+
+type releaseResponse struct {
+	Data releaseResource `json:"data"`
+}
+
+type releaseResource struct {
+	ID         string `json:"id"`
+	Attributes struct {
+		Name        string         `json:"name"`
+		Version     string         `json:"version"`
+		Channel     string         `json:"channel"`
+		Status      string         `json:"status"`
+		Tag         string         `json:"tag"`
+		Description string         `json:"description"`
+		Metadata    map[string]any `json:"metadata"`
+		Created     time.Time      `json:"created"`
+		Updated     time.Time      `json:"updated"`
+	} `json:"attributes"`
+	Relationships struct {
+		Product optionalRelationship `json:"product"`
+		Package optionalRelationship `json:"package"`
+	} `json:"relationships"`
+}
+
+func (r releaseResource) toRelease() Release {
+	a := r.Attributes
+	rel := Release{
+		ID:          r.ID,
+		Name:        a.Name,
+		Version:     a.Version,
+		Channel:     a.Channel,
+		Status:      a.Status,
+		Tag:         a.Tag,
+		Description: a.Description,
+		Metadata:    a.Metadata,
+		Created:     a.Created,
+		Updated:     a.Updated,
+	}
+	if d := r.Relationships.Product.Data; d != nil {
+		rel.ProductID = d.ID
+	}
+	if d := r.Relationships.Package.Data; d != nil {
+		rel.PackageID = d.ID
+	}
+	return rel
+}
+
+type artifactCreateRequest struct {
+	Data struct {
+		Type       string `json:"type"`
+		Attributes struct {
+			Filename  string         `json:"filename"`
+			Filetype  string         `json:"filetype,omitempty"`
+			Filesize  int64          `json:"filesize,omitempty"`
+			Platform  string         `json:"platform,omitempty"`
+			Arch      string         `json:"arch,omitempty"`
+			Checksum  string         `json:"checksum,omitempty"`
+			Signature string         `json:"signature,omitempty"`
+			Metadata  map[string]any `json:"metadata,omitempty"`
+		} `json:"attributes"`
+		Relationships struct {
+			Release licenseRelationship `json:"release"`
+		} `json:"relationships"`
+	} `json:"data"`
+}
+
+// artifactResponse carries links.redirect, the upload or download URL, when
+// the request is sent with Prefer: no-redirect.
+type artifactResponse struct {
+	Data  artifactResource `json:"data"`
 	Links struct {
-		Self  string  `json:"self"`
-		Prev  *string `json:"prev"`
-		Next  *string `json:"next"`
-		First string  `json:"first"`
-		Last  string  `json:"last"`
+		Redirect string `json:"redirect"`
 	} `json:"links"`
+}
+
+type artifactResource struct {
+	ID         string `json:"id"`
+	Attributes struct {
+		Filename  string         `json:"filename"`
+		Filetype  string         `json:"filetype"`
+		Filesize  int64          `json:"filesize"`
+		Platform  string         `json:"platform"`
+		Arch      string         `json:"arch"`
+		Checksum  string         `json:"checksum"`
+		Signature string         `json:"signature"`
+		Status    string         `json:"status"`
+		Metadata  map[string]any `json:"metadata"`
+		Created   time.Time      `json:"created"`
+	} `json:"attributes"`
+	Relationships struct {
+		Release optionalRelationship `json:"release"`
+	} `json:"relationships"`
+}
+
+func (r artifactResource) toArtifact() Artifact {
+	a := r.Attributes
+	art := Artifact{
+		ID:        r.ID,
+		Filename:  a.Filename,
+		Filetype:  a.Filetype,
+		Filesize:  a.Filesize,
+		Platform:  a.Platform,
+		Arch:      a.Arch,
+		Checksum:  a.Checksum,
+		Signature: a.Signature,
+		Status:    a.Status,
+		Metadata:  a.Metadata,
+		Created:   a.Created,
+	}
+	if d := r.Relationships.Release.Data; d != nil {
+		art.ReleaseID = d.ID
+	}
+	return art
+}
+
+type upgradeResponse struct {
+	Data artifactResource `json:"data"`
 	Meta struct {
-		Pages int `json:"pages"`
-		Count int `json:"count"`
+		Current string `json:"current"`
+		Next    string `json:"next"`
 	} `json:"meta"`
+	Links struct {
+		Redirect string `json:"redirect"`
+	} `json:"links"`
+}
+
+type packageCreateRequest struct {
+	Data struct {
+		Type       string `json:"type"`
+		Attributes struct {
+			Name     string         `json:"name"`
+			Key      string         `json:"key"`
+			Engine   string         `json:"engine,omitempty"`
+			Metadata map[string]any `json:"metadata,omitempty"`
+		} `json:"attributes"`
+		Relationships struct {
+			Product licenseRelationship `json:"product"`
+		} `json:"relationships"`
+	} `json:"data"`
+}
+
+type packageResponse struct {
+	Data packageResource `json:"data"`
+}
+
+type packageResource struct {
+	ID         string `json:"id"`
+	Attributes struct {
+		Name     string         `json:"name"`
+		Key      string         `json:"key"`
+		Engine   string         `json:"engine"`
+		Metadata map[string]any `json:"metadata"`
+		Created  time.Time      `json:"created"`
+	} `json:"attributes"`
+	Relationships struct {
+		Product optionalRelationship `json:"product"`
+	} `json:"relationships"`
+}
+
+func (r packageResource) toPackage() Package {
+	p := Package{
+		ID:       r.ID,
+		Name:     r.Attributes.Name,
+		Key:      r.Attributes.Key,
+		Engine:   r.Attributes.Engine,
+		Metadata: r.Attributes.Metadata,
+		Created:  r.Attributes.Created,
+	}
+	if d := r.Relationships.Product.Data; d != nil {
+		p.ProductID = d.ID
+	}
+	return p
+}
+
+// -------- processes
+
+type processCreateRequest struct {
+	Data struct {
+		Type       string `json:"type"`
+		Attributes struct {
+			PID      string         `json:"pid"`
+			Metadata map[string]any `json:"metadata,omitempty"`
+		} `json:"attributes"`
+		Relationships struct {
+			Machine licenseRelationship `json:"machine"`
+		} `json:"relationships"`
+	} `json:"data"`
+}
+
+type processResponse struct {
+	Data processResource `json:"data"`
+}
+
+type processResource struct {
+	ID         string `json:"id"`
+	Attributes struct {
+		PID           string         `json:"pid"`
+		Status        string         `json:"status"`
+		Interval      int            `json:"interval"`
+		LastHeartbeat *time.Time     `json:"lastHeartbeat"`
+		Metadata      map[string]any `json:"metadata"`
+		Created       time.Time      `json:"created"`
+	} `json:"attributes"`
+	Relationships struct {
+		Machine struct {
+			Data *relationshipData `json:"data"`
+		} `json:"machine"`
+	} `json:"relationships"`
+}
+
+func (r processResource) toProcess() Process {
+	p := Process{
+		ID:            r.ID,
+		PID:           r.Attributes.PID,
+		Status:        r.Attributes.Status,
+		Interval:      r.Attributes.Interval,
+		LastHeartbeat: r.Attributes.LastHeartbeat,
+		Metadata:      r.Attributes.Metadata,
+		Created:       r.Attributes.Created,
+	}
+	if r.Relationships.Machine.Data != nil {
+		p.MachineID = r.Relationships.Machine.Data.ID
+	}
+	return p
+}
+
+// -------- components
+
+type componentCreateRequest struct {
+	Data struct {
+		Type       string `json:"type"`
+		Attributes struct {
+			Fingerprint string         `json:"fingerprint"`
+			Name        string         `json:"name"`
+			Metadata    map[string]any `json:"metadata,omitempty"`
+		} `json:"attributes"`
+		Relationships struct {
+			Machine licenseRelationship `json:"machine"`
+		} `json:"relationships"`
+	} `json:"data"`
+}
+
+type componentUpdateRequest struct {
+	Data struct {
+		Type       string `json:"type"`
+		Attributes struct {
+			Name     *string         `json:"name,omitempty"`
+			Metadata *map[string]any `json:"metadata,omitempty"`
+		} `json:"attributes"`
+	} `json:"data"`
+}
+
+type componentResponse struct {
+	Data componentResource `json:"data"`
+}
+
+type componentResource struct {
+	ID         string `json:"id"`
+	Attributes struct {
+		Fingerprint string         `json:"fingerprint"`
+		Name        string         `json:"name"`
+		Metadata    map[string]any `json:"metadata"`
+		Created     time.Time      `json:"created"`
+	} `json:"attributes"`
+	Relationships struct {
+		Machine struct {
+			Data *relationshipData `json:"data"`
+		} `json:"machine"`
+	} `json:"relationships"`
+}
+
+func (r componentResource) toComponent() Component {
+	c := Component{
+		ID:          r.ID,
+		Fingerprint: r.Attributes.Fingerprint,
+		Name:        r.Attributes.Name,
+		Metadata:    r.Attributes.Metadata,
+		Created:     r.Attributes.Created,
+	}
+	if r.Relationships.Machine.Data != nil {
+		c.MachineID = r.Relationships.Machine.Data.ID
+	}
+	return c
+}
+
+// -------- logs
+
+type requestLogResponse struct {
+	Data requestLogResource `json:"data"`
+}
+
+type requestLogResource struct {
+	ID         string `json:"id"`
+	Attributes struct {
+		Method       string    `json:"method"`
+		URL          string    `json:"url"`
+		Status       string    `json:"status"`
+		IP           string    `json:"ip"`
+		UserAgent    string    `json:"userAgent"`
+		RequestBody  string    `json:"requestBody"`
+		ResponseBody string    `json:"responseBody"`
+		Created      time.Time `json:"created"`
+	} `json:"attributes"`
+	Relationships struct {
+		Requestor optionalRelationship `json:"requestor"`
+		Resource  optionalRelationship `json:"resource"`
+	} `json:"relationships"`
+}
+
+func (r requestLogResource) toRequestLog() RequestLog {
+	a := r.Attributes
+	l := RequestLog{
+		ID:           r.ID,
+		Method:       a.Method,
+		URL:          a.URL,
+		IP:           a.IP,
+		UserAgent:    a.UserAgent,
+		RequestBody:  a.RequestBody,
+		ResponseBody: a.ResponseBody,
+		Created:      a.Created,
+	}
+	l.Status, _ = strconv.Atoi(a.Status)
+	if d := r.Relationships.Requestor.Data; d != nil {
+		l.RequestorType, l.RequestorID = d.Type, d.ID
+	}
+	if d := r.Relationships.Resource.Data; d != nil {
+		l.ResourceType, l.ResourceID = d.Type, d.ID
+	}
+	return l
+}
+
+type eventLogResponse struct {
+	Data eventLogResource `json:"data"`
+}
+
+type eventLogResource struct {
+	ID         string `json:"id"`
+	Attributes struct {
+		Event    string         `json:"event"`
+		Metadata map[string]any `json:"metadata"`
+		Created  time.Time      `json:"created"`
+	} `json:"attributes"`
+	Relationships struct {
+		Whodunnit optionalRelationship `json:"whodunnit"`
+		Resource  optionalRelationship `json:"resource"`
+		Request   optionalRelationship `json:"request"`
+	} `json:"relationships"`
+}
+
+func (r eventLogResource) toEventLog() EventLog {
+	l := EventLog{
+		ID:       r.ID,
+		Event:    r.Attributes.Event,
+		Metadata: r.Attributes.Metadata,
+		Created:  r.Attributes.Created,
+	}
+	if d := r.Relationships.Whodunnit.Data; d != nil {
+		l.WhodunnitType, l.WhodunnitID = d.Type, d.ID
+	}
+	if d := r.Relationships.Resource.Data; d != nil {
+		l.ResourceType, l.ResourceID = d.Type, d.ID
+	}
+	if d := r.Relationships.Request.Data; d != nil {
+		l.RequestLogID = d.ID
+	}
+	return l
+}
+
+// -------- analytics
+
+type analyticsCountsResponse struct {
+	Meta AnalyticsCounts `json:"meta"`
+}
+
+// metricsCountResponse maps YYYY-MM-DD to an event count.
+type metricsCountResponse struct {
+	Meta map[string]int `json:"meta"`
+}
+
+// topVolumeResponse is shared by the top-*-by-volume reports; each report
+// sets only its own key field.
+type topVolumeResponse struct {
+	Data []struct {
+		LicenseID string `json:"licenseId"`
+		URL       string `json:"url"`
+		Method    string `json:"method"`
+		IP        string `json:"ip"`
+		Count     int    `json:"count"`
+	} `json:"data"`
+}
+
+// -------- LAN proxy
+
+// proxyValidateRequest is the body accepted by /validate.
+type proxyValidateRequest struct {
+	Key         string `json:"key"`
+	Fingerprint string `json:"fingerprint"`
+}
+
+// proxyActivateRequest is the body accepted by /activate.
+type proxyActivateRequest struct {
+	Key         string `json:"key"`
+	Fingerprint string `json:"fingerprint"`
+	Name        string `json:"name,omitempty"`
+	Platform    string `json:"platform,omitempty"`
+}
+
+// proxyValidateResponse wraps a validation with its provenance.
+type proxyValidateResponse struct {
+	LicenseValidation
+	Cached bool `json:"cached"`
+}
+
+type proxyErrorResponse struct {
+	Error string `json:"error"`
 }
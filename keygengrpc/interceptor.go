@@ -0,0 +1,108 @@
+package keygengrpc
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// ErrUnauthenticated is returned by the Authenticators in this package when
+// a call carries no or unknown credentials.
+var ErrUnauthenticated = errors.New("keygengrpc: unauthenticated")
+
+// Authenticator identifies the caller of the RPC method (e.g.
+// "/dappnode.keygen.v1.KeygenService/CreateLicense") from ctx, typically
+// from its metadata or peer TLS state. It returns the caller name recorded
+// in audit records, or an error to reject the call.
+type Authenticator func(ctx context.Context, method string) (caller string, err error)
+
+// AuditRecord describes one RPC handled behind UnaryInterceptor.
+type AuditRecord struct {
+	Time     time.Time
+	Caller   string // "" when authentication failed
+	Peer     string // remote address, if known
+	Method   string
+	Code     codes.Code
+	Duration time.Duration
+}
+
+// AuditFunc receives an AuditRecord for every call, including rejected ones.
+type AuditFunc func(ctx context.Context, rec AuditRecord)
+
+type callerKey struct{}
+
+// CallerFromContext returns the caller name set by UnaryInterceptor.
+func CallerFromContext(ctx context.Context) (string, bool) {
+	caller, ok := ctx.Value(callerKey{}).(string)
+	return caller, ok
+}
+
+// BearerTokens returns an Authenticator accepting "authorization: Bearer
+// <token>" metadata for any token in tokens, which maps caller names to
+// their tokens. Tokens are compared in constant time.
+func BearerTokens(tokens map[string]string) Authenticator {
+	return func(ctx context.Context, _ string) (string, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		for _, v := range md.Get("authorization") {
+			got, ok := strings.CutPrefix(v, "Bearer ")
+			if !ok || got == "" {
+				continue
+			}
+			for caller, token := range tokens {
+				if subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1 {
+					return caller, nil
+				}
+			}
+		}
+		return "", ErrUnauthenticated
+	}
+}
+
+// UnaryInterceptor authenticates every call with auth and reports it to
+// audit, if non-nil. The Server acts with the client's API token, so it
+// should never be served without this interceptor; a nil auth rejects
+// every call.
+//
+//	s := grpc.NewServer(grpc.ChainUnaryInterceptor(
+//		keygengrpc.UnaryInterceptor(keygengrpc.BearerTokens(tokens), audit)))
+//	keygengrpc.Register(s, client)
+func UnaryInterceptor(auth Authenticator, audit AuditFunc) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		var (
+			caller string
+			resp   any
+			err    error
+		)
+		if auth == nil {
+			err = status.Error(codes.Unauthenticated, "no authenticator configured")
+		} else if caller, err = auth(ctx, info.FullMethod); err != nil {
+			caller = ""
+			err = status.Error(codes.Unauthenticated, "unauthenticated")
+		} else {
+			resp, err = handler(context.WithValue(ctx, callerKey{}, caller), req)
+		}
+		if audit != nil {
+			rec := AuditRecord{
+				Time:     start,
+				Caller:   caller,
+				Method:   info.FullMethod,
+				Code:     status.Code(err),
+				Duration: time.Since(start),
+			}
+			if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+				rec.Peer = p.Addr.String()
+			}
+			audit(ctx, rec)
+		}
+		return resp, err
+	}
+}
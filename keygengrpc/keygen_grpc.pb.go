@@ -0,0 +1,437 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: keygen.proto
+
+package keygengrpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	KeygenService_CreateLicense_FullMethodName              = "/dappnode.keygen.v1.KeygenService/CreateLicense"
+	KeygenService_DeleteLicense_FullMethodName              = "/dappnode.keygen.v1.KeygenService/DeleteLicense"
+	KeygenService_GetLicenseBySubscriptionID_FullMethodName = "/dappnode.keygen.v1.KeygenService/GetLicenseBySubscriptionID"
+	KeygenService_ListLicensesByPolicy_FullMethodName       = "/dappnode.keygen.v1.KeygenService/ListLicensesByPolicy"
+	KeygenService_ResolveLicenseID_FullMethodName           = "/dappnode.keygen.v1.KeygenService/ResolveLicenseID"
+	KeygenService_ActivateMachine_FullMethodName            = "/dappnode.keygen.v1.KeygenService/ActivateMachine"
+	KeygenService_DeactivateMachine_FullMethodName          = "/dappnode.keygen.v1.KeygenService/DeactivateMachine"
+	KeygenService_ListMachines_FullMethodName               = "/dappnode.keygen.v1.KeygenService/ListMachines"
+	KeygenService_Validate_FullMethodName                   = "/dappnode.keygen.v1.KeygenService/Validate"
+)
+
+// KeygenServiceClient is the client API for KeygenService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// KeygenService exposes the keygen client to internal, non-Go services so the
+// Keygen API token lives in a single audited gateway.
+type KeygenServiceClient interface {
+	// --- Licenses ---
+	CreateLicense(ctx context.Context, in *CreateLicenseRequest, opts ...grpc.CallOption) (*CreateLicenseResponse, error)
+	DeleteLicense(ctx context.Context, in *DeleteLicenseRequest, opts ...grpc.CallOption) (*DeleteLicenseResponse, error)
+	GetLicenseBySubscriptionID(ctx context.Context, in *GetLicenseBySubscriptionIDRequest, opts ...grpc.CallOption) (*GetLicenseBySubscriptionIDResponse, error)
+	ListLicensesByPolicy(ctx context.Context, in *ListLicensesByPolicyRequest, opts ...grpc.CallOption) (*ListLicensesByPolicyResponse, error)
+	ResolveLicenseID(ctx context.Context, in *ResolveLicenseIDRequest, opts ...grpc.CallOption) (*ResolveLicenseIDResponse, error)
+	// --- Machines ---
+	ActivateMachine(ctx context.Context, in *ActivateMachineRequest, opts ...grpc.CallOption) (*ActivateMachineResponse, error)
+	DeactivateMachine(ctx context.Context, in *DeactivateMachineRequest, opts ...grpc.CallOption) (*DeactivateMachineResponse, error)
+	ListMachines(ctx context.Context, in *ListMachinesRequest, opts ...grpc.CallOption) (*ListMachinesResponse, error)
+	// --- Validation ---
+	Validate(ctx context.Context, in *ValidateRequest, opts ...grpc.CallOption) (*ValidateResponse, error)
+}
+
+type keygenServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewKeygenServiceClient(cc grpc.ClientConnInterface) KeygenServiceClient {
+	return &keygenServiceClient{cc}
+}
+
+func (c *keygenServiceClient) CreateLicense(ctx context.Context, in *CreateLicenseRequest, opts ...grpc.CallOption) (*CreateLicenseResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateLicenseResponse)
+	err := c.cc.Invoke(ctx, KeygenService_CreateLicense_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keygenServiceClient) DeleteLicense(ctx context.Context, in *DeleteLicenseRequest, opts ...grpc.CallOption) (*DeleteLicenseResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteLicenseResponse)
+	err := c.cc.Invoke(ctx, KeygenService_DeleteLicense_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keygenServiceClient) GetLicenseBySubscriptionID(ctx context.Context, in *GetLicenseBySubscriptionIDRequest, opts ...grpc.CallOption) (*GetLicenseBySubscriptionIDResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetLicenseBySubscriptionIDResponse)
+	err := c.cc.Invoke(ctx, KeygenService_GetLicenseBySubscriptionID_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keygenServiceClient) ListLicensesByPolicy(ctx context.Context, in *ListLicensesByPolicyRequest, opts ...grpc.CallOption) (*ListLicensesByPolicyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListLicensesByPolicyResponse)
+	err := c.cc.Invoke(ctx, KeygenService_ListLicensesByPolicy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keygenServiceClient) ResolveLicenseID(ctx context.Context, in *ResolveLicenseIDRequest, opts ...grpc.CallOption) (*ResolveLicenseIDResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResolveLicenseIDResponse)
+	err := c.cc.Invoke(ctx, KeygenService_ResolveLicenseID_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keygenServiceClient) ActivateMachine(ctx context.Context, in *ActivateMachineRequest, opts ...grpc.CallOption) (*ActivateMachineResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ActivateMachineResponse)
+	err := c.cc.Invoke(ctx, KeygenService_ActivateMachine_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keygenServiceClient) DeactivateMachine(ctx context.Context, in *DeactivateMachineRequest, opts ...grpc.CallOption) (*DeactivateMachineResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeactivateMachineResponse)
+	err := c.cc.Invoke(ctx, KeygenService_DeactivateMachine_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keygenServiceClient) ListMachines(ctx context.Context, in *ListMachinesRequest, opts ...grpc.CallOption) (*ListMachinesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListMachinesResponse)
+	err := c.cc.Invoke(ctx, KeygenService_ListMachines_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keygenServiceClient) Validate(ctx context.Context, in *ValidateRequest, opts ...grpc.CallOption) (*ValidateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ValidateResponse)
+	err := c.cc.Invoke(ctx, KeygenService_Validate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// KeygenServiceServer is the server API for KeygenService service.
+// All implementations must embed UnimplementedKeygenServiceServer
+// for forward compatibility.
+//
+// KeygenService exposes the keygen client to internal, non-Go services so the
+// Keygen API token lives in a single audited gateway.
+type KeygenServiceServer interface {
+	// --- Licenses ---
+	CreateLicense(context.Context, *CreateLicenseRequest) (*CreateLicenseResponse, error)
+	DeleteLicense(context.Context, *DeleteLicenseRequest) (*DeleteLicenseResponse, error)
+	GetLicenseBySubscriptionID(context.Context, *GetLicenseBySubscriptionIDRequest) (*GetLicenseBySubscriptionIDResponse, error)
+	ListLicensesByPolicy(context.Context, *ListLicensesByPolicyRequest) (*ListLicensesByPolicyResponse, error)
+	ResolveLicenseID(context.Context, *ResolveLicenseIDRequest) (*ResolveLicenseIDResponse, error)
+	// --- Machines ---
+	ActivateMachine(context.Context, *ActivateMachineRequest) (*ActivateMachineResponse, error)
+	DeactivateMachine(context.Context, *DeactivateMachineRequest) (*DeactivateMachineResponse, error)
+	ListMachines(context.Context, *ListMachinesRequest) (*ListMachinesResponse, error)
+	// --- Validation ---
+	Validate(context.Context, *ValidateRequest) (*ValidateResponse, error)
+	mustEmbedUnimplementedKeygenServiceServer()
+}
+
+// UnimplementedKeygenServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedKeygenServiceServer struct{}
+
+func (UnimplementedKeygenServiceServer) CreateLicense(context.Context, *CreateLicenseRequest) (*CreateLicenseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateLicense not implemented")
+}
+func (UnimplementedKeygenServiceServer) DeleteLicense(context.Context, *DeleteLicenseRequest) (*DeleteLicenseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteLicense not implemented")
+}
+func (UnimplementedKeygenServiceServer) GetLicenseBySubscriptionID(context.Context, *GetLicenseBySubscriptionIDRequest) (*GetLicenseBySubscriptionIDResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLicenseBySubscriptionID not implemented")
+}
+func (UnimplementedKeygenServiceServer) ListLicensesByPolicy(context.Context, *ListLicensesByPolicyRequest) (*ListLicensesByPolicyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLicensesByPolicy not implemented")
+}
+func (UnimplementedKeygenServiceServer) ResolveLicenseID(context.Context, *ResolveLicenseIDRequest) (*ResolveLicenseIDResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResolveLicenseID not implemented")
+}
+func (UnimplementedKeygenServiceServer) ActivateMachine(context.Context, *ActivateMachineRequest) (*ActivateMachineResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ActivateMachine not implemented")
+}
+func (UnimplementedKeygenServiceServer) DeactivateMachine(context.Context, *DeactivateMachineRequest) (*DeactivateMachineResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeactivateMachine not implemented")
+}
+func (UnimplementedKeygenServiceServer) ListMachines(context.Context, *ListMachinesRequest) (*ListMachinesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMachines not implemented")
+}
+func (UnimplementedKeygenServiceServer) Validate(context.Context, *ValidateRequest) (*ValidateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Validate not implemented")
+}
+func (UnimplementedKeygenServiceServer) mustEmbedUnimplementedKeygenServiceServer() {}
+func (UnimplementedKeygenServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafeKeygenServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to KeygenServiceServer will
+// result in compilation errors.
+type UnsafeKeygenServiceServer interface {
+	mustEmbedUnimplementedKeygenServiceServer()
+}
+
+func RegisterKeygenServiceServer(s grpc.ServiceRegistrar, srv KeygenServiceServer) {
+	// If the following call pancis, it indicates UnimplementedKeygenServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&KeygenService_ServiceDesc, srv)
+}
+
+func _KeygenService_CreateLicense_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateLicenseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeygenServiceServer).CreateLicense(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeygenService_CreateLicense_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeygenServiceServer).CreateLicense(ctx, req.(*CreateLicenseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeygenService_DeleteLicense_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteLicenseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeygenServiceServer).DeleteLicense(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeygenService_DeleteLicense_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeygenServiceServer).DeleteLicense(ctx, req.(*DeleteLicenseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeygenService_GetLicenseBySubscriptionID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLicenseBySubscriptionIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeygenServiceServer).GetLicenseBySubscriptionID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeygenService_GetLicenseBySubscriptionID_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeygenServiceServer).GetLicenseBySubscriptionID(ctx, req.(*GetLicenseBySubscriptionIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeygenService_ListLicensesByPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLicensesByPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeygenServiceServer).ListLicensesByPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeygenService_ListLicensesByPolicy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeygenServiceServer).ListLicensesByPolicy(ctx, req.(*ListLicensesByPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeygenService_ResolveLicenseID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveLicenseIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeygenServiceServer).ResolveLicenseID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeygenService_ResolveLicenseID_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeygenServiceServer).ResolveLicenseID(ctx, req.(*ResolveLicenseIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeygenService_ActivateMachine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ActivateMachineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeygenServiceServer).ActivateMachine(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeygenService_ActivateMachine_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeygenServiceServer).ActivateMachine(ctx, req.(*ActivateMachineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeygenService_DeactivateMachine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeactivateMachineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeygenServiceServer).DeactivateMachine(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeygenService_DeactivateMachine_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeygenServiceServer).DeactivateMachine(ctx, req.(*DeactivateMachineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeygenService_ListMachines_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMachinesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeygenServiceServer).ListMachines(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeygenService_ListMachines_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeygenServiceServer).ListMachines(ctx, req.(*ListMachinesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeygenService_Validate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeygenServiceServer).Validate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeygenService_Validate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeygenServiceServer).Validate(ctx, req.(*ValidateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// KeygenService_ServiceDesc is the grpc.ServiceDesc for KeygenService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var KeygenService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "dappnode.keygen.v1.KeygenService",
+	HandlerType: (*KeygenServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateLicense",
+			Handler:    _KeygenService_CreateLicense_Handler,
+		},
+		{
+			MethodName: "DeleteLicense",
+			Handler:    _KeygenService_DeleteLicense_Handler,
+		},
+		{
+			MethodName: "GetLicenseBySubscriptionID",
+			Handler:    _KeygenService_GetLicenseBySubscriptionID_Handler,
+		},
+		{
+			MethodName: "ListLicensesByPolicy",
+			Handler:    _KeygenService_ListLicensesByPolicy_Handler,
+		},
+		{
+			MethodName: "ResolveLicenseID",
+			Handler:    _KeygenService_ResolveLicenseID_Handler,
+		},
+		{
+			MethodName: "ActivateMachine",
+			Handler:    _KeygenService_ActivateMachine_Handler,
+		},
+		{
+			MethodName: "DeactivateMachine",
+			Handler:    _KeygenService_DeactivateMachine_Handler,
+		},
+		{
+			MethodName: "ListMachines",
+			Handler:    _KeygenService_ListMachines_Handler,
+		},
+		{
+			MethodName: "Validate",
+			Handler:    _KeygenService_Validate_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "keygen.proto",
+}
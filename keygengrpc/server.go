@@ -0,0 +1,253 @@
+// Package keygengrpc exposes the keygen client as a gRPC service so non-Go
+// internal services can consume licensing through a single gateway that
+// holds the Keygen API token. Serve it behind UnaryInterceptor, which
+// authenticates and audits every call; error statuses never carry the raw
+// API response body.
+package keygengrpc
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative keygen.proto
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/dappnode/keygen-client/keygen"
+)
+
+// Server implements KeygenServiceServer on top of a keygen.Client.
+type Server struct {
+	UnimplementedKeygenServiceServer
+
+	client *keygen.Client
+}
+
+// NewServer creates a Server backed by c.
+func NewServer(c *keygen.Client) *Server {
+	return &Server{client: c}
+}
+
+// Register creates a Server backed by c and registers it on s.
+func Register(s grpc.ServiceRegistrar, c *keygen.Client) *Server {
+	srv := NewServer(c)
+	RegisterKeygenServiceServer(s, srv)
+	return srv
+}
+
+// --- Licenses ---
+
+func (s *Server) CreateLicense(ctx context.Context, req *CreateLicenseRequest) (*CreateLicenseResponse, error) {
+	if req.GetPolicyId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "policy_id is required")
+	}
+	meta := keygen.LicenseMetadata{
+		SubscriptionID: req.GetMetadata().GetSubscriptionId(),
+		CustomerEmail:  req.GetMetadata().GetCustomerEmail(),
+	}
+	key, err := s.client.CreateLicense(ctx, req.GetPolicyId(), meta)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &CreateLicenseResponse{Key: key}, nil
+}
+
+func (s *Server) DeleteLicense(ctx context.Context, req *DeleteLicenseRequest) (*DeleteLicenseResponse, error) {
+	if req.GetLicenseId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "license_id is required")
+	}
+	if err := s.client.DeleteLicense(ctx, req.GetLicenseId()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &DeleteLicenseResponse{}, nil
+}
+
+func (s *Server) GetLicenseBySubscriptionID(ctx context.Context, req *GetLicenseBySubscriptionIDRequest) (*GetLicenseBySubscriptionIDResponse, error) {
+	if req.GetSubscriptionId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "subscription_id is required")
+	}
+	id, err := s.client.GetLicenseBySubscriptionID(ctx, req.GetSubscriptionId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &GetLicenseBySubscriptionIDResponse{LicenseId: id}, nil
+}
+
+func (s *Server) ListLicensesByPolicy(ctx context.Context, req *ListLicensesByPolicyRequest) (*ListLicensesByPolicyResponse, error) {
+	if req.GetPolicyId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "policy_id is required")
+	}
+	items, err := s.client.ListLicensesByPolicy(ctx, req.GetPolicyId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	out := make([]*LicenseSummary, 0, len(items))
+	for _, it := range items {
+		meta, err := encodeMetadata(it.Metadata)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "encode metadata for license %s: %v", it.ID, err)
+		}
+		out = append(out, &LicenseSummary{
+			Id:       it.ID,
+			Key:      it.Key,
+			Status:   it.Status,
+			Metadata: meta,
+		})
+	}
+	return &ListLicensesByPolicyResponse{Licenses: out}, nil
+}
+
+func (s *Server) ResolveLicenseID(ctx context.Context, req *ResolveLicenseIDRequest) (*ResolveLicenseIDResponse, error) {
+	if req.GetLicenseKey() == "" {
+		return nil, status.Error(codes.InvalidArgument, "license_key is required")
+	}
+	id, err := s.client.ResolveLicenseID(ctx, req.GetLicenseKey())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &ResolveLicenseIDResponse{LicenseId: id}, nil
+}
+
+// --- Machines ---
+
+func (s *Server) ActivateMachine(ctx context.Context, req *ActivateMachineRequest) (*ActivateMachineResponse, error) {
+	if req.GetLicenseKey() == "" || req.GetFingerprint() == "" {
+		return nil, status.Error(codes.InvalidArgument, "license_key and fingerprint are required")
+	}
+	if err := s.client.ActivateMachine(ctx, req.GetLicenseKey(), req.GetFingerprint(), req.GetName(), req.GetPlatform()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &ActivateMachineResponse{}, nil
+}
+
+func (s *Server) DeactivateMachine(ctx context.Context, req *DeactivateMachineRequest) (*DeactivateMachineResponse, error) {
+	if req.GetLicenseKey() == "" || req.GetFingerprint() == "" {
+		return nil, status.Error(codes.InvalidArgument, "license_key and fingerprint are required")
+	}
+	found, err := s.client.DeactivateMachine(ctx, req.GetLicenseKey(), req.GetFingerprint())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &DeactivateMachineResponse{Found: found}, nil
+}
+
+func (s *Server) ListMachines(ctx context.Context, req *ListMachinesRequest) (*ListMachinesResponse, error) {
+	if req.GetLicenseId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "license_id is required")
+	}
+	items, err := s.client.ListMachines(ctx, req.GetLicenseId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	out := make([]*Machine, 0, len(items))
+	for _, m := range items {
+		out = append(out, &Machine{
+			Id:          m.ID,
+			LicenseId:   m.LicenseId,
+			Fingerprint: m.Fingerprint,
+			Platform:    m.Platform,
+			Name:        m.Name,
+		})
+	}
+	return &ListMachinesResponse{Machines: out}, nil
+}
+
+// --- Validation ---
+
+func (s *Server) Validate(ctx context.Context, req *ValidateRequest) (*ValidateResponse, error) {
+	if req.GetLicenseKey() == "" {
+		return nil, status.Error(codes.InvalidArgument, "license_key is required")
+	}
+	v, err := s.client.Validate(ctx, req.GetLicenseKey(), req.GetFingerprint())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &ValidateResponse{
+		Key:         v.Key,
+		Expiry:      v.Expiry,
+		Status:      v.Status,
+		Valid:       v.Valid,
+		Code:        string(v.Code),
+		Detail:      v.Detail,
+		Timestamp:   v.Timestamp,
+		Fingerprint: v.Fingerprint,
+	}, nil
+}
+
+// --- helpers ---
+
+// toStatus maps client errors onto gRPC statuses. Messages are built from
+// the status code and JSON:API error codes only, so upstream response bodies
+// and license keys never reach the caller.
+func toStatus(err error) error {
+	var herr *keygen.HTTPError
+	switch {
+	case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	case errors.Is(err, keygen.ErrNotFound):
+		return status.Error(codes.NotFound, statusMessage(err, keygen.ErrNotFound))
+	case errors.Is(err, keygen.ErrUnauthorized):
+		return status.Error(codes.Unauthenticated, statusMessage(err, keygen.ErrUnauthorized))
+	case errors.Is(err, keygen.ErrMachineLimitExceeded):
+		return status.Error(codes.ResourceExhausted, statusMessage(err, keygen.ErrMachineLimitExceeded))
+	case errors.Is(err, keygen.ErrRateLimited):
+		return status.Error(codes.ResourceExhausted, statusMessage(err, keygen.ErrRateLimited))
+	case errors.Is(err, keygen.ErrLicenseExpired):
+		return status.Error(codes.FailedPrecondition, statusMessage(err, keygen.ErrLicenseExpired))
+	case errors.Is(err, keygen.ErrCircuitOpen):
+		return status.Error(codes.Unavailable, keygen.ErrCircuitOpen.Error())
+	case errors.As(err, &herr):
+		if herr.StatusCode >= 500 {
+			return status.Error(codes.Unavailable, statusMessage(err, nil))
+		}
+		return status.Error(codes.Unknown, statusMessage(err, nil))
+	}
+	var uerr *url.Error
+	if errors.As(err, &uerr) {
+		return status.Error(codes.Unavailable, "keygen: API unreachable")
+	}
+	return status.Error(codes.Unknown, "keygen: request failed")
+}
+
+// statusMessage describes err by its HTTP status and API error codes, or by
+// sentinel when err is not an *keygen.HTTPError.
+func statusMessage(err, sentinel error) string {
+	var herr *keygen.HTTPError
+	if !errors.As(err, &herr) {
+		if sentinel != nil {
+			return sentinel.Error()
+		}
+		return "keygen: request failed"
+	}
+	msg := fmt.Sprintf("keygen: HTTP %d", herr.StatusCode)
+	if codes := herr.Codes(); len(codes) > 0 {
+		msg += " " + strings.Join(codes, ", ")
+	}
+	return msg
+}
+
+// encodeMetadata flattens metadata into strings, JSON-encoding non-string values.
+func encodeMetadata(in map[string]any) (map[string]string, error) {
+	if len(in) == 0 {
+		return nil, nil
+	}
+	out := make(map[string]string, len(in))
+	for k, v := range in {
+		if s, ok := v.(string); ok {
+			out[k] = s
+			continue
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		out[k] = string(b)
+	}
+	return out, nil
+}
@@ -0,0 +1,184 @@
+package keygengrpc
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/dappnode/keygen-client/keygen"
+)
+
+// startServer serves a Server backed by an API at apiURL over bufconn,
+// behind UnaryInterceptor with a single "svc" caller.
+func startServer(t *testing.T, apiURL string, audit AuditFunc) KeygenServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		UnaryInterceptor(BearerTokens(map[string]string{"svc": "secret"}), audit)))
+	Register(s, keygen.New("acct", "token", keygen.WithBaseURL(apiURL)))
+	go func() { _ = s.Serve(lis) }()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return NewKeygenServiceClient(conn)
+}
+
+func authed(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func TestServer_RPCs(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "POST /accounts/acct/licenses/actions/validate-key":
+			_, _ = w.Write([]byte(`{"data":{"id":"lic-1","attributes":{"key":"KEY","status":"ACTIVE"}},"meta":{"valid":true,"code":"VALID"}}`))
+		case "POST /accounts/acct/machines":
+			_, _ = w.Write([]byte(`{"data":{"id":"m1","type":"machines","attributes":{"fingerprint":"fp"}}}`))
+		case "GET /accounts/acct/machines":
+			_, _ = w.Write([]byte(`{"data":[{"id":"m1","attributes":{"fingerprint":"fp","name":"box"},"relationships":{"license":{"data":{"id":"lic-1"}}}}],"links":{}}`))
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	var (
+		mu   sync.Mutex
+		recs []AuditRecord
+	)
+	client := startServer(t, srv.URL, func(_ context.Context, rec AuditRecord) {
+		mu.Lock()
+		defer mu.Unlock()
+		recs = append(recs, rec)
+	})
+	ctx := authed("secret")
+
+	v, err := client.Validate(ctx, &ValidateRequest{LicenseKey: "KEY", Fingerprint: "fp"})
+	if err != nil || !v.GetValid() || v.GetCode() != "VALID" {
+		t.Fatalf("Validate = %v, %v", v, err)
+	}
+	if _, err := client.ActivateMachine(ctx, &ActivateMachineRequest{LicenseKey: "KEY", Fingerprint: "fp"}); err != nil {
+		t.Fatalf("ActivateMachine: %v", err)
+	}
+	ms, err := client.ListMachines(ctx, &ListMachinesRequest{LicenseId: "lic-1"})
+	if err != nil || len(ms.GetMachines()) != 1 || ms.GetMachines()[0].GetFingerprint() != "fp" {
+		t.Fatalf("ListMachines = %v, %v", ms, err)
+	}
+	if _, err := client.ListMachines(ctx, &ListMachinesRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("ListMachines without id = %v, want InvalidArgument", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(recs) != 4 {
+		t.Fatalf("audit records = %+v", recs)
+	}
+	if r := recs[0]; r.Caller != "svc" || r.Method != KeygenService_Validate_FullMethodName || r.Code != codes.OK {
+		t.Fatalf("audit record = %+v", r)
+	}
+	if recs[3].Code != codes.InvalidArgument {
+		t.Fatalf("audit code = %v", recs[3].Code)
+	}
+}
+
+func TestServer_RejectsUnauthenticated(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unauthenticated call reached the API: %s %s", r.Method, r.URL.Path)
+	}))
+	defer srv.Close()
+
+	var rejected []AuditRecord
+	client := startServer(t, srv.URL, func(_ context.Context, rec AuditRecord) { rejected = append(rejected, rec) })
+	for _, ctx := range []context.Context{context.Background(), authed("wrong")} {
+		_, err := client.DeleteLicense(ctx, &DeleteLicenseRequest{LicenseId: "lic-1"})
+		if status.Code(err) != codes.Unauthenticated {
+			t.Fatalf("DeleteLicense = %v, want Unauthenticated", err)
+		}
+	}
+	if len(rejected) != 2 || rejected[0].Caller != "" || rejected[0].Code != codes.Unauthenticated {
+		t.Fatalf("audit records = %+v", rejected)
+	}
+}
+
+func TestServer_ErrorMapping(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "DELETE /accounts/acct/licenses/gone":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":[{"title":"Not found","detail":"secret-detail","code":"NOT_FOUND"}]}`))
+		case "DELETE /accounts/acct/licenses/denied":
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":[{"title":"Forbidden","detail":"secret-detail"}]}`))
+		case "POST /accounts/acct/licenses/actions/validate-key":
+			_, _ = w.Write([]byte(`{"data":{"id":"lic-1"}}`))
+		case "POST /accounts/acct/machines":
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte(`{"errors":[{"title":"Unprocessable","detail":"secret-detail","code":"MACHINE_LIMIT_EXCEEDED"}]}`))
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	client := startServer(t, srv.URL, nil)
+	ctx := authed("secret")
+	cases := []struct {
+		name string
+		call func() error
+		code codes.Code
+		msg  string
+	}{
+		{"not found", func() error {
+			_, err := client.DeleteLicense(ctx, &DeleteLicenseRequest{LicenseId: "gone"})
+			return err
+		}, codes.NotFound, "keygen: HTTP 404 NOT_FOUND"},
+		{"forbidden", func() error {
+			_, err := client.DeleteLicense(ctx, &DeleteLicenseRequest{LicenseId: "denied"})
+			return err
+		}, codes.Unauthenticated, "keygen: HTTP 403"},
+		{"machine limit", func() error {
+			_, err := client.ActivateMachine(ctx, &ActivateMachineRequest{LicenseKey: "KEY", Fingerprint: "fp"})
+			return err
+		}, codes.ResourceExhausted, "keygen: HTTP 422 MACHINE_LIMIT_EXCEEDED"},
+	}
+	for _, tc := range cases {
+		st, _ := status.FromError(tc.call())
+		if st.Code() != tc.code || st.Message() != tc.msg {
+			t.Errorf("%s: status = %v %q, want %v %q", tc.name, st.Code(), st.Message(), tc.code, tc.msg)
+		}
+		if strings.Contains(st.Message(), "secret-detail") {
+			t.Errorf("%s: status leaks the response body: %q", tc.name, st.Message())
+		}
+	}
+}
+
+func TestBearerTokens(t *testing.T) {
+	auth := BearerTokens(map[string]string{"a": "tok-a", "b": "tok-b"})
+	md := metadata.Pairs("authorization", "Bearer tok-b")
+	if caller, err := auth(metadata.NewIncomingContext(context.Background(), md), "/m"); err != nil || caller != "b" {
+		t.Fatalf("auth = %q, %v", caller, err)
+	}
+	md = metadata.Pairs("authorization", "Basic tok-b")
+	if _, err := auth(metadata.NewIncomingContext(context.Background(), md), "/m"); err != ErrUnauthenticated {
+		t.Fatalf("auth with wrong scheme = %v", err)
+	}
+}